@@ -11,9 +11,20 @@ CREATE TABLE IF NOT EXISTS houses (
     images text[] NOT NULL DEFAULT '{}'::text[],
     check_in_from text NOT NULL DEFAULT '14:00',
     check_out_until text NOT NULL DEFAULT '11:00',
+    directions text NOT NULL DEFAULT '',
+    calendar_token text NOT NULL DEFAULT replace(gen_random_uuid()::text, '-', ''),
     created_at timestamptz NOT NULL DEFAULT now(),
     updated_at timestamptz NOT NULL DEFAULT now()
 );
+-- Секрет для подписки внешних площадок на iCal-календарь дома
+ALTER TABLE houses
+    ADD COLUMN IF NOT EXISTS calendar_token text NOT NULL DEFAULT replace(gen_random_uuid()::text, '-', '');
+-- Как добраться: отправляется гостю утром в день заезда
+ALTER TABLE houses
+    ADD COLUMN IF NOT EXISTS directions text NOT NULL DEFAULT '';
+-- Обложка дома для карточек бота; пустая — берётся первая картинка галереи
+ALTER TABLE houses
+    ADD COLUMN IF NOT EXISTS cover_image text NOT NULL DEFAULT '';
 ------------------------------------------------------------
 -- Гости
 CREATE TABLE IF NOT EXISTS guests (
@@ -22,8 +33,14 @@ CREATE TABLE IF NOT EXISTS guests (
     email text NOT NULL,
     phone text,
     tg_user_id   bigint,
+    notify_channels text[] NOT NULL DEFAULT '{}'::text[], -- каналы уведомлений в порядке приоритета
     created_at timestamptz NOT NULL DEFAULT now()
 );
+ALTER TABLE guests
+    ADD COLUMN IF NOT EXISTS notify_channels text[] NOT NULL DEFAULT '{}'::text[];
+-- Язык гостя из Telegram (language_code), пустой — язык по умолчанию
+ALTER TABLE guests
+    ADD COLUMN IF NOT EXISTS locale text NOT NULL DEFAULT '';
 ------------------------------------------------------------
 -- Статусы
 DO $$
@@ -33,6 +50,8 @@ DO $$
     EXCEPTION
         WHEN duplicate_object THEN NULL;
 END $$;
+-- Гость не приехал (отмечает администратор)
+ALTER TYPE reservation_status ADD VALUE IF NOT EXISTS 'no_show';
 ------------------------------------------------------------
 -- Брони
 CREATE TABLE IF NOT EXISTS reservations (
@@ -106,18 +125,43 @@ CREATE TABLE IF NOT EXISTS reservation_extras (
     PRIMARY KEY (reservation_uuid, extra_id)
 );
 ------------------------------------------------------------
--- Блокировка дат (ремонт, частное пользование)
+-- Внешние календари (Avito/Booking/Airbnb), импортируемые в блокировки
+CREATE TABLE IF NOT EXISTS calendar_sources (
+    id serial PRIMARY KEY,
+    house_id smallint NOT NULL REFERENCES houses ON DELETE CASCADE,
+    name text NOT NULL,
+    url text NOT NULL,
+    last_synced_at timestamptz,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    UNIQUE (house_id, url)
+);
+------------------------------------------------------------
+-- Блокировка дат (ремонт, частное пользование, брони с других площадок)
 CREATE TABLE IF NOT EXISTS blackouts (
     id serial PRIMARY KEY,
     house_id smallint REFERENCES houses ON DELETE CASCADE,
     period daterange NOT NULL,
     reason text,
-    CONSTRAINT no_overlap_blackout
-    EXCLUDE USING gist (
-        house_id WITH =,
-        period WITH &&
-    )
+    source_id int REFERENCES calendar_sources ON DELETE CASCADE,
+    external_uid text
 );
+ALTER TABLE blackouts
+    ADD COLUMN IF NOT EXISTS source_id int REFERENCES calendar_sources ON DELETE CASCADE,
+    ADD COLUMN IF NOT EXISTS external_uid text;
+CREATE UNIQUE INDEX IF NOT EXISTS blackouts_source_uid_idx
+    ON blackouts (source_id, external_uid);
+-- Импортированные события могут пересекаться между собой, запрет пересечений только для ручных блокировок
+ALTER TABLE blackouts DROP CONSTRAINT IF EXISTS no_overlap_blackout;
+DO $$
+    BEGIN
+        ALTER TABLE blackouts ADD CONSTRAINT no_overlap_manual_blackout
+            EXCLUDE USING gist (
+                house_id WITH =,
+                period WITH &&
+            ) WHERE (source_id IS NULL);
+    EXCEPTION
+        WHEN duplicate_object OR duplicate_table THEN NULL;
+END $$;
 ------------------------------------------------------------
 -- Верификация пользователя
 CREATE TABLE IF NOT EXISTS verifications (
@@ -130,12 +174,277 @@ CREATE TABLE IF NOT EXISTS verifications (
     status       text       NOT NULL,
     created_at   timestamptz DEFAULT now(),
     verified_at  timestamptz,
-    expires_at   timestamptz NOT NULL
+    expires_at   timestamptz NOT NULL,
+    notify_channels text[] NOT NULL DEFAULT '{}'::text[]
 );
+ALTER TABLE verifications
+    ADD COLUMN IF NOT EXISTS notify_channels text[] NOT NULL DEFAULT '{}'::text[];
 CREATE INDEX IF NOT EXISTS idx_verifications_code
     ON verifications(code);
 ------------------------------------------------------------
+-- Журнал доставки уведомлений по каналам
+CREATE TABLE IF NOT EXISTS notification_deliveries (
+    id bigserial PRIMARY KEY,
+    kind text NOT NULL,
+    channel text NOT NULL,
+    recipient text NOT NULL,
+    guest_uuid uuid REFERENCES guests ON DELETE SET NULL,
+    reference text,
+    status text NOT NULL,
+    error text,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS notification_deliveries_reference_idx
+    ON notification_deliveries (reference);
+------------------------------------------------------------
+-- Очередь уведомлений (outbox): пишется в одной транзакции с бронью, разбирается воркером
+CREATE TABLE IF NOT EXISTS notification_outbox (
+    id bigserial PRIMARY KEY,
+    kind text NOT NULL,
+    payload jsonb NOT NULL,
+    status text NOT NULL DEFAULT 'pending', -- pending / sent / failed
+    attempts int NOT NULL DEFAULT 0,
+    last_error text,
+    next_attempt_at timestamptz NOT NULL DEFAULT now(),
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS notification_outbox_pending_idx
+    ON notification_outbox (next_attempt_at)
+    WHERE status = 'pending';
+------------------------------------------------------------
+-- Тексты сообщений, изменённые администратором (по умолчанию берутся из файлов)
+CREATE TABLE IF NOT EXISTS message_templates (
+    key text NOT NULL,
+    locale text NOT NULL,
+    body text NOT NULL,
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (key, locale)
+);
+------------------------------------------------------------
+-- Этапы напоминаний, уже отправленные по брони (skipped — пропущен, т.к. наступил более поздний)
+CREATE TABLE IF NOT EXISTS reservation_reminders (
+    reservation_uuid uuid NOT NULL REFERENCES reservations ON DELETE CASCADE,
+    stage text NOT NULL,
+    status text NOT NULL, -- sent / skipped
+    created_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (reservation_uuid, stage)
+);
+------------------------------------------------------------
+-- Администраторы, которым доступны команды бота (/today, /reservation, /block ...)
+CREATE TABLE IF NOT EXISTS bot_admins (
+    tg_user_id bigint PRIMARY KEY,
+    name text NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+------------------------------------------------------------
+-- Отзывы гостей после выезда
+-- Когда гостю отправлен запрос отзыва (чтобы не спрашивать повторно)
+ALTER TABLE reservations
+    ADD COLUMN IF NOT EXISTS feedback_requested_at timestamptz;
+CREATE TABLE IF NOT EXISTS reviews (
+    id serial PRIMARY KEY,
+    reservation_uuid uuid NOT NULL UNIQUE REFERENCES reservations ON DELETE CASCADE,
+    house_id smallint NOT NULL REFERENCES houses ON DELETE CASCADE,
+    guest_uuid uuid REFERENCES guests ON DELETE SET NULL,
+    rating smallint NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    comment text NOT NULL DEFAULT '',
+    awaiting_comment boolean NOT NULL DEFAULT true, -- бот ждёт от гостя текст отзыва
+    status text NOT NULL DEFAULT 'pending', -- pending / approved / rejected
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    moderated_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS reviews_house_approved_idx
+    ON reviews (house_id)
+    WHERE status = 'approved';
+------------------------------------------------------------
 CREATE INDEX IF NOT EXISTS reservations_active_idx
     ON reservations
     USING gist (house_id, stay);
-------------------------------------------------------------
\ No newline at end of file
+------------------------------------------------------------
+-- Бронирование через Telegram‑бота: текущий шаг и черновик брони по чату
+CREATE TABLE IF NOT EXISTS booking_sessions (
+    chat_id bigint PRIMARY KEY,
+    step text NOT NULL,
+    data jsonb NOT NULL DEFAULT '{}'::jsonb,
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+------------------------------------------------------------
+-- Защита подтверждения личности от перебора кодов
+ALTER TABLE verifications
+    ADD COLUMN IF NOT EXISTS attempts int NOT NULL DEFAULT 0; -- сколько раз код присылали заблокированные чаты
+UPDATE verifications v
+SET status = 'expired'
+WHERE status = 'pending'
+  AND (expires_at <= now() OR EXISTS (
+        SELECT 1 FROM verifications n
+        WHERE n.code = v.code AND n.status = 'pending' AND n.created_at > v.created_at));
+CREATE UNIQUE INDEX IF NOT EXISTS verifications_pending_code_uidx
+    ON verifications (code)
+    WHERE status = 'pending';
+CREATE TABLE IF NOT EXISTS verification_lockouts (
+    tg_user_id bigint PRIMARY KEY,
+    failures int NOT NULL DEFAULT 0, -- неверные коды с последней блокировки
+    lockouts int NOT NULL DEFAULT 0, -- число блокировок, от него растёт их длительность
+    locked_until timestamptz,
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+------------------------------------------------------------
+-- Подтверждение личности по ссылке t.me/<бот>?start=<токен>, хранится только SHA-256 токена
+ALTER TABLE verifications
+    ADD COLUMN IF NOT EXISTS token_hash text;
+ALTER TABLE verifications
+    ALTER COLUMN code DROP NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS verifications_token_hash_uidx
+    ON verifications (token_hash);
+------------------------------------------------------------
+-- Очистка подтверждений: просроченные ожидающие и старые завершённые
+CREATE INDEX IF NOT EXISTS verifications_pending_expires_idx
+    ON verifications (expires_at)
+    WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS verifications_created_at_idx
+    ON verifications (created_at);
+------------------------------------------------------------
+-- Один гость — один телефон: телефоны в E.164, дубликаты сливаются в самую раннюю запись
+UPDATE guests
+SET phone = CASE
+        WHEN regexp_replace(phone, '\D', '', 'g') ~ '^[78]\d{10}$'
+            THEN '+7' || right(regexp_replace(phone, '\D', '', 'g'), 10)
+        WHEN regexp_replace(phone, '\D', '', 'g') ~ '^\d{10}$'
+            THEN '+7' || regexp_replace(phone, '\D', '', 'g')
+        ELSE '+' || regexp_replace(phone, '\D', '', 'g')
+    END
+WHERE phone IS NOT NULL AND phone !~ '^\+\d{8,15}$' AND phone ~ '\d';
+UPDATE guests
+SET phone = NULL
+WHERE phone IS NOT NULL AND phone !~ '\d';
+UPDATE guests
+SET email = lower(trim(email)), name = trim(name)
+WHERE email <> lower(trim(email)) OR name <> trim(name);
+DROP TABLE IF EXISTS guest_duplicates;
+CREATE TEMP TABLE guest_duplicates AS
+SELECT uuid, keep_uuid
+FROM (
+    SELECT uuid, first_value(uuid) OVER (PARTITION BY phone ORDER BY created_at, uuid) AS keep_uuid
+    FROM guests
+    WHERE phone IS NOT NULL
+) g
+WHERE uuid <> keep_uuid;
+-- Telegram и язык берутся у последней подтверждённой записи
+UPDATE guests k
+SET tg_user_id = coalesce(l.tg_user_id, k.tg_user_id),
+    locale = CASE WHEN l.locale <> '' THEN l.locale ELSE k.locale END,
+    notify_channels = CASE WHEN cardinality(l.notify_channels) > 0 THEN l.notify_channels ELSE k.notify_channels END
+FROM (
+    SELECT DISTINCT ON (d.keep_uuid) d.keep_uuid, g.tg_user_id, g.locale, g.notify_channels
+    FROM guest_duplicates d
+    JOIN guests g ON g.uuid = d.uuid
+    ORDER BY d.keep_uuid, g.created_at DESC
+) l
+WHERE k.uuid = l.keep_uuid;
+UPDATE reservations r SET guest_uuid = d.keep_uuid FROM guest_duplicates d WHERE r.guest_uuid = d.uuid;
+UPDATE reviews r SET guest_uuid = d.keep_uuid FROM guest_duplicates d WHERE r.guest_uuid = d.uuid;
+UPDATE notification_deliveries n SET guest_uuid = d.keep_uuid FROM guest_duplicates d WHERE n.guest_uuid = d.uuid;
+DELETE FROM guests g USING guest_duplicates d WHERE g.uuid = d.uuid;
+DROP TABLE guest_duplicates;
+-- Аккаунт Telegram остаётся только у последнего гостя, который его подтвердил
+UPDATE guests g
+SET tg_user_id = NULL
+WHERE tg_user_id IS NOT NULL
+  AND EXISTS (
+        SELECT 1 FROM guests n
+        WHERE n.tg_user_id = g.tg_user_id
+          AND (n.created_at, n.uuid) > (g.created_at, g.uuid));
+CREATE UNIQUE INDEX IF NOT EXISTS guests_phone_uidx
+    ON guests (phone)
+    WHERE phone IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS guests_tg_user_id_uidx
+    ON guests (tg_user_id)
+    WHERE tg_user_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS guests_email_idx
+    ON guests (email);
+------------------------------------------------------------
+-- Обезличивание гостей по запросу (152‑ФЗ/GDPR) и после долгого отсутствия: брони остаются для учёта
+ALTER TABLE guests
+    ADD COLUMN IF NOT EXISTS anonymized_at timestamptz;
+------------------------------------------------------------
+-- Программа лояльности: скидка уровня гостя, применённая к брони, и выборка завершённых проживаний
+ALTER TABLE reservations
+    ADD COLUMN IF NOT EXISTS loyalty_discount smallint NOT NULL DEFAULT 0; -- процент
+CREATE INDEX IF NOT EXISTS reservations_guest_checked_out_idx
+    ON reservations (guest_uuid)
+    WHERE status = 'checked_out';
+------------------------------------------------------------
+-- Заявки на мероприятия: хранятся, чтобы не потерять лид, если сообщение в чате админов пропало
+CREATE TABLE IF NOT EXISTS event_applications (
+    id serial PRIMARY KEY,
+    name text NOT NULL,
+    phone text NOT NULL,
+    check_in text NOT NULL DEFAULT '', -- дата из формы как есть
+    guests_count int NOT NULL DEFAULT 0,
+    status text NOT NULL DEFAULT 'new', -- new / contacted / quoted / won / lost
+    notes text NOT NULL DEFAULT '',
+    manager text NOT NULL DEFAULT '', -- кто из менеджеров ведёт заявку
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS event_applications_status_idx
+    ON event_applications (status, created_at DESC);
+------------------------------------------------------------
+-- Мероприятия: одна бронь на несколько домов и бань. Каждый дом получает обычную бронь со ссылкой
+-- на мероприятие, поэтому пересечения по-прежнему запрещает no_overlap; цена и предоплата — на мероприятии
+CREATE TABLE IF NOT EXISTS event_bookings (
+    uuid uuid PRIMARY KEY,
+    application_id int REFERENCES event_applications ON DELETE SET NULL,
+    title text NOT NULL,
+    contact_name text NOT NULL DEFAULT '',
+    contact_phone text NOT NULL DEFAULT '',
+    stay daterange NOT NULL,
+    guests_count int NOT NULL CHECK (guests_count > 0),
+    house_ids int[] NOT NULL, -- остаются после отмены, когда брони домов уже удалены
+    bathhouse_ids int[] NOT NULL DEFAULT '{}'::int[],
+    total_price numeric(10,2) NOT NULL CHECK (total_price >= 0),
+    deposit numeric(10,2) NOT NULL DEFAULT 0 CHECK (deposit >= 0),
+    status text NOT NULL DEFAULT 'confirmed', -- confirmed / cancelled
+    notes text NOT NULL DEFAULT '',
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+ALTER TABLE reservations
+    ADD COLUMN IF NOT EXISTS event_uuid uuid REFERENCES event_bookings ON DELETE CASCADE;
+CREATE INDEX IF NOT EXISTS reservations_event_uuid_idx
+    ON reservations (event_uuid)
+    WHERE event_uuid IS NOT NULL;
+------------------------------------------------------------
+-- Загруженные изображения домов, услуг и бань. images владельца повторяет ссылки на самые
+-- крупные варианты в порядке position, поэтому старые клиенты продолжают работать
+CREATE TABLE IF NOT EXISTS media (
+    id serial PRIMARY KEY,
+    owner_type text NOT NULL, -- house / extra / bathhouse
+    owner_id int NOT NULL,
+    position int NOT NULL,
+    content_type text NOT NULL,
+    size int NOT NULL,
+    width int NOT NULL,
+    height int NOT NULL,
+    storage_key text NOT NULL, -- ключ оригинала в хранилище
+    url text NOT NULL,
+    variants jsonb NOT NULL DEFAULT '[]'::jsonb, -- уменьшенные копии от меньшей к большей
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS media_owner_idx
+    ON media (owner_type, owner_id, position);
+------------------------------------------------------------
+-- Удобства домов и теги для фильтров поиска
+ALTER TABLE houses
+    ADD COLUMN IF NOT EXISTS sauna boolean NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS pets_allowed boolean NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS kitchen boolean NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS fireplace boolean NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS beds jsonb NOT NULL DEFAULT '[]'::jsonb, -- [{"kind": "double", "count": 1}]
+    ADD COLUMN IF NOT EXISTS area int NOT NULL DEFAULT 0 CHECK (area >= 0), -- м², 0 — не указана
+    ADD COLUMN IF NOT EXISTS tags text[] NOT NULL DEFAULT '{}'::text[]; -- в нижнем регистре
+CREATE INDEX IF NOT EXISTS houses_tags_idx
+    ON houses USING gin (tags);
+------------------------------------------------------------
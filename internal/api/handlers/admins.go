@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/calyrexx/QuietGrooveBackend/internal/api"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"log/slog"
+	"net/http"
+)
+
+type IAdminsController interface {
+	GetAll(ctx context.Context) ([]BotAdmin, error)
+	Add(ctx context.Context, admin BotAdmin) error
+	Delete(ctx context.Context, tgID int64) error
+}
+
+type AdminsDependencies struct {
+	Controller IAdminsController
+	Logger     *slog.Logger
+}
+
+// Admins manages the Telegram users allowed to run the bot's admin commands.
+type Admins struct {
+	controller IAdminsController
+	logger     *slog.Logger
+}
+
+func NewAdmins(dep AdminsDependencies) (*Admins, error) {
+	if dep.Logger == nil {
+		return nil, errorspkg.NewErrConstructorDependencies("NewAdmins", "Logger", "nil")
+	}
+	if dep.Controller == nil {
+		return nil, errorspkg.NewErrConstructorDependencies("NewAdmins", "Controller", "nil")
+	}
+
+	logger := dep.Logger.With("Handler", "Admins")
+
+	return &Admins{
+		controller: dep.Controller,
+		logger:     logger,
+	}, nil
+}
+
+func (h *Admins) GetAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	admins, err := h.controller.GetAll(ctx)
+	if err != nil {
+		h.logger.Error(err.Error(), "method", "GetAll")
+		api.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	api.WriteJSON(w, http.StatusOK, admins)
+}
+
+func (h *Admins) Add(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req BotAdmin
+	if err := api.ReadJSON(r, &req); err != nil {
+		api.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.controller.Add(ctx, req); err != nil {
+		if errors.Is(err, errorspkg.ErrInvalidTgID) {
+			api.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+		h.logger.Error(err.Error(), "method", "Add")
+		api.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	api.WriteJSON(w, http.StatusCreated, nil)
+}
+
+func (h *Admins) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := api.URLParamInt(r, "id")
+	if err != nil {
+		api.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err = h.controller.Delete(ctx, int64(id)); err != nil {
+		var notFound *errorspkg.ErrRepoNotFound
+		if errors.As(err, &notFound) {
+			api.WriteError(w, http.StatusNotFound, err)
+			return
+		}
+		h.logger.Error(err.Error(), "method", "Delete")
+		api.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	api.WriteJSON(w, http.StatusOK, nil)
+}
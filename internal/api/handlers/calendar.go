@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/calyrexx/QuietGrooveBackend/internal/api"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"log/slog"
+	"net/http"
+)
+
+type ICalendarController interface {
+	Export(ctx context.Context, houseID int, token string) ([]byte, error)
+	RegenerateToken(ctx context.Context, houseID int) (string, error)
+	GetSources(ctx context.Context, houseID int) ([]CalendarSource, error)
+	AddSource(ctx context.Context, source CalendarSource) (CalendarSource, error)
+	DeleteSource(ctx context.Context, houseID, sourceID int) error
+}
+
+type CalendarDependencies struct {
+	Controller ICalendarController
+	Logger     *slog.Logger
+}
+
+type Calendar struct {
+	controller ICalendarController
+	logger     *slog.Logger
+}
+
+func NewCalendar(dep CalendarDependencies) (*Calendar, error) {
+	if dep.Logger == nil {
+		return nil, errorspkg.NewErrConstructorDependencies("NewCalendar", "Logger", "nil")
+	}
+	if dep.Controller == nil {
+		return nil, errorspkg.NewErrConstructorDependencies("NewCalendar", "Controller", "nil")
+	}
+
+	logger := dep.Logger.With("Handler", "Calendar")
+
+	return &Calendar{
+		controller: dep.Controller,
+		logger:     logger,
+	}, nil
+}
+
+func (h *Calendar) Export(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := api.URLParamInt(r, "id")
+	if err != nil {
+		api.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	feed, err := h.controller.Export(ctx, id, r.URL.Query().Get("token"))
+	if err != nil {
+		var notFound *errorspkg.ErrRepoNotFound
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, errorspkg.ErrInvalidCalendarToken):
+			status = http.StatusForbidden
+		case errors.As(err, &notFound):
+			status = http.StatusNotFound
+		}
+		h.logger.Error(err.Error(), "method", "Export")
+		api.WriteError(w, status, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write(feed); err != nil {
+		h.logger.Error(err.Error(), "method", "Export")
+	}
+}
+
+func (h *Calendar) RegenerateToken(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := api.URLParamInt(r, "id")
+	if err != nil {
+		api.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	token, err := h.controller.RegenerateToken(ctx, id)
+	if err != nil {
+		var notFound *errorspkg.ErrRepoNotFound
+		status := http.StatusInternalServerError
+		if errors.As(err, &notFound) {
+			status = http.StatusNotFound
+		}
+		h.logger.Error(err.Error(), "method", "RegenerateToken")
+		api.WriteError(w, status, err)
+		return
+	}
+
+	api.WriteJSON(w, http.StatusOK, map[string]string{"token": token})
+}
+
+func (h *Calendar) GetSources(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := api.URLParamInt(r, "id")
+	if err != nil {
+		api.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	sources, err := h.controller.GetSources(ctx, id)
+	if err != nil {
+		h.logger.Error(err.Error(), "method", "GetSources")
+		api.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	api.WriteJSON(w, http.StatusOK, sources)
+}
+
+func (h *Calendar) AddSource(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := api.URLParamInt(r, "id")
+	if err != nil {
+		api.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req CalendarSource
+	if err = api.ReadJSON(r, &req); err != nil {
+		api.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+	if req.Name == "" || req.URL == "" {
+		api.WriteError(w, http.StatusBadRequest, errors.New("name and url are required"))
+		return
+	}
+
+	req.HouseID = id
+
+	source, err := h.controller.AddSource(ctx, req)
+	if err != nil {
+		var notFound *errorspkg.ErrRepoNotFound
+		status := http.StatusInternalServerError
+		if errors.As(err, &notFound) {
+			status = http.StatusNotFound
+		}
+		h.logger.Error(err.Error(), "method", "AddSource")
+		api.WriteError(w, status, err)
+		return
+	}
+
+	api.WriteJSON(w, http.StatusCreated, source)
+}
+
+func (h *Calendar) DeleteSource(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := api.URLParamInt(r, "id")
+	if err != nil {
+		api.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	sourceID, err := api.URLParamInt(r, "sourceId")
+	if err != nil {
+		api.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err = h.controller.DeleteSource(ctx, id, sourceID); err != nil {
+		var notFound *errorspkg.ErrRepoNotFound
+		status := http.StatusInternalServerError
+		if errors.As(err, &notFound) {
+			status = http.StatusNotFound
+		}
+		h.logger.Error(err.Error(), "method", "DeleteSource")
+		api.WriteError(w, status, err)
+		return
+	}
+
+	api.WriteJSON(w, http.StatusOK, nil)
+}
@@ -2,14 +2,24 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"github.com/calyrexx/QuietGrooveBackend/internal/api"
 	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
 	"log/slog"
 	"net/http"
+	"time"
 )
 
 type IEventsController interface {
 	NewApplication(ctx context.Context, req EventsNewApplication) error
+	Applications(ctx context.Context, status string) ([]EventApplication, error)
+	UpdateApplication(ctx context.Context, id int, req EventApplicationUpdate) (EventApplication, error)
+	CreateBooking(ctx context.Context, req CreateEventBooking) (EventBooking, error)
+	Bookings(ctx context.Context, from, to string) ([]EventBooking, error)
+	Booking(ctx context.Context, bookingUUID uuid.UUID) (EventBooking, error)
+	CancelBooking(ctx context.Context, bookingUUID uuid.UUID) error
 }
 
 type EventsDependencies struct {
@@ -56,3 +66,163 @@ func (h *Events) NewApplication(w http.ResponseWriter, r *http.Request) {
 
 	api.WriteJSON(w, http.StatusCreated, nil)
 }
+
+// Applications lists event applications, ?status=new|contacted|quoted|won|lost narrows the list.
+func (h *Events) Applications(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	apps, err := h.controller.Applications(ctx, r.URL.Query().Get("status"))
+	if err != nil {
+		if errors.Is(err, errorspkg.ErrInvalidApplicationStatus) {
+			api.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+		h.logger.Error(err.Error(), "method", "Applications")
+		api.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	api.WriteJSON(w, http.StatusOK, apps)
+}
+
+func (h *Events) UpdateApplication(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := api.URLParamInt(r, "id")
+	if err != nil {
+		api.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req EventApplicationUpdate
+	if err = api.ReadJSON(r, &req); err != nil {
+		api.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	app, err := h.controller.UpdateApplication(ctx, id, req)
+	if err != nil {
+		var notFound *errorspkg.ErrRepoNotFound
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, errorspkg.ErrInvalidApplicationStatus):
+			status = http.StatusBadRequest
+		case errors.As(err, &notFound):
+			status = http.StatusNotFound
+		}
+		h.logger.Error(err.Error(), "method", "UpdateApplication")
+		api.WriteError(w, status, err)
+		return
+	}
+
+	api.WriteJSON(w, http.StatusOK, app)
+}
+
+func (h *Events) CreateBooking(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req CreateEventBooking
+	if err := api.ReadJSON(r, &req); err != nil {
+		api.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	booking, err := h.controller.CreateBooking(ctx, req)
+	if err != nil {
+		var (
+			parseErr    *time.ParseError
+			unavailable *errorspkg.ErrHouseUnavailable
+			notFound    *errorspkg.ErrRepoNotFound
+		)
+		status := http.StatusInternalServerError
+		switch {
+		case errors.As(err, &parseErr),
+			errors.Is(err, errorspkg.ErrInvalidPeriod),
+			errors.Is(err, errorspkg.ErrEventWithoutHouses),
+			errors.Is(err, errorspkg.ErrEventBathhouse),
+			errors.Is(err, errorspkg.ErrInvalidGuestsCount),
+			errors.Is(err, errorspkg.ErrInvalidDeposit),
+			errors.Is(err, errorspkg.ErrInvalidPhone),
+			errors.Is(err, errorspkg.ErrUnknownHouse):
+			status = http.StatusBadRequest
+		case errors.As(err, &unavailable), errors.Is(err, errorspkg.ErrBathhouseUnavailable):
+			status = http.StatusConflict
+		case errors.As(err, &notFound):
+			status = http.StatusNotFound
+		default:
+			h.logger.Error(err.Error(), "method", "CreateBooking")
+		}
+		api.WriteError(w, status, err)
+		return
+	}
+
+	api.WriteJSON(w, http.StatusCreated, booking)
+}
+
+// Bookings lists events overlapping ?from=YYYY-MM-DD&to=YYYY-MM-DD, by default the upcoming ones.
+func (h *Events) Bookings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	query := r.URL.Query()
+	bookings, err := h.controller.Bookings(ctx, query.Get("from"), query.Get("to"))
+	if err != nil {
+		var parseErr *time.ParseError
+		if errors.As(err, &parseErr) || errors.Is(err, errorspkg.ErrInvalidPeriod) {
+			api.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+		h.logger.Error(err.Error(), "method", "Bookings")
+		api.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	api.WriteJSON(w, http.StatusOK, bookings)
+}
+
+func (h *Events) Booking(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		api.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	booking, err := h.controller.Booking(ctx, id)
+	if err != nil {
+		var notFound *errorspkg.ErrRepoNotFound
+		if errors.As(err, &notFound) {
+			api.WriteError(w, http.StatusNotFound, err)
+			return
+		}
+		h.logger.Error(err.Error(), "method", "Booking")
+		api.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	api.WriteJSON(w, http.StatusOK, booking)
+}
+
+// CancelBooking cancels the event and frees its houses and bathhouses.
+func (h *Events) CancelBooking(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		api.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err = h.controller.CancelBooking(ctx, id); err != nil {
+		var notFound *errorspkg.ErrRepoNotFound
+		if errors.As(err, &notFound) {
+			api.WriteError(w, http.StatusNotFound, err)
+			return
+		}
+		h.logger.Error(err.Error(), "method", "CancelBooking")
+		api.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	api.WriteJSON(w, http.StatusOK, nil)
+}
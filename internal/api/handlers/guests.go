@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/calyrexx/QuietGrooveBackend/internal/api"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/calyrexx/QuietGrooveBackend/internal/usecases"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"log/slog"
+	"net/http"
+)
+
+type IGuestsController interface {
+	Search(ctx context.Context, query string) ([]GuestProfile, error)
+	Merge(ctx context.Context, targetUUID, sourceUUID uuid.UUID) (GuestMerge, error)
+	Export(ctx context.Context, guestUUID uuid.UUID) (usecases.GuestExport, error)
+	Erase(ctx context.Context, guestUUID uuid.UUID) error
+}
+
+type GuestsDependencies struct {
+	Controller IGuestsController
+	Logger     *slog.Logger
+}
+
+// Guests lets administrators look guests up and merge duplicate accounts.
+type Guests struct {
+	controller IGuestsController
+	logger     *slog.Logger
+}
+
+func NewGuests(dep GuestsDependencies) (*Guests, error) {
+	if dep.Logger == nil {
+		return nil, errorspkg.NewErrConstructorDependencies("NewGuests", "Logger", "nil")
+	}
+	if dep.Controller == nil {
+		return nil, errorspkg.NewErrConstructorDependencies("NewGuests", "Controller", "nil")
+	}
+
+	logger := dep.Logger.With("Handler", "Guests")
+
+	return &Guests{
+		controller: dep.Controller,
+		logger:     logger,
+	}, nil
+}
+
+func (h *Guests) Search(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	guests, err := h.controller.Search(ctx, r.URL.Query().Get("q"))
+	if err != nil {
+		if errors.Is(err, errorspkg.ErrGuestQueryTooShort) {
+			api.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+		h.logger.Error(err.Error(), "method", "Search")
+		api.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	api.WriteJSON(w, http.StatusOK, guests)
+}
+
+// Merge keeps the guest from the path and folds the one from the body into it.
+func (h *Guests) Merge(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	target, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		api.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req GuestMergeRequest
+	if err = api.ReadJSON(r, &req); err != nil {
+		api.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+	source, err := uuid.Parse(req.SourceID)
+	if err != nil {
+		api.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	res, err := h.controller.Merge(ctx, target, source)
+	if err != nil {
+		if errors.Is(err, errorspkg.ErrMergeSameGuest) {
+			api.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+		var notFound *errorspkg.ErrRepoNotFound
+		if errors.As(err, &notFound) {
+			api.WriteError(w, http.StatusNotFound, err)
+			return
+		}
+		h.logger.Error(err.Error(), "method", "Merge")
+		api.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	api.WriteJSON(w, http.StatusOK, res)
+}
+
+// Export returns everything stored about the guest as a downloadable JSON file.
+func (h *Guests) Export(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		api.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	export, err := h.controller.Export(ctx, id)
+	if err != nil {
+		var notFound *errorspkg.ErrRepoNotFound
+		if errors.As(err, &notFound) {
+			api.WriteError(w, http.StatusNotFound, err)
+			return
+		}
+		h.logger.Error(err.Error(), "method", "Export")
+		api.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="guest-%s.json"`, id))
+	api.WriteJSON(w, http.StatusOK, export)
+}
+
+// Erase anonymizes the guest, reservations are kept without personal data.
+func (h *Guests) Erase(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		api.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err = h.controller.Erase(ctx, id); err != nil {
+		if errors.Is(err, errorspkg.ErrGuestHasActiveStays) {
+			api.WriteError(w, http.StatusConflict, err)
+			return
+		}
+		var notFound *errorspkg.ErrRepoNotFound
+		if errors.As(err, &notFound) {
+			api.WriteError(w, http.StatusNotFound, err)
+			return
+		}
+		h.logger.Error(err.Error(), "method", "Erase")
+		api.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	api.WriteJSON(w, http.StatusOK, nil)
+}
@@ -15,6 +15,9 @@ type IHousesControllers interface {
 	Add(ctx context.Context, houses []House) error
 	Update(ctx context.Context, house entities.House) error
 	Delete(ctx context.Context, houseID int) error
+	UpdateAmenities(ctx context.Context, houseID int, amenities HouseAmenities) error
+	UpdateTags(ctx context.Context, houseID int, req HouseTags) error
+	GetTags(ctx context.Context) ([]HouseTag, error)
 }
 
 type HousesDependencies struct {
@@ -66,6 +69,10 @@ func (h *Houses) Add(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.controller.Add(ctx, req); err != nil {
+		if isAmenitiesError(err) {
+			api.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
 		h.logger.Error(err.Error(), "method", "Add")
 		api.WriteError(w, http.StatusInternalServerError, err)
 		return
@@ -125,3 +132,82 @@ func (h *Houses) Delete(w http.ResponseWriter, r *http.Request) {
 
 	api.WriteJSON(w, http.StatusOK, nil)
 }
+
+func (h *Houses) UpdateAmenities(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := api.URLParamInt(r, "id")
+	if err != nil {
+		api.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req HouseAmenities
+	if err = api.ReadJSON(r, &req); err != nil {
+		api.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err = h.controller.UpdateAmenities(ctx, id, req); err != nil {
+		h.writeError(w, err, "UpdateAmenities")
+		return
+	}
+
+	api.WriteJSON(w, http.StatusOK, map[string]string{"message": "amenities updated"})
+}
+
+func (h *Houses) UpdateTags(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := api.URLParamInt(r, "id")
+	if err != nil {
+		api.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req HouseTags
+	if err = api.ReadJSON(r, &req); err != nil {
+		api.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err = h.controller.UpdateTags(ctx, id, req); err != nil {
+		h.writeError(w, err, "UpdateTags")
+		return
+	}
+
+	api.WriteJSON(w, http.StatusOK, map[string]string{"message": "tags updated"})
+}
+
+func (h *Houses) GetTags(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	tags, err := h.controller.GetTags(ctx)
+	if err != nil {
+		h.logger.Error(err.Error(), "method", "GetTags")
+		api.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	api.WriteJSON(w, http.StatusOK, tags)
+}
+
+func (h *Houses) writeError(w http.ResponseWriter, err error, method string) {
+	var notFound *errorspkg.ErrRepoNotFound
+	status := http.StatusInternalServerError
+	switch {
+	case isAmenitiesError(err):
+		status = http.StatusBadRequest
+	case errors.As(err, &notFound):
+		status = http.StatusNotFound
+	default:
+		h.logger.Error(err.Error(), "method", method)
+	}
+	api.WriteError(w, status, err)
+}
+
+func isAmenitiesError(err error) bool {
+	return errors.Is(err, errorspkg.ErrInvalidBed) ||
+		errors.Is(err, errorspkg.ErrInvalidArea) ||
+		errors.Is(err, errorspkg.ErrInvalidTag)
+}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/calyrexx/QuietGrooveBackend/internal/api"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/gorilla/mux"
+	"io"
+	"log/slog"
+	"net/http"
+)
+
+// maxUploadBody caps the request body before the configured file size limit is checked.
+const maxUploadBody = 64 << 20
+
+type IMediaController interface {
+	Upload(ctx context.Context, owner string, ownerID int, data []byte) (MediaFile, error)
+	List(ctx context.Context, owner string, ownerID int) ([]MediaFile, error)
+	Reorder(ctx context.Context, owner string, ownerID int, req MediaOrder) error
+	Delete(ctx context.Context, id int) error
+}
+
+type MediaDependencies struct {
+	Controller IMediaController
+	Logger     *slog.Logger
+}
+
+type Media struct {
+	controller IMediaController
+	logger     *slog.Logger
+}
+
+func NewMedia(dep MediaDependencies) (*Media, error) {
+	if dep.Logger == nil {
+		return nil, errorspkg.NewErrConstructorDependencies("NewMedia", "Logger", "nil")
+	}
+	if dep.Controller == nil {
+		return nil, errorspkg.NewErrConstructorDependencies("NewMedia", "Controller", "nil")
+	}
+
+	logger := dep.Logger.With("Handler", "Media")
+
+	return &Media{
+		controller: dep.Controller,
+		logger:     logger,
+	}, nil
+}
+
+// Upload takes the image from the multipart field "file".
+func (h *Media) Upload(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ownerID, err := api.URLParamInt(r, "id")
+	if err != nil {
+		api.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadBody)
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			api.WriteError(w, http.StatusRequestEntityTooLarge, errorspkg.ErrMediaTooLarge)
+			return
+		}
+		api.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+	defer func() { _ = file.Close() }()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		api.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	media, err := h.controller.Upload(ctx, mux.Vars(r)["owner"], ownerID, data)
+	if err != nil {
+		h.writeError(w, err, "Upload")
+		return
+	}
+
+	api.WriteJSON(w, http.StatusCreated, media)
+}
+
+func (h *Media) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ownerID, err := api.URLParamInt(r, "id")
+	if err != nil {
+		api.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	media, err := h.controller.List(ctx, mux.Vars(r)["owner"], ownerID)
+	if err != nil {
+		h.writeError(w, err, "List")
+		return
+	}
+
+	api.WriteJSON(w, http.StatusOK, media)
+}
+
+func (h *Media) Reorder(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ownerID, err := api.URLParamInt(r, "id")
+	if err != nil {
+		api.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req MediaOrder
+	if err = api.ReadJSON(r, &req); err != nil {
+		api.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err = h.controller.Reorder(ctx, mux.Vars(r)["owner"], ownerID, req); err != nil {
+		h.writeError(w, err, "Reorder")
+		return
+	}
+
+	api.WriteJSON(w, http.StatusOK, map[string]string{"message": "media reordered"})
+}
+
+func (h *Media) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := api.URLParamInt(r, "id")
+	if err != nil {
+		api.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err = h.controller.Delete(ctx, id); err != nil {
+		h.writeError(w, err, "Delete")
+		return
+	}
+
+	api.WriteJSON(w, http.StatusOK, nil)
+}
+
+func (h *Media) writeError(w http.ResponseWriter, err error, method string) {
+	var notFound *errorspkg.ErrRepoNotFound
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, errorspkg.ErrUnknownMediaOwner), errors.Is(err, errorspkg.ErrMediaOrder):
+		status = http.StatusBadRequest
+	case errors.Is(err, errorspkg.ErrMediaTooLarge):
+		status = http.StatusRequestEntityTooLarge
+	case errors.Is(err, errorspkg.ErrUnsupportedMedia):
+		status = http.StatusUnsupportedMediaType
+	case errors.As(err, &notFound):
+		status = http.StatusNotFound
+	default:
+		h.logger.Error(err.Error(), "method", method)
+	}
+	api.WriteError(w, status, err)
+}
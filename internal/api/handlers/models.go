@@ -1,15 +1,100 @@
 package handlers
 
+import (
+	"encoding/json"
+	"time"
+)
+
 type (
 	House struct {
-		ID            int      `json:"id"`
-		Name          string   `json:"title"`
-		Description   string   `json:"description"`
-		Capacity      int      `json:"people"`
-		BasePrice     int      `json:"cost"`
-		Images        []string `json:"images"`
-		CheckInFrom   string   `json:"timeFirst"`
-		CheckOutUntil string   `json:"timeSecond"`
+		ID            int            `json:"id"`
+		Name          string         `json:"title"`
+		Description   string         `json:"description"`
+		Capacity      int            `json:"people"`
+		BasePrice     int            `json:"cost"`
+		CoverImage    string         `json:"cover"`
+		Images        []string       `json:"images"`
+		CheckInFrom   string         `json:"timeFirst"`
+		CheckOutUntil string         `json:"timeSecond"`
+		Directions    string         `json:"directions"`
+		Amenities     HouseAmenities `json:"amenities"`
+		Tags          []string       `json:"tags"`
+		Rating        float64        `json:"rating"`
+		ReviewsCount  int            `json:"reviewsCount"`
+		Reviews       []HouseReview  `json:"reviews"`
+	}
+
+	HouseAmenities struct {
+		Sauna       bool       `json:"sauna"`
+		PetsAllowed bool       `json:"petsAllowed"`
+		Kitchen     bool       `json:"kitchen"`
+		Fireplace   bool       `json:"fireplace"`
+		Beds        []HouseBed `json:"beds"`
+		Area        int        `json:"area"`
+	}
+
+	HouseBed struct {
+		Kind  string `json:"kind"`
+		Count int    `json:"count"`
+	}
+
+	HouseTags struct {
+		Tags []string `json:"tags"`
+	}
+
+	HouseTag struct {
+		Name   string `json:"name"`
+		Houses int    `json:"houses"`
+	}
+
+	HouseReview struct {
+		GuestName string    `json:"guestName"`
+		Rating    int       `json:"rating"`
+		Comment   string    `json:"comment,omitempty"`
+		CreatedAt time.Time `json:"createdAt"`
+	}
+
+	Review struct {
+		ID              int        `json:"id"`
+		ReservationUUID string     `json:"reservationUuid"`
+		HouseID         int        `json:"houseId"`
+		GuestName       string     `json:"guestName"`
+		Rating          int        `json:"rating"`
+		Comment         string     `json:"comment"`
+		Status          string     `json:"status"`
+		CreatedAt       time.Time  `json:"createdAt"`
+		ModeratedAt     *time.Time `json:"moderatedAt,omitempty"`
+	}
+
+	ReviewModeration struct {
+		Status string `json:"status"`
+	}
+
+	BotAdmin struct {
+		TgID      int64     `json:"tgId"`
+		Name      string    `json:"name"`
+		CreatedAt time.Time `json:"createdAt"`
+	}
+
+	GuestProfile struct {
+		ID        string    `json:"id"`
+		Name      string    `json:"name"`
+		Email     string    `json:"email"`
+		Phone     string    `json:"phone"`
+		TgID      int64     `json:"tgId,omitempty"`
+		Locale    string    `json:"locale,omitempty"`
+		CreatedAt time.Time `json:"createdAt"`
+	}
+
+	GuestMergeRequest struct {
+		SourceID string `json:"sourceId"`
+	}
+
+	GuestMerge struct {
+		Guest         GuestProfile `json:"guest"`
+		Reservations  int64        `json:"reservations"`
+		Reviews       int64        `json:"reviews"`
+		Notifications int64        `json:"notifications"`
 	}
 
 	Extra struct {
@@ -21,10 +106,29 @@ type (
 		Images      []string `json:"images"`
 	}
 
+	// GetAvailableHouses: tags may be repeated or comma separated.
 	GetAvailableHouses struct {
-		CheckIn     string `schema:"in"`
-		CheckOut    string `schema:"out"`
-		GuestsCount int    `schema:"guests"`
+		CheckIn     string   `schema:"in"`
+		CheckOut    string   `schema:"out"`
+		GuestsCount int      `schema:"guests"`
+		Sauna       bool     `schema:"sauna"`
+		PetsAllowed bool     `schema:"pets"`
+		Kitchen     bool     `schema:"kitchen"`
+		Fireplace   bool     `schema:"fireplace"`
+		Bathhouse   bool     `schema:"bathhouse"`
+		MinArea     int      `schema:"area"`
+		Tags        []string `schema:"tags"`
+	}
+
+	// FlexibleSearch: in and out are the requested stay for mode=shift and the window
+	// to fit nights into for mode=nights, mode=weekend takes month as YYYY-MM.
+	FlexibleSearch struct {
+		GetAvailableHouses
+		Mode   string `schema:"mode"`
+		Days   int    `schema:"days"`
+		Month  string `schema:"month"`
+		Nights int    `schema:"nights"`
+		Limit  int    `schema:"limit"`
 	}
 
 	CreateReservation struct {
@@ -58,9 +162,23 @@ type (
 	}
 
 	VerifyRequest struct {
-		Email string `json:"email"`
-		Phone string `json:"phone"`
-		Name  string `json:"name"`
+		Email          string   `json:"email"`
+		Phone          string   `json:"phone"`
+		Name           string   `json:"name"`
+		NotifyChannels []string `json:"notifyChannels,omitempty"`
+	}
+
+	VerificationLink struct {
+		ID        string    `json:"id"`
+		Link      string    `json:"link"`
+		ExpiresAt time.Time `json:"expiresAt"`
+	}
+
+	VerificationStatus struct {
+		ID         string     `json:"id"`
+		Status     string     `json:"status"`
+		ExpiresAt  time.Time  `json:"expiresAt"`
+		VerifiedAt *time.Time `json:"verifiedAt,omitempty"`
 	}
 
 	Bathhouse struct {
@@ -82,10 +200,120 @@ type (
 		Price       int    `json:"price"`
 	}
 
+	CalendarSource struct {
+		ID           int        `json:"id"`
+		HouseID      int        `json:"houseId"`
+		Name         string     `json:"name"`
+		URL          string     `json:"url"`
+		LastSyncedAt *time.Time `json:"lastSyncedAt,omitempty"`
+	}
+
+	OutboxMessage struct {
+		ID        int64           `json:"id"`
+		Kind      string          `json:"kind"`
+		Attempts  int             `json:"attempts"`
+		LastError string          `json:"lastError"`
+		CreatedAt time.Time       `json:"createdAt"`
+		Payload   json.RawMessage `json:"payload"`
+	}
+
+	MessageTemplate struct {
+		Key       string     `json:"key"`
+		Locale    string     `json:"locale"`
+		Body      string     `json:"body"`
+		IsDefault bool       `json:"isDefault"`
+		UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+	}
+
+	TemplateBody struct {
+		Body string `json:"body"`
+	}
+
 	EventsNewApplication struct {
 		Name        string `json:"name"`
 		Phone       string `json:"phone"`
 		CheckIn     string `json:"checkIn"`
 		GuestsCount int    `json:"guestsCount"`
 	}
+
+	EventApplication struct {
+		ID          int       `json:"id"`
+		Name        string    `json:"name"`
+		Phone       string    `json:"phone"`
+		CheckIn     string    `json:"checkIn"`
+		GuestsCount int       `json:"guestsCount"`
+		Status      string    `json:"status"`
+		Notes       string    `json:"notes"`
+		Manager     string    `json:"manager"`
+		CreatedAt   time.Time `json:"createdAt"`
+		UpdatedAt   time.Time `json:"updatedAt"`
+	}
+
+	// EventApplicationUpdate changes only the fields present in the body.
+	EventApplicationUpdate struct {
+		Status  *string `json:"status,omitempty"`
+		Notes   *string `json:"notes,omitempty"`
+		Manager *string `json:"manager,omitempty"`
+	}
+
+	CreateEventBooking struct {
+		ApplicationID *int   `json:"applicationId,omitempty"`
+		Title         string `json:"title"`
+		ContactName   string `json:"contactName"`
+		ContactPhone  string `json:"contactPhone"`
+		CheckIn       string `json:"checkIn"`
+		CheckOut      string `json:"checkOut"`
+		GuestsCount   int    `json:"guestsCount"`
+		HouseIDs      []int  `json:"houseIds"`
+		BathhouseIDs  []int  `json:"bathhouseIds"`
+		TotalPrice    int    `json:"totalPrice"`
+		Deposit       int    `json:"deposit"`
+		Notes         string `json:"notes"`
+	}
+
+	EventBooking struct {
+		ID             string    `json:"id"`
+		ApplicationID  *int      `json:"applicationId,omitempty"`
+		Title          string    `json:"title"`
+		ContactName    string    `json:"contactName"`
+		ContactPhone   string    `json:"contactPhone"`
+		CheckIn        string    `json:"checkIn"`
+		CheckOut       string    `json:"checkOut"`
+		GuestsCount    int       `json:"guestsCount"`
+		HouseIDs       []int     `json:"houseIds"`
+		HouseNames     []string  `json:"houseNames"`
+		BathhouseIDs   []int     `json:"bathhouseIds"`
+		BathhouseNames []string  `json:"bathhouseNames"`
+		TotalPrice     int       `json:"totalPrice"`
+		Deposit        int       `json:"deposit"`
+		Status         string    `json:"status"`
+		Notes          string    `json:"notes"`
+		CreatedAt      time.Time `json:"createdAt"`
+	}
+
+	MediaFile struct {
+		ID          int            `json:"id"`
+		Owner       string         `json:"owner"`
+		OwnerID     int            `json:"ownerId"`
+		Position    int            `json:"position"`
+		ContentType string         `json:"contentType"`
+		Size        int            `json:"size"`
+		Width       int            `json:"width"`
+		Height      int            `json:"height"`
+		URL         string         `json:"url"`
+		Variants    []MediaVariant `json:"variants"`
+		CreatedAt   time.Time      `json:"createdAt"`
+	}
+
+	MediaVariant struct {
+		Name   string `json:"name"`
+		URL    string `json:"url"`
+		Width  int    `json:"width"`
+		Height int    `json:"height"`
+	}
+
+	// MediaOrder lists every media id of the owner in the new gallery order.
+	MediaOrder struct {
+		IDs []int `json:"ids"`
+	}
 )
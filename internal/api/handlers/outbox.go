@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/calyrexx/QuietGrooveBackend/internal/api"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"log/slog"
+	"net/http"
+)
+
+type IOutboxController interface {
+	GetFailed(ctx context.Context) ([]OutboxMessage, error)
+	Retry(ctx context.Context, id int64) error
+}
+
+type OutboxDependencies struct {
+	Controller IOutboxController
+	Logger     *slog.Logger
+}
+
+type Outbox struct {
+	controller IOutboxController
+	logger     *slog.Logger
+}
+
+func NewOutbox(dep OutboxDependencies) (*Outbox, error) {
+	if dep.Logger == nil {
+		return nil, errorspkg.NewErrConstructorDependencies("NewOutbox", "Logger", "nil")
+	}
+	if dep.Controller == nil {
+		return nil, errorspkg.NewErrConstructorDependencies("NewOutbox", "Controller", "nil")
+	}
+
+	logger := dep.Logger.With("Handler", "Outbox")
+
+	return &Outbox{
+		controller: dep.Controller,
+		logger:     logger,
+	}, nil
+}
+
+func (h *Outbox) GetFailed(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	messages, err := h.controller.GetFailed(ctx)
+	if err != nil {
+		h.logger.Error(err.Error(), "method", "GetFailed")
+		api.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	api.WriteJSON(w, http.StatusOK, messages)
+}
+
+func (h *Outbox) Retry(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := api.URLParamInt(r, "id")
+	if err != nil {
+		api.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err = h.controller.Retry(ctx, int64(id)); err != nil {
+		var notFound *errorspkg.ErrRepoNotFound
+		status := http.StatusInternalServerError
+		if errors.As(err, &notFound) {
+			status = http.StatusNotFound
+		}
+		h.logger.Error(err.Error(), "method", "Retry")
+		api.WriteError(w, status, err)
+		return
+	}
+
+	api.WriteJSON(w, http.StatusOK, nil)
+}
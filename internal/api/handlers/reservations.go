@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"github.com/calyrexx/QuietGrooveBackend/internal/api"
 	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
 	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
@@ -9,11 +10,13 @@ import (
 	"github.com/gorilla/schema"
 	"log/slog"
 	"net/http"
+	"time"
 )
 
 type IControllers interface {
 	CreateReservation(ctx context.Context, req CreateReservation) (entities.Reservation, error)
 	GetAvailableHouses(ctx context.Context, req GetAvailableHouses) ([]usecases.GetAvailableHousesResponse, error)
+	GetAlternatives(ctx context.Context, req FlexibleSearch) ([]usecases.AlternativeStay, error)
 }
 
 type ReservationsDependencies struct {
@@ -55,6 +58,10 @@ func (h *Reservations) GetAvailableHouses(w http.ResponseWriter, r *http.Request
 
 	result, err := h.controller.GetAvailableHouses(ctx, req)
 	if err != nil {
+		if errors.Is(err, errorspkg.ErrInvalidTag) {
+			api.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
 		h.logger.Error(err.Error(), "method", "GetAvailableHouses")
 		api.WriteError(w, http.StatusInternalServerError, err)
 		return
@@ -63,6 +70,37 @@ func (h *Reservations) GetAvailableHouses(w http.ResponseWriter, r *http.Request
 	api.WriteJSON(w, http.StatusOK, result)
 }
 
+// GetAlternatives suggests free stays near the requested dates, see FlexibleSearch for the modes.
+func (h *Reservations) GetAlternatives(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	decoder := schema.NewDecoder()
+
+	var req FlexibleSearch
+	if err := decoder.Decode(&req, r.URL.Query()); err != nil {
+		api.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := h.controller.GetAlternatives(ctx, req)
+	if err != nil {
+		var parseErr *time.ParseError
+		switch {
+		case errors.As(err, &parseErr),
+			errors.Is(err, errorspkg.ErrInvalidTag),
+			errors.Is(err, errorspkg.ErrFlexibleMode),
+			errors.Is(err, errorspkg.ErrFlexibleRange):
+			api.WriteError(w, http.StatusBadRequest, err)
+		default:
+			h.logger.Error(err.Error(), "method", "GetAlternatives")
+			api.WriteError(w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	api.WriteJSON(w, http.StatusOK, result)
+}
+
 func (h *Reservations) CreateReservation(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -74,6 +112,15 @@ func (h *Reservations) CreateReservation(w http.ResponseWriter, r *http.Request)
 
 	result, err := h.controller.CreateReservation(ctx, req)
 	if err != nil {
+		if errors.Is(err, errorspkg.ErrInvalidPhone) {
+			api.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+		var notFound *errorspkg.ErrRepoNotFound
+		if errors.As(err, &notFound) {
+			api.WriteError(w, http.StatusNotFound, err)
+			return
+		}
 		h.logger.Error(err.Error(), "method", "CreateReservation")
 		api.WriteError(w, http.StatusInternalServerError, err)
 		return
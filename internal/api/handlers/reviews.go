@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/calyrexx/QuietGrooveBackend/internal/api"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"log/slog"
+	"net/http"
+)
+
+type IReviewsController interface {
+	GetAll(ctx context.Context, status string) ([]Review, error)
+	Moderate(ctx context.Context, id int, status string) error
+}
+
+type ReviewsDependencies struct {
+	Controller IReviewsController
+	Logger     *slog.Logger
+}
+
+type Reviews struct {
+	controller IReviewsController
+	logger     *slog.Logger
+}
+
+func NewReviews(dep ReviewsDependencies) (*Reviews, error) {
+	if dep.Logger == nil {
+		return nil, errorspkg.NewErrConstructorDependencies("NewReviews", "Logger", "nil")
+	}
+	if dep.Controller == nil {
+		return nil, errorspkg.NewErrConstructorDependencies("NewReviews", "Controller", "nil")
+	}
+
+	logger := dep.Logger.With("Handler", "Reviews")
+
+	return &Reviews{
+		controller: dep.Controller,
+		logger:     logger,
+	}, nil
+}
+
+// GetAll lists reviews for moderation, ?status=pending|approved|rejected narrows the list.
+func (h *Reviews) GetAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	reviews, err := h.controller.GetAll(ctx, r.URL.Query().Get("status"))
+	if err != nil {
+		h.logger.Error(err.Error(), "method", "GetAll")
+		api.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	api.WriteJSON(w, http.StatusOK, reviews)
+}
+
+func (h *Reviews) Moderate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := api.URLParamInt(r, "id")
+	if err != nil {
+		api.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req ReviewModeration
+	if err = api.ReadJSON(r, &req); err != nil {
+		api.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err = h.controller.Moderate(ctx, id, req.Status); err != nil {
+		var notFound *errorspkg.ErrRepoNotFound
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, errorspkg.ErrInvalidReviewStatus):
+			status = http.StatusBadRequest
+		case errors.As(err, &notFound):
+			status = http.StatusNotFound
+		}
+		h.logger.Error(err.Error(), "method", "Moderate")
+		api.WriteError(w, status, err)
+		return
+	}
+
+	api.WriteJSON(w, http.StatusOK, nil)
+}
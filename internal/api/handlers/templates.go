@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/calyrexx/QuietGrooveBackend/internal/api"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/gorilla/mux"
+	"log/slog"
+	"net/http"
+)
+
+type ITemplatesController interface {
+	GetAll(ctx context.Context) ([]MessageTemplate, error)
+	Update(ctx context.Context, key, locale, body string) error
+	Reset(ctx context.Context, key, locale string) error
+	Preview(ctx context.Context, key, locale, body string) (string, error)
+}
+
+type TemplatesDependencies struct {
+	Controller ITemplatesController
+	Logger     *slog.Logger
+}
+
+type Templates struct {
+	controller ITemplatesController
+	logger     *slog.Logger
+}
+
+func NewTemplates(dep TemplatesDependencies) (*Templates, error) {
+	if dep.Logger == nil {
+		return nil, errorspkg.NewErrConstructorDependencies("NewTemplates", "Logger", "nil")
+	}
+	if dep.Controller == nil {
+		return nil, errorspkg.NewErrConstructorDependencies("NewTemplates", "Controller", "nil")
+	}
+
+	logger := dep.Logger.With("Handler", "Templates")
+
+	return &Templates{
+		controller: dep.Controller,
+		logger:     logger,
+	}, nil
+}
+
+func (h *Templates) GetAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	templates, err := h.controller.GetAll(ctx)
+	if err != nil {
+		h.logger.Error(err.Error(), "method", "GetAll")
+		api.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	api.WriteJSON(w, http.StatusOK, templates)
+}
+
+func (h *Templates) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+
+	var req TemplateBody
+	if err := api.ReadJSON(r, &req); err != nil {
+		api.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+	if req.Body == "" {
+		api.WriteError(w, http.StatusBadRequest, errors.New("body is required"))
+		return
+	}
+
+	if err := h.controller.Update(ctx, vars["key"], vars["locale"], req.Body); err != nil {
+		h.writeError(w, "Update", err)
+		return
+	}
+
+	api.WriteJSON(w, http.StatusOK, nil)
+}
+
+func (h *Templates) Reset(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+
+	if err := h.controller.Reset(ctx, vars["key"], vars["locale"]); err != nil {
+		h.writeError(w, "Reset", err)
+		return
+	}
+
+	api.WriteJSON(w, http.StatusOK, nil)
+}
+
+func (h *Templates) Preview(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+
+	var req TemplateBody
+	if r.ContentLength != 0 {
+		if err := api.ReadJSON(r, &req); err != nil {
+			api.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	text, err := h.controller.Preview(ctx, vars["key"], vars["locale"], req.Body)
+	if err != nil {
+		h.writeError(w, "Preview", err)
+		return
+	}
+
+	api.WriteJSON(w, http.StatusOK, map[string]string{"text": text})
+}
+
+func (h *Templates) writeError(w http.ResponseWriter, method string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, errorspkg.ErrUnknownTemplate):
+		status = http.StatusNotFound
+	case errors.Is(err, errorspkg.ErrInvalidTemplate):
+		status = http.StatusBadRequest
+	}
+	h.logger.Error(err.Error(), "method", method)
+	api.WriteError(w, status, err)
+}
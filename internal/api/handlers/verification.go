@@ -2,14 +2,18 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"github.com/calyrexx/QuietGrooveBackend/internal/api"
 	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
 	"log/slog"
 	"net/http"
 )
 
 type IVerificationController interface {
-	Generate(ctx context.Context, email, phone, name string) (string, error)
+	Generate(ctx context.Context, email, phone, name string, channels []string) (VerificationLink, error)
+	Status(ctx context.Context, id string) (VerificationStatus, error)
 }
 
 type VerificationDependencies struct {
@@ -47,12 +51,41 @@ func (h *Verification) VerifyIdentity(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp, err := h.controller.Generate(ctx, req.Email, req.Phone, req.Name)
+	resp, err := h.controller.Generate(ctx, req.Email, req.Phone, req.Name, req.NotifyChannels)
 	if err != nil {
+		if errors.Is(err, errorspkg.ErrUnknownNotifyChannel) || errors.Is(err, errorspkg.ErrInvalidPhone) {
+			api.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
 		h.logger.Error(err.Error(), "method", "VerifyIdentity")
 		api.WriteError(w, http.StatusInternalServerError, err)
 		return
 	}
 
-	api.WriteJSON(w, http.StatusCreated, map[string]string{"code": resp})
+	api.WriteJSON(w, http.StatusCreated, resp)
+}
+
+// Status is polled by the website until the guest confirms the link in Telegram.
+func (h *Verification) Status(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		api.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	resp, err := h.controller.Status(ctx, id.String())
+	if err != nil {
+		var notFound *errorspkg.ErrRepoNotFound
+		if errors.As(err, &notFound) {
+			api.WriteError(w, http.StatusNotFound, err)
+			return
+		}
+		h.logger.Error(err.Error(), "method", "Status")
+		api.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	api.WriteJSON(w, http.StatusOK, resp)
 }
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/utils"
+	"net/http"
+	"strings"
+)
+
+const bearerPrefix = "Bearer "
+
+// AdminAuthMiddleware lets through requests with "Authorization: Bearer <token>".
+// Without a configured token every request is refused.
+type AdminAuthMiddleware struct {
+	token []byte
+}
+
+type AdminAuthMiddlewareDependencies struct {
+	Token string
+}
+
+func NewAdminAuthMiddleware(d AdminAuthMiddlewareDependencies) *AdminAuthMiddleware {
+	return &AdminAuthMiddleware{
+		token: []byte(d.Token),
+	}
+}
+
+func (mw *AdminAuthMiddleware) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		if len(mw.token) == 0 || !strings.HasPrefix(header, bearerPrefix) ||
+			subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(header, bearerPrefix)), mw.token) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			utils.WriteError(w, http.StatusUnauthorized, errorspkg.ErrUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -8,25 +8,55 @@ import (
 )
 
 const (
-	healthPath       = "/health"
-	versionPath      = "/version"
-	housesPath       = "/houses"
-	extrasPath       = "/extras"
-	reservationPath  = "/reservation"
-	verificationPath = "/verification"
-	eventsPath       = "/events"
-	bathhousesPath   = "/bathhouses"
-	idPath           = "/{id}"
-	emptyPath        = ""
+	healthPath        = "/health"
+	versionPath       = "/version"
+	housesPath        = "/houses"
+	extrasPath        = "/extras"
+	reservationPath   = "/reservation"
+	alternativesPath  = "/alternatives"
+	verificationPath  = "/verification"
+	eventsPath        = "/events"
+	applicationsPath  = "/applications"
+	applicationPath   = "/applications/{id}"
+	eventBookingsPath = "/bookings"
+	eventBookingPath  = "/bookings/{id}"
+	bathhousesPath    = "/bathhouses"
+	idPath            = "/{id}"
+	calendarPath      = "/{id}/calendar.ics"
+	calendarKeyPath   = "/{id}/calendar-token"
+	calendarsPath     = "/{id}/calendars"
+	calendarIDPath    = "/{id}/calendars/{sourceId}"
+	amenitiesPath     = "/{id}/amenities"
+	tagsPath          = "/tags"
+	houseTagsPath     = "/{id}/tags"
+	outboxPath        = "/notifications/failed"
+	outboxRetryPath   = "/{id}/retry"
+	templatesPath     = "/templates"
+	templatePath      = "/{key}/{locale}"
+	templatePreview   = "/{key}/{locale}/preview"
+	reviewsPath       = "/reviews"
+	adminsPath        = "/admins"
+	guestsPath        = "/guests"
+	guestMergePath    = "/{id}/merge"
+	guestExportPath   = "/{id}/export"
+	mediaPath         = "/media"
+	mediaOwnerPath    = "/{owner}/{id}"
+	mediaOrderPath    = "/{owner}/{id}/order"
+	uploadsPath       = "/uploads/"
+	telegramWebhook   = "/telegram/webhook"
+	emptyPath         = ""
 )
 
 type Middlewares struct {
 	PanicRecovery mux.MiddlewareFunc
+	// AdminAuth guards every route registered on the admin subrouter.
+	AdminAuth mux.MiddlewareFunc
 }
 
 type IReservations interface {
 	CreateReservation(w http.ResponseWriter, r *http.Request)
 	GetAvailableHouses(w http.ResponseWriter, r *http.Request)
+	GetAlternatives(w http.ResponseWriter, r *http.Request)
 }
 
 type IHouses interface {
@@ -34,6 +64,9 @@ type IHouses interface {
 	Add(w http.ResponseWriter, r *http.Request)
 	Update(w http.ResponseWriter, r *http.Request)
 	Delete(w http.ResponseWriter, r *http.Request)
+	UpdateAmenities(w http.ResponseWriter, r *http.Request)
+	UpdateTags(w http.ResponseWriter, r *http.Request)
+	GetTags(w http.ResponseWriter, r *http.Request)
 }
 
 type IBathhouses interface {
@@ -53,10 +86,62 @@ type IExtras interface {
 
 type IVerification interface {
 	VerifyIdentity(w http.ResponseWriter, r *http.Request)
+	Status(w http.ResponseWriter, r *http.Request)
 }
 
 type IEvents interface {
 	NewApplication(w http.ResponseWriter, r *http.Request)
+	Applications(w http.ResponseWriter, r *http.Request)
+	UpdateApplication(w http.ResponseWriter, r *http.Request)
+	CreateBooking(w http.ResponseWriter, r *http.Request)
+	Bookings(w http.ResponseWriter, r *http.Request)
+	Booking(w http.ResponseWriter, r *http.Request)
+	CancelBooking(w http.ResponseWriter, r *http.Request)
+}
+
+type ICalendar interface {
+	Export(w http.ResponseWriter, r *http.Request)
+	RegenerateToken(w http.ResponseWriter, r *http.Request)
+	GetSources(w http.ResponseWriter, r *http.Request)
+	AddSource(w http.ResponseWriter, r *http.Request)
+	DeleteSource(w http.ResponseWriter, r *http.Request)
+}
+
+type IOutbox interface {
+	GetFailed(w http.ResponseWriter, r *http.Request)
+	Retry(w http.ResponseWriter, r *http.Request)
+}
+
+type ITemplates interface {
+	GetAll(w http.ResponseWriter, r *http.Request)
+	Update(w http.ResponseWriter, r *http.Request)
+	Reset(w http.ResponseWriter, r *http.Request)
+	Preview(w http.ResponseWriter, r *http.Request)
+}
+
+type IReviews interface {
+	GetAll(w http.ResponseWriter, r *http.Request)
+	Moderate(w http.ResponseWriter, r *http.Request)
+}
+
+type IAdmins interface {
+	GetAll(w http.ResponseWriter, r *http.Request)
+	Add(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+}
+
+type IGuests interface {
+	Search(w http.ResponseWriter, r *http.Request)
+	Merge(w http.ResponseWriter, r *http.Request)
+	Export(w http.ResponseWriter, r *http.Request)
+	Erase(w http.ResponseWriter, r *http.Request)
+}
+
+type IMedia interface {
+	Upload(w http.ResponseWriter, r *http.Request)
+	List(w http.ResponseWriter, r *http.Request)
+	Reorder(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
 }
 
 type IGeneral interface {
@@ -71,7 +156,18 @@ type Handlers struct {
 	Extras       IExtras
 	Verification IVerification
 	Events       IEvents
+	Calendar     ICalendar
+	Outbox       IOutbox
+	Templates    ITemplates
+	Reviews      IReviews
+	Admins       IAdmins
+	Guests       IGuests
+	Media        IMedia
 	General      IGeneral
+	// TelegramWebhook is nil while the bot uses long polling.
+	TelegramWebhook http.Handler
+	// Uploads serves media from the local storage, nil for other storages.
+	Uploads http.Handler
 }
 
 type RouterDependencies struct {
@@ -92,31 +188,105 @@ func NewRouter(dep RouterDependencies) http.Handler {
 	r.HandleFunc(versionPath, dep.Handlers.General.Version)
 
 	r.HandleFunc(verificationPath, dep.Handlers.Verification.VerifyIdentity).Methods("POST")
+	r.HandleFunc(verificationPath+idPath, dep.Handlers.Verification.Status).Methods(http.MethodGet)
 
 	r.HandleFunc(eventsPath, dep.Handlers.Events.NewApplication).Methods("POST")
 
+	if dep.Handlers.TelegramWebhook != nil {
+		r.Handle(telegramWebhook, dep.Handlers.TelegramWebhook).Methods(http.MethodPost)
+	}
+
+	if dep.Handlers.Uploads != nil {
+		r.PathPrefix(uploadsPath).Handler(http.StripPrefix(uploadsPath, dep.Handlers.Uploads)).Methods(http.MethodGet, http.MethodHead)
+	}
+
 	reservations := r.PathPrefix(reservationPath).Subrouter()
 	reservations.HandleFunc(emptyPath, dep.Handlers.Reservations.GetAvailableHouses).Methods(http.MethodGet)
 	reservations.HandleFunc(emptyPath, dep.Handlers.Reservations.CreateReservation).Methods(http.MethodPost)
+	reservations.HandleFunc(alternativesPath, dep.Handlers.Reservations.GetAlternatives).Methods(http.MethodGet)
 
 	houses := r.PathPrefix(housesPath).Subrouter()
-	houses.HandleFunc(emptyPath, dep.Handlers.Houses.Add).Methods(http.MethodPost)
-	houses.HandleFunc(idPath, dep.Handlers.Houses.Update).Methods(http.MethodPut)
-	houses.HandleFunc(idPath, dep.Handlers.Houses.Delete).Methods(http.MethodDelete)
 	houses.HandleFunc(emptyPath, dep.Handlers.Houses.GetAll).Methods(http.MethodGet)
+	houses.HandleFunc(tagsPath, dep.Handlers.Houses.GetTags).Methods(http.MethodGet)
+	houses.HandleFunc(calendarPath, dep.Handlers.Calendar.Export).Methods(http.MethodGet)
 
 	bathhouses := r.PathPrefix(bathhousesPath).Subrouter()
-	bathhouses.HandleFunc(emptyPath, dep.Handlers.Bathhouses.Add).Methods(http.MethodPost)
-	bathhouses.HandleFunc(idPath, dep.Handlers.Bathhouses.Update).Methods(http.MethodPut)
-	bathhouses.HandleFunc(idPath, dep.Handlers.Bathhouses.Delete).Methods(http.MethodDelete)
 	bathhouses.HandleFunc(emptyPath, dep.Handlers.Bathhouses.GetAll).Methods(http.MethodGet)
 	bathhouses.HandleFunc(idPath, dep.Handlers.Bathhouses.GetByHouse).Methods(http.MethodGet)
 
 	extras := r.PathPrefix(extrasPath).Subrouter()
-	extras.HandleFunc(emptyPath, dep.Handlers.Extras.Add).Methods(http.MethodPost)
-	extras.HandleFunc(idPath, dep.Handlers.Extras.Update).Methods(http.MethodPut)
-	extras.HandleFunc(idPath, dep.Handlers.Extras.Delete).Methods(http.MethodDelete)
 	extras.HandleFunc(emptyPath, dep.Handlers.Extras.GetAll).Methods(http.MethodGet)
 
+	// Approved reviews are public, any other status filter is answered by the admin route.
+	reviews := r.PathPrefix(reviewsPath).Subrouter()
+	reviews.HandleFunc(emptyPath, dep.Handlers.Reviews.GetAll).Methods(http.MethodGet).Queries("status", "approved")
+
+	media := r.PathPrefix(mediaPath).Subrouter()
+	media.HandleFunc(mediaOwnerPath, dep.Handlers.Media.List).Methods(http.MethodGet)
+
+	// Management routes go below: the admin subrouter checks the admin token before any of them.
+	// It is registered after the public routes so that they keep answering on shared paths.
+	admin := r.NewRoute().Subrouter()
+	admin.Use(dep.Middlewares.AdminAuth)
+
+	adminHouses := admin.PathPrefix(housesPath).Subrouter()
+	adminHouses.HandleFunc(emptyPath, dep.Handlers.Houses.Add).Methods(http.MethodPost)
+	adminHouses.HandleFunc(idPath, dep.Handlers.Houses.Update).Methods(http.MethodPut)
+	adminHouses.HandleFunc(idPath, dep.Handlers.Houses.Delete).Methods(http.MethodDelete)
+	adminHouses.HandleFunc(amenitiesPath, dep.Handlers.Houses.UpdateAmenities).Methods(http.MethodPut)
+	adminHouses.HandleFunc(houseTagsPath, dep.Handlers.Houses.UpdateTags).Methods(http.MethodPut)
+	adminHouses.HandleFunc(calendarKeyPath, dep.Handlers.Calendar.RegenerateToken).Methods(http.MethodPost)
+	adminHouses.HandleFunc(calendarsPath, dep.Handlers.Calendar.GetSources).Methods(http.MethodGet)
+	adminHouses.HandleFunc(calendarsPath, dep.Handlers.Calendar.AddSource).Methods(http.MethodPost)
+	adminHouses.HandleFunc(calendarIDPath, dep.Handlers.Calendar.DeleteSource).Methods(http.MethodDelete)
+
+	adminBathhouses := admin.PathPrefix(bathhousesPath).Subrouter()
+	adminBathhouses.HandleFunc(emptyPath, dep.Handlers.Bathhouses.Add).Methods(http.MethodPost)
+	adminBathhouses.HandleFunc(idPath, dep.Handlers.Bathhouses.Update).Methods(http.MethodPut)
+	adminBathhouses.HandleFunc(idPath, dep.Handlers.Bathhouses.Delete).Methods(http.MethodDelete)
+
+	adminExtras := admin.PathPrefix(extrasPath).Subrouter()
+	adminExtras.HandleFunc(emptyPath, dep.Handlers.Extras.Add).Methods(http.MethodPost)
+	adminExtras.HandleFunc(idPath, dep.Handlers.Extras.Update).Methods(http.MethodPut)
+	adminExtras.HandleFunc(idPath, dep.Handlers.Extras.Delete).Methods(http.MethodDelete)
+
+	outbox := admin.PathPrefix(outboxPath).Subrouter()
+	outbox.HandleFunc(emptyPath, dep.Handlers.Outbox.GetFailed).Methods(http.MethodGet)
+	outbox.HandleFunc(outboxRetryPath, dep.Handlers.Outbox.Retry).Methods(http.MethodPost)
+
+	templates := admin.PathPrefix(templatesPath).Subrouter()
+	templates.HandleFunc(emptyPath, dep.Handlers.Templates.GetAll).Methods(http.MethodGet)
+	templates.HandleFunc(templatePath, dep.Handlers.Templates.Update).Methods(http.MethodPut)
+	templates.HandleFunc(templatePath, dep.Handlers.Templates.Reset).Methods(http.MethodDelete)
+	templates.HandleFunc(templatePreview, dep.Handlers.Templates.Preview).Methods(http.MethodPost)
+
+	adminReviews := admin.PathPrefix(reviewsPath).Subrouter()
+	adminReviews.HandleFunc(emptyPath, dep.Handlers.Reviews.GetAll).Methods(http.MethodGet)
+	adminReviews.HandleFunc(idPath, dep.Handlers.Reviews.Moderate).Methods(http.MethodPut)
+
+	admins := admin.PathPrefix(adminsPath).Subrouter()
+	admins.HandleFunc(emptyPath, dep.Handlers.Admins.GetAll).Methods(http.MethodGet)
+	admins.HandleFunc(emptyPath, dep.Handlers.Admins.Add).Methods(http.MethodPost)
+	admins.HandleFunc(idPath, dep.Handlers.Admins.Delete).Methods(http.MethodDelete)
+
+	events := admin.PathPrefix(eventsPath).Subrouter()
+	events.HandleFunc(applicationsPath, dep.Handlers.Events.Applications).Methods(http.MethodGet)
+	events.HandleFunc(applicationPath, dep.Handlers.Events.UpdateApplication).Methods(http.MethodPut)
+	events.HandleFunc(eventBookingsPath, dep.Handlers.Events.CreateBooking).Methods(http.MethodPost)
+	events.HandleFunc(eventBookingsPath, dep.Handlers.Events.Bookings).Methods(http.MethodGet)
+	events.HandleFunc(eventBookingPath, dep.Handlers.Events.Booking).Methods(http.MethodGet)
+	events.HandleFunc(eventBookingPath, dep.Handlers.Events.CancelBooking).Methods(http.MethodDelete)
+
+	guests := admin.PathPrefix(guestsPath).Subrouter()
+	guests.HandleFunc(emptyPath, dep.Handlers.Guests.Search).Methods(http.MethodGet)
+	guests.HandleFunc(guestMergePath, dep.Handlers.Guests.Merge).Methods(http.MethodPost)
+	guests.HandleFunc(guestExportPath, dep.Handlers.Guests.Export).Methods(http.MethodGet)
+	guests.HandleFunc(idPath, dep.Handlers.Guests.Erase).Methods(http.MethodDelete)
+
+	adminMedia := admin.PathPrefix(mediaPath).Subrouter()
+	adminMedia.HandleFunc(mediaOwnerPath, dep.Handlers.Media.Upload).Methods(http.MethodPost)
+	adminMedia.HandleFunc(mediaOrderPath, dep.Handlers.Media.Reorder).Methods(http.MethodPut)
+	adminMedia.HandleFunc(idPath, dep.Handlers.Media.Delete).Methods(http.MethodDelete)
+
 	return middleware.WithCORS(r)
 }
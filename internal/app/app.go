@@ -3,12 +3,25 @@ package app
 import (
 	"context"
 	"github.com/calyrexx/QuietGrooveBackend/internal/configuration"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/integrations/email"
+	"github.com/calyrexx/QuietGrooveBackend/internal/integrations/localstorage"
+	"github.com/calyrexx/QuietGrooveBackend/internal/integrations/s3storage"
+	"github.com/calyrexx/QuietGrooveBackend/internal/integrations/sms"
 	"github.com/calyrexx/QuietGrooveBackend/internal/integrations/telegram"
+	"github.com/calyrexx/QuietGrooveBackend/internal/integrations/webhook"
 	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/calyrexx/QuietGrooveBackend/internal/usecases"
 	"log/slog"
+	"net/http"
 	"sync"
 )
 
+const (
+	storageLocal = "local"
+	storageS3    = "s3"
+)
+
 type App struct {
 	repo        *Registry
 	rest        *Rest
@@ -42,12 +55,37 @@ func New(
 		return nil, err
 	}
 
-	usecases, err := NewUsecases(logger, config, repo, tgBot)
+	templates, err := NewTemplates(logger, config, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	channels, err := newNotificationChannels(logger, creds, tgBot, templates)
 	if err != nil {
 		return nil, err
 	}
 
-	tgBot.RegisterHandlers(usecases.verification, usecases.reservations)
+	storage, uploads, err := newMediaStorage(creds)
+	if err != nil {
+		return nil, err
+	}
+
+	usecases, err := NewUsecases(logger, config, repo, tgBot, channels, templates, storage)
+	if err != nil {
+		return nil, err
+	}
+
+	tgBot.RegisterHandlers(
+		usecases.verification,
+		usecases.reservations,
+		usecases.reviews,
+		usecases.admin,
+		usecases.booking,
+		usecases.guests,
+		usecases.loyalty,
+		usecases.events,
+		usecases.templates,
+	)
 
 	controllers, err := NewControllers(logger, usecases)
 	if err != nil {
@@ -58,7 +96,10 @@ func New(
 		controllers,
 		logger,
 		config.WebServer,
+		&creds.API,
 		version,
+		tgBot.WebhookHandler(),
+		uploads,
 	)
 	if err != nil {
 		return nil, err
@@ -71,6 +112,11 @@ func New(
 
 	appCron.Add(config.AppCron.UpdateReservationsStatuses.Spec, usecases.reservations.UpdateStatuses)
 	appCron.Add(config.AppCron.GetForReminder.Spec, usecases.reservations.GetForReminder)
+	appCron.Add(config.AppCron.SyncExternalCalendars.Spec, usecases.calendar.SyncImported)
+	appCron.Add(config.AppCron.ProcessOutbox.Spec, usecases.outbox.Process)
+	appCron.Add(config.AppCron.RequestFeedback.Spec, usecases.reviews.RequestFeedback)
+	appCron.Add(config.AppCron.CleanupVerifications.Spec, usecases.verification.Cleanup)
+	appCron.Add(config.AppCron.AnonymizeInactiveGuests.Spec, usecases.guests.AnonymizeInactive)
 
 	return &App{
 		repo:        repo,
@@ -82,6 +128,65 @@ func New(
 	}, nil
 }
 
+// newNotificationChannels registers Telegram and every optional channel that has credentials.
+func newNotificationChannels(
+	logger *slog.Logger,
+	creds *configuration.Credentials,
+	tgBot *telegram.Adapter,
+	templates *usecases.Templates,
+) (map[entities.NotificationChannel]usecases.ChannelNotifier, error) {
+	channels := map[entities.NotificationChannel]usecases.ChannelNotifier{
+		entities.ChannelTelegram: tgBot,
+	}
+
+	if creds.SMTP.Host != "" {
+		mailer, err := email.NewAdapter(&creds.SMTP, logger)
+		if err != nil {
+			return nil, err
+		}
+		channels[entities.ChannelEmail] = mailer
+	}
+
+	if creds.SMS.APIKey != "" {
+		smsAdapter, err := sms.NewAdapter(&creds.SMS, &http.Client{Timeout: creds.SMS.Timeout}, templates, logger)
+		if err != nil {
+			return nil, err
+		}
+		channels[entities.ChannelSMS] = smsAdapter
+	}
+
+	if len(creds.Webhook.URLs) > 0 {
+		webhookAdapter, err := webhook.NewAdapter(&creds.Webhook, &http.Client{Timeout: creds.Webhook.Timeout}, logger)
+		if err != nil {
+			return nil, err
+		}
+		channels[entities.ChannelWebhook] = webhookAdapter
+	}
+
+	return channels, nil
+}
+
+// newMediaStorage picks the storage from credentials. The handler serving files is
+// returned only for the local storage, S3 serves them itself.
+func newMediaStorage(creds *configuration.Credentials) (usecases.MediaStorage, http.Handler, error) {
+	switch creds.Storage.Backend {
+	case "", storageLocal:
+		local, err := localstorage.NewAdapter(&creds.Storage.Local)
+		if err != nil {
+			return nil, nil, err
+		}
+		return local, local.Handler(), nil
+	case storageS3:
+		s3, err := s3storage.NewAdapter(&creds.Storage.S3, &http.Client{Timeout: creds.Storage.S3.Timeout})
+		if err != nil {
+			return nil, nil, err
+		}
+		return s3, nil, nil
+	default:
+		return nil, nil, errorspkg.NewErrConstructorDependencies("App", "Storage.Backend", creds.Storage.Backend)
+	}
+}
+
 func (a *App) Start(ctx context.Context, wg *sync.WaitGroup) error {
 	var err error
 
@@ -12,6 +12,13 @@ type Controllers struct {
 	Extras       *controllers.Extras
 	Verification *controllers.Verification
 	Events       *controllers.Events
+	Calendar     *controllers.Calendar
+	Outbox       *controllers.Outbox
+	Templates    *controllers.Templates
+	Reviews      *controllers.Reviews
+	Admins       *controllers.Admins
+	Guests       *controllers.Guests
+	Media        *controllers.Media
 }
 
 func NewControllers(
@@ -61,6 +68,55 @@ func NewControllers(
 		return nil, err
 	}
 
+	calendarController, err := controllers.NewCalendar(&controllers.CalendarDependencies{
+		UseCase: usecases.calendar,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	outboxController, err := controllers.NewOutbox(&controllers.OutboxDependencies{
+		UseCase: usecases.outbox,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	templatesController, err := controllers.NewTemplates(&controllers.TemplatesDependencies{
+		UseCase: usecases.templates,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reviewsController, err := controllers.NewReviews(&controllers.ReviewsDependencies{
+		UseCase: usecases.reviews,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	adminsController, err := controllers.NewAdmins(&controllers.AdminsDependencies{
+		UseCase: usecases.admin,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	guestsController, err := controllers.NewGuests(&controllers.GuestsDependencies{
+		UseCase: usecases.guests,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	mediaController, err := controllers.NewMedia(&controllers.MediaDependencies{
+		UseCase: usecases.media,
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	return &Controllers{
 		Reservations: reservationsController,
 		Houses:       housesController,
@@ -68,5 +124,12 @@ func NewControllers(
 		Extras:       extrasController,
 		Verification: verificationController,
 		Events:       eventsController,
+		Calendar:     calendarController,
+		Outbox:       outboxController,
+		Templates:    templatesController,
+		Reviews:      reviewsController,
+		Admins:       adminsController,
+		Guests:       guestsController,
+		Media:        mediaController,
 	}, nil
 }
@@ -9,12 +9,23 @@ import (
 )
 
 type Registry struct {
-	Reservations repository.IReservations
-	Houses       repository.IHouses
-	Bathhouses   repository.IBathhouses
-	Extras       repository.IExtras
-	Guests       repository.IGuests
-	Verification repository.IVerification
+	Reservations  repository.IReservations
+	Houses        repository.IHouses
+	Bathhouses    repository.IBathhouses
+	Extras        repository.IExtras
+	Guests        repository.IGuests
+	Verification  repository.IVerification
+	Calendar      repository.ICalendar
+	Notifications repository.INotifications
+	Outbox        repository.IOutbox
+	Templates     repository.ITemplates
+	Reviews       repository.IReviews
+	Admin         repository.IAdmin
+	Booking       repository.IBookingSessions
+	Loyalty       repository.ILoyalty
+	Events        repository.IEventApplications
+	EventBookings repository.IEventBookings
+	Media         repository.IMedia
 }
 
 func NewRepo(ctx context.Context, creds *configuration.Credentials) (*Registry, error) {
@@ -33,13 +44,35 @@ func InitRepoRegistry(postgresConnect *pgxpool.Pool) (*Registry, error) {
 	extrasRepo := postgres.NewExtrasRepo(postgresConnect)
 	guestsRepo := postgres.NewGuestsRepo(postgresConnect)
 	verificationRepo := postgres.NewVerificationRepo(postgresConnect)
+	calendarRepo := postgres.NewCalendarRepo(postgresConnect)
+	notificationsRepo := postgres.NewNotificationsRepo(postgresConnect)
+	outboxRepo := postgres.NewOutboxRepo(postgresConnect)
+	templatesRepo := postgres.NewTemplatesRepo(postgresConnect)
+	reviewsRepo := postgres.NewReviewsRepo(postgresConnect)
+	adminRepo := postgres.NewAdminRepo(postgresConnect)
+	bookingRepo := postgres.NewBookingSessionsRepo(postgresConnect)
+	loyaltyRepo := postgres.NewLoyaltyRepo(postgresConnect)
+	eventsRepo := postgres.NewEventApplicationsRepo(postgresConnect)
+	eventBookingsRepo := postgres.NewEventBookingsRepo(postgresConnect)
+	mediaRepo := postgres.NewMediaRepo(postgresConnect)
 
 	return &Registry{
-		Reservations: reservationsRepo,
-		Houses:       housesRepo,
-		Bathhouses:   bathhousesRepo,
-		Extras:       extrasRepo,
-		Guests:       guestsRepo,
-		Verification: verificationRepo,
+		Reservations:  reservationsRepo,
+		Houses:        housesRepo,
+		Bathhouses:    bathhousesRepo,
+		Extras:        extrasRepo,
+		Guests:        guestsRepo,
+		Verification:  verificationRepo,
+		Calendar:      calendarRepo,
+		Notifications: notificationsRepo,
+		Outbox:        outboxRepo,
+		Templates:     templatesRepo,
+		Reviews:       reviewsRepo,
+		Admin:         adminRepo,
+		Booking:       bookingRepo,
+		Loyalty:       loyaltyRepo,
+		Events:        eventsRepo,
+		EventBookings: eventBookingsRepo,
+		Media:         mediaRepo,
 	}, nil
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/calyrexx/QuietGrooveBackend/internal/api/middleware"
 	"github.com/calyrexx/QuietGrooveBackend/internal/configuration"
 	"log/slog"
+	"net/http"
 )
 
 type Rest struct {
@@ -17,7 +18,10 @@ func NewRest(
 	controllers *Controllers,
 	logger *slog.Logger,
 	config *configuration.HttpServer,
+	apiCreds *configuration.API,
 	version string,
+	telegramWebhook http.Handler,
+	uploads http.Handler,
 ) (*Rest, error) {
 
 	general, err := handlers.NewGeneral(version)
@@ -32,6 +36,13 @@ func NewRest(
 		return nil, err
 	}
 
+	adminAuthMiddleware := middleware.NewAdminAuthMiddleware(middleware.AdminAuthMiddlewareDependencies{
+		Token: apiCreds.AdminToken,
+	})
+	if apiCreds.AdminToken == "" {
+		logger.Warn("API.AdminToken is empty, admin endpoints are closed")
+	}
+
 	reservationsHandler, err := handlers.NewReservations(handlers.ReservationsDependencies{
 		Controller: controllers.Reservations,
 		Logger:     logger,
@@ -80,18 +91,84 @@ func NewRest(
 		return nil, err
 	}
 
+	calendarHandler, err := handlers.NewCalendar(handlers.CalendarDependencies{
+		Controller: controllers.Calendar,
+		Logger:     logger,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	outboxHandler, err := handlers.NewOutbox(handlers.OutboxDependencies{
+		Controller: controllers.Outbox,
+		Logger:     logger,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	templatesHandler, err := handlers.NewTemplates(handlers.TemplatesDependencies{
+		Controller: controllers.Templates,
+		Logger:     logger,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reviewsHandler, err := handlers.NewReviews(handlers.ReviewsDependencies{
+		Controller: controllers.Reviews,
+		Logger:     logger,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	adminsHandler, err := handlers.NewAdmins(handlers.AdminsDependencies{
+		Controller: controllers.Admins,
+		Logger:     logger,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	guestsHandler, err := handlers.NewGuests(handlers.GuestsDependencies{
+		Controller: controllers.Guests,
+		Logger:     logger,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	mediaHandler, err := handlers.NewMedia(handlers.MediaDependencies{
+		Controller: controllers.Media,
+		Logger:     logger,
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	router := api.NewRouter(api.RouterDependencies{
 		Handlers: api.Handlers{
-			Reservations: reservationsHandler,
-			Houses:       housesHandler,
-			Bathhouses:   bathhousesHandler,
-			Extras:       extrasHandler,
-			Verification: verificationHandler,
-			Events:       eventsHandler,
-			General:      general,
+			Reservations:    reservationsHandler,
+			Houses:          housesHandler,
+			Bathhouses:      bathhousesHandler,
+			Extras:          extrasHandler,
+			Verification:    verificationHandler,
+			Events:          eventsHandler,
+			Calendar:        calendarHandler,
+			Outbox:          outboxHandler,
+			Templates:       templatesHandler,
+			Reviews:         reviewsHandler,
+			Admins:          adminsHandler,
+			Guests:          guestsHandler,
+			Media:           mediaHandler,
+			General:         general,
+			TelegramWebhook: telegramWebhook,
+			Uploads:         uploads,
 		},
 		Middlewares: api.Middlewares{
 			PanicRecovery: panicRecoveryMiddleware.Middleware,
+			AdminAuth:     adminAuthMiddleware.Middleware,
 		},
 	})
 
@@ -2,10 +2,12 @@ package app
 
 import (
 	"github.com/calyrexx/QuietGrooveBackend/internal/configuration"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/integrations/calendars"
 	"github.com/calyrexx/QuietGrooveBackend/internal/integrations/telegram"
 	"github.com/calyrexx/QuietGrooveBackend/internal/usecases"
 	"log/slog"
-	"time"
+	"net/http"
 )
 
 type Usecases struct {
@@ -15,6 +17,15 @@ type Usecases struct {
 	extras       *usecases.Extras
 	verification *usecases.Verification
 	events       *usecases.Events
+	calendar     *usecases.Calendar
+	outbox       *usecases.Outbox
+	templates    *usecases.Templates
+	reviews      *usecases.Reviews
+	admin        *usecases.Admin
+	booking      *usecases.Booking
+	guests       *usecases.Guests
+	loyalty      *usecases.Loyalty
+	media        *usecases.Media
 }
 
 func NewUsecases(
@@ -22,24 +33,60 @@ func NewUsecases(
 	config *configuration.Config,
 	repo *Registry,
 	tgBot *telegram.Adapter,
+	channels map[entities.NotificationChannel]usecases.ChannelNotifier,
+	templatesUsecase *usecases.Templates,
+	storage usecases.MediaStorage,
 ) (*Usecases, error) {
+	notificationsUsecase, err := usecases.NewNotifications(&usecases.NotificationsDependencies{
+		Channels:  channels,
+		Repo:      repo.Notifications,
+		GuestRepo: repo.Guests,
+		Config:    config.Notifications,
+		Logger:    logger,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	outboxUsecase, err := usecases.NewOutbox(&usecases.OutboxDependencies{
+		Repo:     repo.Outbox,
+		Notifier: notificationsUsecase,
+		Config:   config.Outbox,
+		Logger:   logger,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	loyaltyUsecase, err := usecases.NewLoyalty(&usecases.LoyaltyDependencies{
+		Repo:      repo.Loyalty,
+		GuestRepo: repo.Guests,
+		Config:    config.Loyalty,
+		Logger:    logger,
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	reservationsUsecase, err := usecases.NewReservation(&usecases.ReservationDependencies{
 		ReservationRepo: repo.Reservations,
 		GuestRepo:       repo.Guests,
 		HouseRepo:       repo.Houses,
 		BathhouseRepo:   repo.Bathhouses,
+		Loyalty:         loyaltyUsecase,
 		Config:          config.Reservations,
 		Logger:          logger,
-		Notifier:        tgBot,
+		Notifier:        notificationsUsecase,
+		Admins:          notificationsUsecase,
 	})
 	if err != nil {
 		return nil, err
 	}
 
 	housesUsecase, err := usecases.NewHouses(&usecases.HousesDependencies{
-		Repo:   repo.Houses,
-		Logger: logger,
+		Repo:        repo.Houses,
+		ReviewsRepo: repo.Reviews,
+		Logger:      logger,
 	})
 	if err != nil {
 		return nil, err
@@ -62,9 +109,11 @@ func NewUsecases(
 	}
 
 	verificationUsecase, err := usecases.NewVerification(&usecases.VerificationDependencies{
-		Repo:       repo.Verification,
-		GuestsRepo: repo.Guests,
-		TTL:        time.Hour,
+		Repo:     repo.Verification,
+		Notifier: tgBot,
+		Linker:   tgBot,
+		Config:   config.Verification,
+		Logger:   logger,
 	})
 	if err != nil {
 		return nil, err
@@ -72,7 +121,79 @@ func NewUsecases(
 
 	eventsUsecase, err := usecases.NewEvents(&usecases.EventsDependencies{
 		Logger:   logger,
+		Repo:     repo.Events,
+		Bookings: repo.EventBookings,
+		Notifier: notificationsUsecase,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	calendarClient, err := calendars.NewClient(&http.Client{Timeout: config.Calendar.FetchTimeout})
+	if err != nil {
+		return nil, err
+	}
+
+	calendarUsecase, err := usecases.NewCalendar(&usecases.CalendarDependencies{
+		Repo:      repo.Calendar,
+		HouseRepo: repo.Houses,
+		Fetcher:   calendarClient,
+		Notifier:  tgBot,
+		Logger:    logger,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reviewsUsecase, err := usecases.NewReviews(&usecases.ReviewsDependencies{
+		Repo:     repo.Reviews,
 		Notifier: tgBot,
+		Config:   config.Reviews,
+		Logger:   logger,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	adminUsecase, err := usecases.NewAdmin(&usecases.AdminDependencies{
+		Repo:            repo.Admin,
+		ReservationRepo: repo.Reservations,
+		CalendarRepo:    repo.Calendar,
+		HouseRepo:       repo.Houses,
+		Canceller:       reservationsUsecase,
+		Logger:          logger,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	bookingUsecase, err := usecases.NewBooking(&usecases.BookingDependencies{
+		Repo:         repo.Booking,
+		GuestRepo:    repo.Guests,
+		ExtrasRepo:   repo.Extras,
+		Reservations: reservationsUsecase,
+		Loyalty:      loyaltyUsecase,
+		Config:       config.Booking,
+		Logger:       logger,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	guestsUsecase, err := usecases.NewGuests(&usecases.GuestsDependencies{
+		Repo:   repo.Guests,
+		Config: config.Guests,
+		Logger: logger,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	mediaUsecase, err := usecases.NewMedia(&usecases.MediaDependencies{
+		Repo:    repo.Media,
+		Storage: storage,
+		Config:  config.Media,
+		Logger:  logger,
 	})
 	if err != nil {
 		return nil, err
@@ -85,5 +206,31 @@ func NewUsecases(
 		extras:       extrasUsecase,
 		verification: verificationUsecase,
 		events:       eventsUsecase,
+		calendar:     calendarUsecase,
+		outbox:       outboxUsecase,
+		templates:    templatesUsecase,
+		reviews:      reviewsUsecase,
+		admin:        adminUsecase,
+		booking:      bookingUsecase,
+		guests:       guestsUsecase,
+		loyalty:      loyaltyUsecase,
+		media:        mediaUsecase,
 	}, nil
 }
+
+// NewTemplates is built ahead of the other usecases: the notification channels render
+// their texts through it.
+func NewTemplates(logger *slog.Logger, config *configuration.Config, repo *Registry) (*usecases.Templates, error) {
+	defaultTemplates, err := telegram.DefaultTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	return usecases.NewTemplates(&usecases.TemplatesDependencies{
+		Repo:           repo.Templates,
+		Defaults:       defaultTemplates,
+		DefaultLocale:  config.Templates.DefaultLocale,
+		ReloadInterval: config.Templates.ReloadInterval,
+		Logger:         logger,
+	})
+}
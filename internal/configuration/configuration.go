@@ -10,16 +10,31 @@ import (
 
 type (
 	Config struct {
-		Logger       *Logger       `yaml:"Logger"`
-		WebServer    *HttpServer   `yaml:"WebServer"`
-		AppCron      *AppCron      `yaml:"AppCron"`
-		Reservations *Reservations `yaml:"Reservations"`
-		Version      string
+		Logger        *Logger        `yaml:"Logger"`
+		WebServer     *HttpServer    `yaml:"WebServer"`
+		AppCron       *AppCron       `yaml:"AppCron"`
+		Reservations  *Reservations  `yaml:"Reservations"`
+		Calendar      *Calendar      `yaml:"Calendar"`
+		Notifications *Notifications `yaml:"Notifications"`
+		Outbox        *Outbox        `yaml:"Outbox"`
+		Templates     *Templates     `yaml:"Templates"`
+		Reviews       *Reviews       `yaml:"Reviews"`
+		Booking       *Booking       `yaml:"Booking"`
+		Verification  *Verification  `yaml:"Verification"`
+		Guests        *Guests        `yaml:"Guests"`
+		Loyalty       *Loyalty       `yaml:"Loyalty"`
+		Media         *Media         `yaml:"Media"`
+		Version       string
 	}
 
 	AppCron struct {
 		UpdateReservationsStatuses CronConfig
 		GetForReminder             CronConfig
+		SyncExternalCalendars      CronConfig
+		ProcessOutbox              CronConfig
+		RequestFeedback            CronConfig
+		CleanupVerifications       CronConfig
+		AnonymizeInactiveGuests    CronConfig
 	}
 
 	CronConfig struct {
@@ -30,9 +45,113 @@ type (
 		Level slog.Level `yaml:"Level"`
 	}
 
+	Calendar struct {
+		FetchTimeout time.Duration
+	}
+
+	// Notifications lists channels in priority order; guest preferences go first
+	// and GuestChannels is the fallback after them.
+	Notifications struct {
+		GuestChannels []string
+		AdminChannels []string
+	}
+
+	Templates struct {
+		DefaultLocale  string
+		ReloadInterval time.Duration
+	}
+
+	// Reviews: the guest is asked to rate the stay FeedbackDelay after check-out,
+	// stays older than FeedbackWindow are skipped.
+	Reviews struct {
+		FeedbackDelay  time.Duration
+		FeedbackWindow time.Duration
+	}
+
+	// Booking: a bot booking left untouched for SessionTTL starts over,
+	// MaxGuests limits the guest count buttons.
+	Booking struct {
+		SessionTTL time.Duration
+		MaxGuests  int
+	}
+
+	// Verification: links live for TTL. A chat sending MaxChatAttempts unknown tokens is
+	// locked out for Lockout, every next lockout lasts twice as long up to MaxLockout;
+	// counters idle for Window are forgotten. A pending token sent MaxLinkAttempts times
+	// by locked out chats is revoked. Finished verifications are deleted after Retention,
+	// the cleanup job logs the funnel of the last FunnelWindow.
+	Verification struct {
+		TTL             time.Duration
+		Window          time.Duration
+		MaxChatAttempts int
+		MaxLinkAttempts int
+		Lockout         time.Duration
+		MaxLockout      time.Duration
+		Retention       time.Duration
+		FunnelWindow    time.Duration
+	}
+
+	// Guests without a stay or a new reservation for InactiveAfter are anonymized,
+	// at most AnonymizeBatch per run.
+	Guests struct {
+		InactiveAfter  time.Duration
+		AnonymizeBatch int
+	}
+
+	// Loyalty: a guest reaches a tier with MinStays checked out stays and MinSpend spent
+	// on them; the best reached tier's Discount percent is taken off new reservations.
+	Loyalty struct {
+		Tiers []LoyaltyTier
+	}
+
+	LoyaltyTier struct {
+		Name     string
+		MinStays int
+		MinSpend int
+		Discount int
+	}
+
+	// Media: uploads over MaxSize bytes or MaxPixels pixels are rejected. Every upload gets
+	// a JPEG copy (PNG when transparent) per variant, scaled down to its Width.
+	Media struct {
+		MaxSize     int
+		MaxPixels   int
+		JPEGQuality int
+		Variants    []MediaVariant
+	}
+
+	MediaVariant struct {
+		Name  string
+		Width int
+	}
+
+	Outbox struct {
+		BatchSize   int
+		MaxAttempts int
+		BaseBackoff time.Duration
+		MaxBackoff  time.Duration
+		Lease       time.Duration
+	}
+
 	Reservations struct {
-		PriceCoefficients     []PriceCoefficient
-		NotificationThreshold int
+		PriceCoefficients []PriceCoefficient
+		ReminderStages    []ReminderStage
+	}
+
+	// ReminderStage is sent DaysBefore days before the Anchor date (check_in or check_out)
+	// at At local time. Name identifies the stage in the sent-reminders log.
+	ReminderStage struct {
+		Name       string
+		Anchor     string
+		DaysBefore int
+		At         time.Duration // since midnight
+	}
+
+	ReminderStageTemp struct {
+		Name       string
+		Anchor     string
+		DaysBefore int
+		At         string
 	}
 
 	PriceCoefficient struct {
@@ -62,8 +181,8 @@ func NewConfig() (*Config, error) {
 	var (
 		conf Config
 		temp struct {
-			NotificationThreshold int
-			PriceCoefficients     []PriceCoefficientTemp
+			PriceCoefficients []PriceCoefficientTemp
+			ReminderStages    []ReminderStageTemp
 		}
 	)
 
@@ -91,6 +210,56 @@ func NewConfig() (*Config, error) {
 		return nil, errorspkg.NewErrReadConfigViper("AppCron", err)
 	}
 
+	err = viperNew.UnmarshalKey("Calendar", &conf.Calendar)
+	if err != nil {
+		return nil, errorspkg.NewErrReadConfigViper("Calendar", err)
+	}
+
+	err = viperNew.UnmarshalKey("Notifications", &conf.Notifications)
+	if err != nil {
+		return nil, errorspkg.NewErrReadConfigViper("Notifications", err)
+	}
+
+	err = viperNew.UnmarshalKey("Outbox", &conf.Outbox)
+	if err != nil {
+		return nil, errorspkg.NewErrReadConfigViper("Outbox", err)
+	}
+
+	err = viperNew.UnmarshalKey("Templates", &conf.Templates)
+	if err != nil {
+		return nil, errorspkg.NewErrReadConfigViper("Templates", err)
+	}
+
+	err = viperNew.UnmarshalKey("Reviews", &conf.Reviews)
+	if err != nil {
+		return nil, errorspkg.NewErrReadConfigViper("Reviews", err)
+	}
+
+	err = viperNew.UnmarshalKey("Booking", &conf.Booking)
+	if err != nil {
+		return nil, errorspkg.NewErrReadConfigViper("Booking", err)
+	}
+
+	err = viperNew.UnmarshalKey("Verification", &conf.Verification)
+	if err != nil {
+		return nil, errorspkg.NewErrReadConfigViper("Verification", err)
+	}
+
+	err = viperNew.UnmarshalKey("Guests", &conf.Guests)
+	if err != nil {
+		return nil, errorspkg.NewErrReadConfigViper("Guests", err)
+	}
+
+	err = viperNew.UnmarshalKey("Loyalty", &conf.Loyalty)
+	if err != nil {
+		return nil, errorspkg.NewErrReadConfigViper("Loyalty", err)
+	}
+
+	err = viperNew.UnmarshalKey("Media", &conf.Media)
+	if err != nil {
+		return nil, errorspkg.NewErrReadConfigViper("Media", err)
+	}
+
 	err = viperNew.UnmarshalKey("Reservations", &temp)
 	if err != nil {
 		return nil, errorspkg.NewErrReadConfigViper("PriceCoefficients", err)
@@ -101,9 +270,14 @@ func NewConfig() (*Config, error) {
 		return nil, errorspkg.NewErrReadConfigViper("PriceCoefficients", err)
 	}
 
+	stages, err := parseReminderStages(temp.ReminderStages)
+	if err != nil {
+		return nil, errorspkg.NewErrReadConfigViper("ReminderStages", err)
+	}
+
 	conf.Reservations = &Reservations{
-		NotificationThreshold: temp.NotificationThreshold,
-		PriceCoefficients:     pc,
+		PriceCoefficients: pc,
+		ReminderStages:    stages,
 	}
 
 	return &conf, nil
@@ -128,3 +302,20 @@ func parseDate(req []PriceCoefficientTemp) ([]PriceCoefficient, error) {
 	}
 	return result, nil
 }
+
+func parseReminderStages(req []ReminderStageTemp) ([]ReminderStage, error) {
+	result := make([]ReminderStage, 0, len(req))
+	for _, st := range req {
+		at, pErr := time.Parse("15:04", st.At)
+		if pErr != nil {
+			return nil, pErr
+		}
+		result = append(result, ReminderStage{
+			Name:       st.Name,
+			Anchor:     st.Anchor,
+			DaysBefore: st.DaysBefore,
+			At:         time.Duration(at.Hour())*time.Hour + time.Duration(at.Minute())*time.Minute,
+		})
+	}
+	return result, nil
+}
@@ -8,6 +8,11 @@ import (
 type Credentials struct {
 	Postgres    Postgres
 	TelegramBot TelegramBot
+	SMTP        SMTP
+	SMS         SMS
+	Webhook     Webhook
+	API         API
+	Storage     Storage
 }
 
 type Postgres struct {
@@ -23,9 +28,72 @@ type Postgres struct {
 	JitterConnection   time.Duration `yaml:"JitterConnection"`
 }
 
+// API.AdminToken guards the admin endpoints, which are closed while it is empty.
+type API struct {
+	AdminToken string `yaml:"AdminToken"`
+}
+
+// TelegramBot uses long polling while WebhookURL is empty, otherwise Telegram pushes
+// updates to WebhookURL, which must point at /telegram/webhook of this service.
 type TelegramBot struct {
-	Token        string  `yaml:"Token"`
-	AdminChatIDs []int64 `yaml:"AdminChatIDs"`
+	Token         string  `yaml:"Token"`
+	AdminChatIDs  []int64 `yaml:"AdminChatIDs"`
+	WebhookURL    string  `yaml:"WebhookURL"`
+	WebhookSecret string  `yaml:"WebhookSecret"`
+}
+
+// SMTP is optional: email notifications are disabled while Host is empty.
+type SMTP struct {
+	Host        string        `yaml:"Host"`
+	Port        string        `yaml:"Port"`
+	Username    string        `yaml:"Username"`
+	Password    string        `yaml:"Password"`
+	From        string        `yaml:"From"`
+	AdminEmails []string      `yaml:"AdminEmails"`
+	ImplicitTLS bool          `yaml:"ImplicitTLS"`
+	Timeout     time.Duration `yaml:"Timeout"`
+}
+
+// SMS is optional: SMS notifications are disabled while APIKey is empty.
+type SMS struct {
+	BaseURL     string        `yaml:"BaseURL"`
+	APIKey      string        `yaml:"APIKey"`
+	Sender      string        `yaml:"Sender"`
+	AdminPhones []string      `yaml:"AdminPhones"`
+	Timeout     time.Duration `yaml:"Timeout"`
+}
+
+// Webhook is optional: webhook notifications are disabled while URLs is empty.
+type Webhook struct {
+	URLs    []string      `yaml:"URLs"`
+	Secret  string        `yaml:"Secret"`
+	Timeout time.Duration `yaml:"Timeout"`
+}
+
+// Storage keeps uploaded media on the local disk (Backend: local) or in an S3-compatible
+// bucket (Backend: s3).
+type Storage struct {
+	Backend string       `yaml:"Backend"`
+	Local   LocalStorage `yaml:"Local"`
+	S3      S3Storage    `yaml:"S3"`
+}
+
+// LocalStorage files are served by this service under /uploads/, BaseURL must point there.
+type LocalStorage struct {
+	Dir     string `yaml:"Dir"`
+	BaseURL string `yaml:"BaseURL"`
+}
+
+// S3Storage uses path-style requests, so MinIO works as well. Objects must be readable
+// at PublicURL, which defaults to Endpoint/Bucket.
+type S3Storage struct {
+	Endpoint  string        `yaml:"Endpoint"`
+	Region    string        `yaml:"Region"`
+	Bucket    string        `yaml:"Bucket"`
+	AccessKey string        `yaml:"AccessKey"`
+	SecretKey string        `yaml:"SecretKey"`
+	PublicURL string        `yaml:"PublicURL"`
+	Timeout   time.Duration `yaml:"Timeout"`
 }
 
 func NewCredentials() (*Credentials, error) {
@@ -51,5 +119,30 @@ func NewCredentials() (*Credentials, error) {
 		return nil, err
 	}
 
+	err = viperNew.UnmarshalKey("SMTP", &creds.SMTP)
+	if err != nil {
+		return nil, err
+	}
+
+	err = viperNew.UnmarshalKey("SMS", &creds.SMS)
+	if err != nil {
+		return nil, err
+	}
+
+	err = viperNew.UnmarshalKey("Webhook", &creds.Webhook)
+	if err != nil {
+		return nil, err
+	}
+
+	err = viperNew.UnmarshalKey("API", &creds.API)
+	if err != nil {
+		return nil, err
+	}
+
+	err = viperNew.UnmarshalKey("Storage", &creds.Storage)
+	if err != nil {
+		return nil, err
+	}
+
 	return &creds, nil
 }
@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"context"
+	"github.com/calyrexx/QuietGrooveBackend/internal/api/handlers"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+)
+
+type IAdminsUseCase interface {
+	GetAdmins(ctx context.Context) ([]entities.BotAdmin, error)
+	AddAdmin(ctx context.Context, admin entities.BotAdmin) error
+	DeleteAdmin(ctx context.Context, tgID int64) error
+}
+
+type AdminsDependencies struct {
+	UseCase IAdminsUseCase
+}
+
+type Admins struct {
+	useCase IAdminsUseCase
+}
+
+func NewAdmins(d *AdminsDependencies) (*Admins, error) {
+	if d.UseCase == nil {
+		return nil, errorspkg.NewErrConstructorDependencies("Admins Controller", "usecase", "nil")
+	}
+	return &Admins{
+		useCase: d.UseCase,
+	}, nil
+}
+
+func (c *Admins) GetAll(ctx context.Context) ([]handlers.BotAdmin, error) {
+	res, err := c.useCase.GetAdmins(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	admins := make([]handlers.BotAdmin, 0, len(res))
+	for _, a := range res {
+		admins = append(admins, handlers.BotAdmin{
+			TgID:      a.TgID,
+			Name:      a.Name,
+			CreatedAt: a.CreatedAt,
+		})
+	}
+	return admins, nil
+}
+
+func (c *Admins) Add(ctx context.Context, admin handlers.BotAdmin) error {
+	return c.useCase.AddAdmin(ctx, entities.BotAdmin{
+		TgID: admin.TgID,
+		Name: admin.Name,
+	})
+}
+
+func (c *Admins) Delete(ctx context.Context, tgID int64) error {
+	return c.useCase.DeleteAdmin(ctx, tgID)
+}
@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"context"
+	"github.com/calyrexx/QuietGrooveBackend/internal/api/handlers"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+)
+
+type ICalendarUseCase interface {
+	Export(ctx context.Context, houseID int, token string) ([]byte, error)
+	RegenerateToken(ctx context.Context, houseID int) (string, error)
+	GetSources(ctx context.Context, houseID int) ([]entities.CalendarSource, error)
+	AddSource(ctx context.Context, source entities.CalendarSource) (entities.CalendarSource, error)
+	DeleteSource(ctx context.Context, houseID, sourceID int) error
+}
+
+type CalendarDependencies struct {
+	UseCase ICalendarUseCase
+}
+
+type Calendar struct {
+	useCase ICalendarUseCase
+}
+
+func NewCalendar(d *CalendarDependencies) (*Calendar, error) {
+	if d.UseCase == nil {
+		return nil, errorspkg.NewErrConstructorDependencies("Calendar Controller", "usecase", "nil")
+	}
+	return &Calendar{
+		useCase: d.UseCase,
+	}, nil
+}
+
+func (c *Calendar) Export(ctx context.Context, houseID int, token string) ([]byte, error) {
+	return c.useCase.Export(ctx, houseID, token)
+}
+
+func (c *Calendar) RegenerateToken(ctx context.Context, houseID int) (string, error) {
+	return c.useCase.RegenerateToken(ctx, houseID)
+}
+
+func (c *Calendar) GetSources(ctx context.Context, houseID int) ([]handlers.CalendarSource, error) {
+	res, err := c.useCase.GetSources(ctx, houseID)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make([]handlers.CalendarSource, 0, len(res))
+	for _, src := range res {
+		sources = append(sources, c.convertEntityToSource(src))
+	}
+	return sources, nil
+}
+
+func (c *Calendar) AddSource(ctx context.Context, source handlers.CalendarSource) (handlers.CalendarSource, error) {
+	res, err := c.useCase.AddSource(ctx, entities.CalendarSource{
+		HouseID: source.HouseID,
+		Name:    source.Name,
+		URL:     source.URL,
+	})
+	if err != nil {
+		return handlers.CalendarSource{}, err
+	}
+
+	return c.convertEntityToSource(res), nil
+}
+
+func (c *Calendar) DeleteSource(ctx context.Context, houseID, sourceID int) error {
+	return c.useCase.DeleteSource(ctx, houseID, sourceID)
+}
+
+func (c *Calendar) convertEntityToSource(src entities.CalendarSource) handlers.CalendarSource {
+	return handlers.CalendarSource{
+		ID:           src.ID,
+		HouseID:      src.HouseID,
+		Name:         src.Name,
+		URL:          src.URL,
+		LastSyncedAt: src.LastSyncedAt,
+	}
+}
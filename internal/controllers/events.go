@@ -5,10 +5,18 @@ import (
 	"github.com/calyrexx/QuietGrooveBackend/internal/api/handlers"
 	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
 	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/google/uuid"
+	"time"
 )
 
 type IEventsUseCase interface {
 	NewApplication(ctx context.Context, req entities.NewApplication) error
+	Applications(ctx context.Context, status string) ([]entities.EventApplication, error)
+	UpdateApplication(ctx context.Context, id int, upd entities.EventApplicationUpdate) (entities.EventApplication, error)
+	CreateBooking(ctx context.Context, b entities.EventBooking) (entities.EventBooking, error)
+	Bookings(ctx context.Context, from, to time.Time) ([]entities.EventBooking, error)
+	Booking(ctx context.Context, bookingUUID uuid.UUID) (entities.EventBooking, error)
+	CancelBooking(ctx context.Context, bookingUUID uuid.UUID) error
 }
 
 type EventsDependencies struct {
@@ -35,6 +43,109 @@ func (c *Events) NewApplication(ctx context.Context, req handlers.EventsNewAppli
 	return c.useCase.NewApplication(ctx, request)
 }
 
+func (c *Events) Applications(ctx context.Context, status string) ([]handlers.EventApplication, error) {
+	res, err := c.useCase.Applications(ctx, status)
+	if err != nil {
+		return nil, err
+	}
+
+	apps := make([]handlers.EventApplication, 0, len(res))
+	for _, app := range res {
+		apps = append(apps, applicationToHandler(app))
+	}
+	return apps, nil
+}
+
+func (c *Events) UpdateApplication(
+	ctx context.Context,
+	id int,
+	req handlers.EventApplicationUpdate,
+) (handlers.EventApplication, error) {
+	upd := entities.EventApplicationUpdate{
+		Notes:   req.Notes,
+		Manager: req.Manager,
+	}
+	if req.Status != nil {
+		status := entities.ApplicationStatus(*req.Status)
+		upd.Status = &status
+	}
+
+	res, err := c.useCase.UpdateApplication(ctx, id, upd)
+	if err != nil {
+		return handlers.EventApplication{}, err
+	}
+	return applicationToHandler(res), nil
+}
+
+func (c *Events) CreateBooking(ctx context.Context, req handlers.CreateEventBooking) (handlers.EventBooking, error) {
+	checkIn, err := time.Parse(time.DateOnly, req.CheckIn)
+	if err != nil {
+		return handlers.EventBooking{}, err
+	}
+	checkOut, err := time.Parse(time.DateOnly, req.CheckOut)
+	if err != nil {
+		return handlers.EventBooking{}, err
+	}
+
+	res, err := c.useCase.CreateBooking(ctx, entities.EventBooking{
+		ApplicationID: req.ApplicationID,
+		Title:         req.Title,
+		ContactName:   req.ContactName,
+		ContactPhone:  req.ContactPhone,
+		CheckIn:       checkIn,
+		CheckOut:      checkOut,
+		GuestsCount:   req.GuestsCount,
+		HouseIDs:      req.HouseIDs,
+		BathhouseIDs:  req.BathhouseIDs,
+		TotalPrice:    req.TotalPrice,
+		Deposit:       req.Deposit,
+		Notes:         req.Notes,
+	})
+	if err != nil {
+		return handlers.EventBooking{}, err
+	}
+	return eventBookingToHandler(res), nil
+}
+
+// Bookings parses the optional from and to dates, YYYY-MM-DD.
+func (c *Events) Bookings(ctx context.Context, from, to string) ([]handlers.EventBooking, error) {
+	var fromDate, toDate time.Time
+	var err error
+	if from != "" {
+		if fromDate, err = time.Parse(time.DateOnly, from); err != nil {
+			return nil, err
+		}
+	}
+	if to != "" {
+		if toDate, err = time.Parse(time.DateOnly, to); err != nil {
+			return nil, err
+		}
+	}
+
+	res, err := c.useCase.Bookings(ctx, fromDate, toDate)
+	if err != nil {
+		return nil, err
+	}
+
+	bookings := make([]handlers.EventBooking, 0, len(res))
+	for _, b := range res {
+		bookings = append(bookings, eventBookingToHandler(b))
+	}
+	return bookings, nil
+}
+
+func (c *Events) Booking(ctx context.Context, bookingUUID uuid.UUID) (handlers.EventBooking, error) {
+	res, err := c.useCase.Booking(ctx, bookingUUID)
+	if err != nil {
+		return handlers.EventBooking{}, err
+	}
+	return eventBookingToHandler(res), nil
+}
+
+func (c *Events) CancelBooking(ctx context.Context, bookingUUID uuid.UUID) error {
+	return c.useCase.CancelBooking(ctx, bookingUUID)
+}
+
 func (c *Events) convertNewApplication(req handlers.EventsNewApplication) entities.NewApplication {
 	return entities.NewApplication{
 		Name:        req.Name,
@@ -43,3 +154,40 @@ func (c *Events) convertNewApplication(req handlers.EventsNewApplication) entiti
 		GuestsCount: req.GuestsCount,
 	}
 }
+
+func applicationToHandler(app entities.EventApplication) handlers.EventApplication {
+	return handlers.EventApplication{
+		ID:          app.ID,
+		Name:        app.Name,
+		Phone:       app.Phone,
+		CheckIn:     app.CheckIn,
+		GuestsCount: app.GuestsCount,
+		Status:      string(app.Status),
+		Notes:       app.Notes,
+		Manager:     app.Manager,
+		CreatedAt:   app.CreatedAt,
+		UpdatedAt:   app.UpdatedAt,
+	}
+}
+
+func eventBookingToHandler(b entities.EventBooking) handlers.EventBooking {
+	return handlers.EventBooking{
+		ID:             b.UUID.String(),
+		ApplicationID:  b.ApplicationID,
+		Title:          b.Title,
+		ContactName:    b.ContactName,
+		ContactPhone:   b.ContactPhone,
+		CheckIn:        b.CheckIn.Format(time.DateOnly),
+		CheckOut:       b.CheckOut.Format(time.DateOnly),
+		GuestsCount:    b.GuestsCount,
+		HouseIDs:       b.HouseIDs,
+		HouseNames:     b.HouseNames,
+		BathhouseIDs:   b.BathhouseIDs,
+		BathhouseNames: b.BathhouseNames,
+		TotalPrice:     b.TotalPrice,
+		Deposit:        b.Deposit,
+		Status:         string(b.Status),
+		Notes:          b.Notes,
+		CreatedAt:      b.CreatedAt,
+	}
+}
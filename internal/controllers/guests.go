@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"context"
+	"github.com/calyrexx/QuietGrooveBackend/internal/api/handlers"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/calyrexx/QuietGrooveBackend/internal/usecases"
+	"github.com/google/uuid"
+)
+
+type IGuestsUseCase interface {
+	Search(ctx context.Context, query string) ([]entities.GuestProfile, error)
+	Merge(ctx context.Context, targetUUID, sourceUUID uuid.UUID) (entities.GuestMerge, error)
+	Export(ctx context.Context, guestUUID uuid.UUID) (usecases.GuestExport, error)
+	Erase(ctx context.Context, guestUUID uuid.UUID) error
+}
+
+type GuestsDependencies struct {
+	UseCase IGuestsUseCase
+}
+
+type Guests struct {
+	useCase IGuestsUseCase
+}
+
+func NewGuests(d *GuestsDependencies) (*Guests, error) {
+	if d.UseCase == nil {
+		return nil, errorspkg.NewErrConstructorDependencies("Guests Controller", "usecase", "nil")
+	}
+	return &Guests{
+		useCase: d.UseCase,
+	}, nil
+}
+
+func (c *Guests) Search(ctx context.Context, query string) ([]handlers.GuestProfile, error) {
+	res, err := c.useCase.Search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	guests := make([]handlers.GuestProfile, 0, len(res))
+	for _, g := range res {
+		guests = append(guests, guestToHandler(g))
+	}
+	return guests, nil
+}
+
+func (c *Guests) Merge(ctx context.Context, targetUUID, sourceUUID uuid.UUID) (handlers.GuestMerge, error) {
+	res, err := c.useCase.Merge(ctx, targetUUID, sourceUUID)
+	if err != nil {
+		return handlers.GuestMerge{}, err
+	}
+
+	return handlers.GuestMerge{
+		Guest:         guestToHandler(res.Guest),
+		Reservations:  res.Reservations,
+		Reviews:       res.Reviews,
+		Notifications: res.Notifications,
+	}, nil
+}
+
+func (c *Guests) Export(ctx context.Context, guestUUID uuid.UUID) (usecases.GuestExport, error) {
+	return c.useCase.Export(ctx, guestUUID)
+}
+
+func (c *Guests) Erase(ctx context.Context, guestUUID uuid.UUID) error {
+	return c.useCase.Erase(ctx, guestUUID)
+}
+
+func guestToHandler(g entities.GuestProfile) handlers.GuestProfile {
+	return handlers.GuestProfile{
+		ID:        g.UUID.String(),
+		Name:      g.Name,
+		Email:     g.Email,
+		Phone:     g.Phone,
+		TgID:      g.TgID,
+		Locale:    g.Locale,
+		CreatedAt: g.CreatedAt,
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/calyrexx/QuietGrooveBackend/internal/api/handlers"
 	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
 	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"strings"
 )
 
 type IHousesUseCase interface {
@@ -12,6 +13,9 @@ type IHousesUseCase interface {
 	Add(ctx context.Context, houses []entities.House) error
 	Update(ctx context.Context, house entities.House) error
 	Delete(ctx context.Context, houseID int) error
+	UpdateAmenities(ctx context.Context, houseID int, amenities entities.HouseAmenities) error
+	UpdateTags(ctx context.Context, houseID int, tags []string) error
+	GetTags(ctx context.Context) ([]entities.HouseTag, error)
 }
 
 type HousesDependencies struct {
@@ -56,6 +60,30 @@ func (c *Houses) Delete(ctx context.Context, houseID int) error {
 	return c.useCase.Delete(ctx, houseID)
 }
 
+func (c *Houses) UpdateAmenities(ctx context.Context, houseID int, amenities handlers.HouseAmenities) error {
+	return c.useCase.UpdateAmenities(ctx, houseID, c.convertAmenitiesToEntity(amenities))
+}
+
+func (c *Houses) UpdateTags(ctx context.Context, houseID int, req handlers.HouseTags) error {
+	return c.useCase.UpdateTags(ctx, houseID, req.Tags)
+}
+
+func (c *Houses) GetTags(ctx context.Context) ([]handlers.HouseTag, error) {
+	tags, err := c.useCase.GetTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]handlers.HouseTag, 0, len(tags))
+	for _, tag := range tags {
+		res = append(res, handlers.HouseTag{
+			Name:   tag.Name,
+			Houses: tag.Houses,
+		})
+	}
+	return res, nil
+}
+
 func (c *Houses) convertEntitiesToHouses(entities []entities.House) []handlers.House {
 	res := make([]handlers.House, 0, len(entities))
 	for _, entity := range entities {
@@ -71,12 +99,34 @@ func (c *Houses) convertEntityToHouse(entity entities.House) handlers.House {
 		Description:   entity.Description,
 		Capacity:      entity.Capacity,
 		BasePrice:     entity.BasePrice,
+		CoverImage:    entity.CoverImage,
 		Images:        entity.Images,
 		CheckInFrom:   entity.CheckInFrom,
 		CheckOutUntil: entity.CheckOutUntil,
+		Directions:    entity.Directions,
+		Amenities:     c.convertEntityToAmenities(entity.Amenities),
+		Tags:          entity.Tags,
+		Rating:        entity.Rating,
+		ReviewsCount:  entity.ReviewsCount,
+		Reviews:       c.convertEntitiesToHouseReviews(entity.Reviews),
 	}
 }
 
+// convertEntitiesToHouseReviews publishes only the guest's first name.
+func (c *Houses) convertEntitiesToHouseReviews(reviews []entities.Review) []handlers.HouseReview {
+	res := make([]handlers.HouseReview, 0, len(reviews))
+	for _, rv := range reviews {
+		name, _, _ := strings.Cut(strings.TrimSpace(rv.GuestName), " ")
+		res = append(res, handlers.HouseReview{
+			GuestName: name,
+			Rating:    rv.Rating,
+			Comment:   rv.Comment,
+			CreatedAt: rv.CreatedAt,
+		})
+	}
+	return res
+}
+
 func (c *Houses) convertHousesToEntity(houses []handlers.House) []entities.House {
 	resp := make([]entities.House, 0, len(houses))
 	for _, house := range houses {
@@ -86,10 +136,50 @@ func (c *Houses) convertHousesToEntity(houses []handlers.House) []entities.House
 			Description:   house.Description,
 			Capacity:      house.Capacity,
 			BasePrice:     house.BasePrice,
+			CoverImage:    house.CoverImage,
 			Images:        house.Images,
 			CheckInFrom:   house.CheckInFrom,
 			CheckOutUntil: house.CheckOutUntil,
+			Directions:    house.Directions,
+			Amenities:     c.convertAmenitiesToEntity(house.Amenities),
+			Tags:          house.Tags,
 		})
 	}
 	return resp
 }
+
+func (c *Houses) convertEntityToAmenities(amenities entities.HouseAmenities) handlers.HouseAmenities {
+	beds := make([]handlers.HouseBed, 0, len(amenities.Beds))
+	for _, bed := range amenities.Beds {
+		beds = append(beds, handlers.HouseBed{
+			Kind:  string(bed.Kind),
+			Count: bed.Count,
+		})
+	}
+	return handlers.HouseAmenities{
+		Sauna:       amenities.Sauna,
+		PetsAllowed: amenities.PetsAllowed,
+		Kitchen:     amenities.Kitchen,
+		Fireplace:   amenities.Fireplace,
+		Beds:        beds,
+		Area:        amenities.Area,
+	}
+}
+
+func (c *Houses) convertAmenitiesToEntity(amenities handlers.HouseAmenities) entities.HouseAmenities {
+	beds := make([]entities.HouseBed, 0, len(amenities.Beds))
+	for _, bed := range amenities.Beds {
+		beds = append(beds, entities.HouseBed{
+			Kind:  entities.BedKind(bed.Kind),
+			Count: bed.Count,
+		})
+	}
+	return entities.HouseAmenities{
+		Sauna:       amenities.Sauna,
+		PetsAllowed: amenities.PetsAllowed,
+		Kitchen:     amenities.Kitchen,
+		Fireplace:   amenities.Fireplace,
+		Beds:        beds,
+		Area:        amenities.Area,
+	}
+}
@@ -0,0 +1,89 @@
+package controllers
+
+import (
+	"context"
+	"github.com/calyrexx/QuietGrooveBackend/internal/api/handlers"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+)
+
+type IMediaUseCase interface {
+	Upload(ctx context.Context, owner entities.MediaOwner, ownerID int, data []byte) (entities.Media, error)
+	List(ctx context.Context, owner entities.MediaOwner, ownerID int) ([]entities.Media, error)
+	Reorder(ctx context.Context, owner entities.MediaOwner, ownerID int, ids []int) error
+	Delete(ctx context.Context, id int) error
+}
+
+type MediaDependencies struct {
+	UseCase IMediaUseCase
+}
+
+type Media struct {
+	useCase IMediaUseCase
+}
+
+func NewMedia(d *MediaDependencies) (*Media, error) {
+	if d.UseCase == nil {
+		return nil, errorspkg.NewErrConstructorDependencies("Media UseCase", "whole", "nil")
+	}
+
+	return &Media{
+		useCase: d.UseCase,
+	}, nil
+}
+
+func (c *Media) Upload(ctx context.Context, owner string, ownerID int, data []byte) (handlers.MediaFile, error) {
+	media, err := c.useCase.Upload(ctx, entities.MediaOwner(owner), ownerID, data)
+	if err != nil {
+		return handlers.MediaFile{}, err
+	}
+
+	return mediaToHandler(media), nil
+}
+
+func (c *Media) List(ctx context.Context, owner string, ownerID int) ([]handlers.MediaFile, error) {
+	res, err := c.useCase.List(ctx, entities.MediaOwner(owner), ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	media := make([]handlers.MediaFile, 0, len(res))
+	for _, m := range res {
+		media = append(media, mediaToHandler(m))
+	}
+	return media, nil
+}
+
+func (c *Media) Reorder(ctx context.Context, owner string, ownerID int, req handlers.MediaOrder) error {
+	return c.useCase.Reorder(ctx, entities.MediaOwner(owner), ownerID, req.IDs)
+}
+
+func (c *Media) Delete(ctx context.Context, id int) error {
+	return c.useCase.Delete(ctx, id)
+}
+
+func mediaToHandler(m entities.Media) handlers.MediaFile {
+	variants := make([]handlers.MediaVariant, 0, len(m.Variants))
+	for _, v := range m.Variants {
+		variants = append(variants, handlers.MediaVariant{
+			Name:   v.Name,
+			URL:    v.URL,
+			Width:  v.Width,
+			Height: v.Height,
+		})
+	}
+
+	return handlers.MediaFile{
+		ID:          m.ID,
+		Owner:       string(m.Owner),
+		OwnerID:     m.OwnerID,
+		Position:    m.Position,
+		ContentType: m.ContentType,
+		Size:        m.Size,
+		Width:       m.Width,
+		Height:      m.Height,
+		URL:         m.URL,
+		Variants:    variants,
+		CreatedAt:   m.CreatedAt,
+	}
+}
@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"context"
+	"github.com/calyrexx/QuietGrooveBackend/internal/api/handlers"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+)
+
+type IOutboxUseCase interface {
+	GetFailed(ctx context.Context) ([]entities.OutboxMessage, error)
+	Retry(ctx context.Context, id int64) error
+}
+
+type OutboxDependencies struct {
+	UseCase IOutboxUseCase
+}
+
+type Outbox struct {
+	useCase IOutboxUseCase
+}
+
+func NewOutbox(d *OutboxDependencies) (*Outbox, error) {
+	if d.UseCase == nil {
+		return nil, errorspkg.NewErrConstructorDependencies("Outbox Controller", "usecase", "nil")
+	}
+	return &Outbox{
+		useCase: d.UseCase,
+	}, nil
+}
+
+func (c *Outbox) GetFailed(ctx context.Context) ([]handlers.OutboxMessage, error) {
+	res, err := c.useCase.GetFailed(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]handlers.OutboxMessage, 0, len(res))
+	for _, m := range res {
+		messages = append(messages, handlers.OutboxMessage{
+			ID:        m.ID,
+			Kind:      m.Kind,
+			Attempts:  m.Attempts,
+			LastError: m.LastError,
+			CreatedAt: m.CreatedAt,
+			Payload:   m.Payload,
+		})
+	}
+	return messages, nil
+}
+
+func (c *Outbox) Retry(ctx context.Context, id int64) error {
+	return c.useCase.Retry(ctx, id)
+}
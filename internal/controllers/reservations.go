@@ -6,12 +6,14 @@ import (
 	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
 	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
 	"github.com/calyrexx/QuietGrooveBackend/internal/usecases"
+	"strings"
 	"time"
 )
 
 type IReservationsUseCase interface {
 	CreateReservation(ctx context.Context, req usecases.CreateReservationRequest) (entities.Reservation, error)
 	GetAvailableHouses(ctx context.Context, req entities.GetAvailableHouses) ([]usecases.GetAvailableHousesResponse, error)
+	GetAlternatives(ctx context.Context, req entities.FlexibleSearch) ([]usecases.AlternativeStay, error)
 }
 
 type ReservationsDependencies struct {
@@ -45,6 +47,15 @@ func (c *Reservations) GetAvailableHouses(ctx context.Context, req handlers.GetA
 	return response, nil
 }
 
+func (c *Reservations) GetAlternatives(ctx context.Context, req handlers.FlexibleSearch) ([]usecases.AlternativeStay, error) {
+	request, err := c.convertFlexibleSearchReq(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.useCase.GetAlternatives(ctx, request)
+}
+
 func (c *Reservations) CreateReservation(ctx context.Context, req handlers.CreateReservation) (entities.Reservation, error) {
 	request, err := c.convertCreateReservation(req)
 	if err != nil {
@@ -60,19 +71,59 @@ func (c *Reservations) CreateReservation(ctx context.Context, req handlers.Creat
 }
 
 func (c *Reservations) convertGetAvailableHousesReq(req handlers.GetAvailableHouses) (entities.GetAvailableHouses, error) {
+	res := c.convertHouseFilter(req)
 	in, err := time.Parse(time.DateOnly, req.CheckIn)
 	if err != nil {
-		return entities.GetAvailableHouses{}, err
+		return res, err
 	}
 	out, err := time.Parse(time.DateOnly, req.CheckOut)
 	if err != nil {
-		return entities.GetAvailableHouses{}, err
+		return res, err
+	}
+	res.CheckIn, res.CheckOut = in, out
+	return res, nil
+}
+
+// convertFlexibleSearchReq parses only the dates the mode uses.
+func (c *Reservations) convertFlexibleSearchReq(req handlers.FlexibleSearch) (entities.FlexibleSearch, error) {
+	res := entities.FlexibleSearch{
+		Mode:   entities.FlexibleMode(req.Mode),
+		Days:   req.Days,
+		Nights: req.Nights,
+		Limit:  req.Limit,
+	}
+
+	var err error
+	if res.Mode == entities.FlexibleWeekend {
+		res.Filter = c.convertHouseFilter(req.GetAvailableHouses)
+		res.Month, err = time.Parse("2006-01", req.Month)
+		return res, err
+	}
+
+	res.Filter, err = c.convertGetAvailableHousesReq(req.GetAvailableHouses)
+	return res, err
+}
+
+// convertHouseFilter converts everything but the dates, tags may be comma separated.
+func (c *Reservations) convertHouseFilter(req handlers.GetAvailableHouses) entities.GetAvailableHouses {
+	var tags []string
+	for _, param := range req.Tags {
+		for _, tag := range strings.Split(param, ",") {
+			if strings.TrimSpace(tag) != "" {
+				tags = append(tags, tag)
+			}
+		}
 	}
 	return entities.GetAvailableHouses{
-		CheckIn:     in,
-		CheckOut:    out,
-		GuestsCount: req.GuestsCount,
-	}, nil
+		GuestsCount:   req.GuestsCount,
+		Sauna:         req.Sauna,
+		PetsAllowed:   req.PetsAllowed,
+		Kitchen:       req.Kitchen,
+		Fireplace:     req.Fireplace,
+		WithBathhouse: req.Bathhouse,
+		MinArea:       req.MinArea,
+		Tags:          tags,
+	}
 }
 
 func (c *Reservations) convertCreateReservation(req handlers.CreateReservation) (usecases.CreateReservationRequest, error) {
@@ -0,0 +1,57 @@
+package controllers
+
+import (
+	"context"
+	"github.com/calyrexx/QuietGrooveBackend/internal/api/handlers"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+)
+
+type IReviewsUseCase interface {
+	GetAll(ctx context.Context, status string) ([]entities.Review, error)
+	Moderate(ctx context.Context, id int, status string) error
+}
+
+type ReviewsDependencies struct {
+	UseCase IReviewsUseCase
+}
+
+type Reviews struct {
+	useCase IReviewsUseCase
+}
+
+func NewReviews(d *ReviewsDependencies) (*Reviews, error) {
+	if d.UseCase == nil {
+		return nil, errorspkg.NewErrConstructorDependencies("Reviews Controller", "usecase", "nil")
+	}
+	return &Reviews{
+		useCase: d.UseCase,
+	}, nil
+}
+
+func (c *Reviews) GetAll(ctx context.Context, status string) ([]handlers.Review, error) {
+	res, err := c.useCase.GetAll(ctx, status)
+	if err != nil {
+		return nil, err
+	}
+
+	reviews := make([]handlers.Review, 0, len(res))
+	for _, rv := range res {
+		reviews = append(reviews, handlers.Review{
+			ID:              rv.ID,
+			ReservationUUID: rv.ReservationUUID.String(),
+			HouseID:         rv.HouseID,
+			GuestName:       rv.GuestName,
+			Rating:          rv.Rating,
+			Comment:         rv.Comment,
+			Status:          string(rv.Status),
+			CreatedAt:       rv.CreatedAt,
+			ModeratedAt:     rv.ModeratedAt,
+		})
+	}
+	return reviews, nil
+}
+
+func (c *Reviews) Moderate(ctx context.Context, id int, status string) error {
+	return c.useCase.Moderate(ctx, id, status)
+}
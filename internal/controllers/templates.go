@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"context"
+	"github.com/calyrexx/QuietGrooveBackend/internal/api/handlers"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+)
+
+type ITemplatesUseCase interface {
+	GetAll(ctx context.Context) ([]entities.MessageTemplate, error)
+	Update(ctx context.Context, key, locale, body string) error
+	Reset(ctx context.Context, key, locale string) error
+	Preview(ctx context.Context, key, locale, body string) (string, error)
+}
+
+type TemplatesDependencies struct {
+	UseCase ITemplatesUseCase
+}
+
+type Templates struct {
+	useCase ITemplatesUseCase
+}
+
+func NewTemplates(d *TemplatesDependencies) (*Templates, error) {
+	if d.UseCase == nil {
+		return nil, errorspkg.NewErrConstructorDependencies("Templates Controller", "usecase", "nil")
+	}
+	return &Templates{
+		useCase: d.UseCase,
+	}, nil
+}
+
+func (c *Templates) GetAll(ctx context.Context) ([]handlers.MessageTemplate, error) {
+	res, err := c.useCase.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	templates := make([]handlers.MessageTemplate, 0, len(res))
+	for _, t := range res {
+		templates = append(templates, handlers.MessageTemplate{
+			Key:       t.Key,
+			Locale:    t.Locale,
+			Body:      t.Body,
+			IsDefault: t.IsDefault,
+			UpdatedAt: t.UpdatedAt,
+		})
+	}
+	return templates, nil
+}
+
+func (c *Templates) Update(ctx context.Context, key, locale, body string) error {
+	return c.useCase.Update(ctx, key, locale, body)
+}
+
+func (c *Templates) Reset(ctx context.Context, key, locale string) error {
+	return c.useCase.Reset(ctx, key, locale)
+}
+
+func (c *Templates) Preview(ctx context.Context, key, locale, body string) (string, error) {
+	return c.useCase.Preview(ctx, key, locale, body)
+}
@@ -2,11 +2,18 @@ package controllers
 
 import (
 	"context"
+	"github.com/calyrexx/QuietGrooveBackend/internal/api/handlers"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
 	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
 )
 
 type IVerificationUseCase interface {
-	Generate(ctx context.Context, email, phone, name string) (string, error)
+	Generate(
+		ctx context.Context,
+		email, phone, name string,
+		channels []entities.NotificationChannel,
+	) (entities.VerificationLink, error)
+	Status(ctx context.Context, id string) (entities.Verification, error)
 }
 
 type VerificationDependencies struct {
@@ -27,6 +34,38 @@ func NewVerification(d *VerificationDependencies) (*Verification, error) {
 	}, nil
 }
 
-func (c *Verification) Generate(ctx context.Context, email, phone, name string) (string, error) {
-	return c.useCase.Generate(ctx, email, phone, name)
+func (c *Verification) Generate(
+	ctx context.Context,
+	email, phone, name string,
+	channels []string,
+) (handlers.VerificationLink, error) {
+	notifyChannels := make([]entities.NotificationChannel, 0, len(channels))
+	for _, ch := range channels {
+		notifyChannels = append(notifyChannels, entities.NotificationChannel(ch))
+	}
+
+	link, err := c.useCase.Generate(ctx, email, phone, name, notifyChannels)
+	if err != nil {
+		return handlers.VerificationLink{}, err
+	}
+
+	return handlers.VerificationLink{
+		ID:        link.ID,
+		Link:      link.Link,
+		ExpiresAt: link.ExpiresAt,
+	}, nil
+}
+
+func (c *Verification) Status(ctx context.Context, id string) (handlers.VerificationStatus, error) {
+	v, err := c.useCase.Status(ctx, id)
+	if err != nil {
+		return handlers.VerificationStatus{}, err
+	}
+
+	return handlers.VerificationStatus{
+		ID:         v.ID,
+		Status:     string(v.Status),
+		ExpiresAt:  v.ExpiresAt,
+		VerifiedAt: v.VerifiedAt,
+	}, nil
 }
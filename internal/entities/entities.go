@@ -9,6 +9,62 @@ const (
 	VerifPending  VerificationStatus = "pending"
 	VerifApproved VerificationStatus = "approved"
 	VerifExpired  VerificationStatus = "expired"
+
+	SuspiciousChatLocked    SuspiciousReason = "chat_locked"
+	SuspiciousLinkRevoked   SuspiciousReason = "link_revoked"
+	SuspiciousGuestConflict SuspiciousReason = "guest_conflict"
+
+	BusyReservation BusyKind = "reservation"
+	BusyBlackout    BusyKind = "blackout"
+	BusyEvent       BusyKind = "event"
+
+	ChannelTelegram NotificationChannel = "telegram"
+	ChannelEmail    NotificationChannel = "email"
+	ChannelSMS      NotificationChannel = "sms"
+	ChannelWebhook  NotificationChannel = "webhook"
+
+	BookingCheckIn    BookingStep = "check_in"
+	BookingCheckOut   BookingStep = "check_out"
+	BookingGuests     BookingStep = "guests"
+	BookingHouse      BookingStep = "house"
+	BookingExtras     BookingStep = "extras"
+	BookingBathhouse  BookingStep = "bathhouse"
+	BookingFillOption BookingStep = "fill_option"
+	BookingConfirm    BookingStep = "confirm"
+
+	DeliverySent    DeliveryStatus = "sent"
+	DeliveryFailed  DeliveryStatus = "failed"
+	DeliverySkipped DeliveryStatus = "skipped"
+
+	OutboxPending OutboxStatus = "pending"
+	OutboxSent    OutboxStatus = "sent"
+	OutboxFailed  OutboxStatus = "failed"
+
+	ReviewPending  ReviewStatus = "pending"
+	ReviewApproved ReviewStatus = "approved"
+	ReviewRejected ReviewStatus = "rejected"
+
+	ApplicationNew       ApplicationStatus = "new"
+	ApplicationContacted ApplicationStatus = "contacted"
+	ApplicationQuoted    ApplicationStatus = "quoted"
+	ApplicationWon       ApplicationStatus = "won"
+	ApplicationLost      ApplicationStatus = "lost"
+
+	EventConfirmed EventBookingStatus = "confirmed"
+	EventCancelled EventBookingStatus = "cancelled"
+
+	MediaHouse     MediaOwner = "house"
+	MediaExtra     MediaOwner = "extra"
+	MediaBathhouse MediaOwner = "bathhouse"
+
+	BedDouble BedKind = "double"
+	BedSingle BedKind = "single"
+	BedSofa   BedKind = "sofa"
+	BedBunk   BedKind = "bunk"
+
+	FlexibleShift   FlexibleMode = "shift"
+	FlexibleWeekend FlexibleMode = "weekend"
+	FlexibleNights  FlexibleMode = "nights"
 )
 
 type (
@@ -18,19 +74,115 @@ type (
 		Description   string
 		Capacity      int
 		BasePrice     int
-		Images        []string
+		CoverImage    string   // the chosen cover, otherwise the first gallery image
+		Images        []string // gallery in display order
 		CheckInFrom   string
 		CheckOutUntil string
+		Directions    string
+		Amenities     HouseAmenities
+		Tags          []string
+		Rating        float64
+		ReviewsCount  int
+		Reviews       []Review
+	}
+
+	HouseAmenities struct {
+		Sauna       bool
+		PetsAllowed bool
+		Kitchen     bool
+		Fireplace   bool
+		Beds        []HouseBed
+		Area        int // m², 0 if unknown
+	}
+
+	HouseBed struct {
+		Kind  BedKind
+		Count int
+	}
+
+	BedKind string
+
+	HouseTag struct {
+		Name   string
+		Houses int
 	}
 
 	Guest struct {
-		Name  string
-		Email string
-		Phone string
-		TgID  int64
+		Name           string
+		Email          string
+		Phone          string
+		TgID           int64
+		Locale         string
+		NotifyChannels []NotificationChannel
+	}
+
+	// GuestProfile is a stored guest, Phone is in E.164 and unique among guests.
+	GuestProfile struct {
+		UUID      uuid.UUID
+		Name      string
+		Email     string
+		Phone     string
+		TgID      int64
+		Locale    string
+		CreatedAt time.Time
+	}
+
+	// GuestMerge counts the records moved from the merged guest to the kept one.
+	GuestMerge struct {
+		Guest         GuestProfile
+		Reservations  int64
+		Reviews       int64
+		Notifications int64
+	}
+
+	// GuestData is everything stored about a guest, served on a personal data request.
+	GuestData struct {
+		Profile        GuestProfile
+		NotifyChannels []NotificationChannel
+		Reservations   []GuestDataReservation
+		Reviews        []GuestDataReview
+		Notifications  []GuestDataNotification
+		Verifications  []GuestDataVerification
+	}
+
+	GuestDataReservation struct {
+		UUID        uuid.UUID
+		HouseName   string
+		CheckIn     time.Time
+		CheckOut    time.Time
+		GuestsCount int
+		Status      string
+		TotalPrice  int
+		CreatedAt   time.Time
+	}
+
+	GuestDataReview struct {
+		ReservationUUID uuid.UUID
+		Rating          int
+		Comment         string
+		Status          string
+		CreatedAt       time.Time
+	}
+
+	GuestDataNotification struct {
+		Kind      string
+		Channel   string
+		Recipient string
+		Status    string
+		CreatedAt time.Time
+	}
+
+	GuestDataVerification struct {
+		Name       string
+		Email      string
+		Phone      string
+		Status     string
+		CreatedAt  time.Time
+		VerifiedAt *time.Time
 	}
 
 	Reservation struct {
+		UUID        uuid.UUID
 		HouseID     int
 		GuestUUID   uuid.UUID
 		CheckIn     time.Time // [checkIn, checkOut)
@@ -38,12 +190,37 @@ type (
 		GuestsCount int
 		Status      string
 		TotalPrice  int
+		Discount    int // loyalty discount already applied to TotalPrice, percent
 		CreatedAt   time.Time
 		UpdatedAt   time.Time
 		Extras      []ReservationExtra
 		Bathhouse   []BathhouseReservation
 	}
 
+	// LoyaltyStats counts the guest's completed (checked out) stays and their cost.
+	LoyaltyStats struct {
+		Stays int
+		Spend int
+	}
+
+	// LoyaltyTier is reached with at least MinStays completed stays and MinSpend spent.
+	LoyaltyTier struct {
+		Name     string
+		MinStays int
+		MinSpend int
+		Discount int // percent
+	}
+
+	// LoyaltyStatus is the guest's current tier, nil below the first one, and what is
+	// left to reach the next tier, nil at the top.
+	LoyaltyStatus struct {
+		LoyaltyStats
+		Tier        *LoyaltyTier
+		Next        *LoyaltyTier
+		StaysToNext int
+		SpendToNext int
+	}
+
 	ReservationUpdateStatus struct {
 		UUID     uuid.UUID
 		CheckIn  time.Time // [checkIn, checkOut)
@@ -52,11 +229,25 @@ type (
 	}
 
 	ReservationReminderNotification struct {
-		UUID      uuid.UUID
-		HouseName string
-		CheckIn   time.Time
-		CheckOut  time.Time
-		UserTgID  int64
+		UUID          uuid.UUID
+		HouseName     string
+		CheckIn       time.Time
+		CheckOut      time.Time
+		CheckInFrom   string
+		CheckOutUntil string
+		Directions    string
+		UserTgID      int64
+		GuestUUID     uuid.UUID
+		GuestName     string
+		GuestEmail    string
+		GuestPhone    string
+		GuestLocale   string
+		CreatedAt     time.Time
+		// Stage being sent and the stages already sent or skipped for the reservation.
+		Stage      string
+		Anchor     string
+		DaysBefore int
+		SentStages []string
 	}
 
 	ReservationMessage struct {
@@ -80,17 +271,36 @@ type (
 	}
 
 	ReservationCreatedMessage struct {
+		UUID        uuid.UUID
 		HouseName   string
+		GuestUUID   uuid.UUID
 		GuestName   string
 		GuestPhone  string
+		GuestEmail  string
+		GuestLocale string
 		CheckIn     time.Time // [checkIn, checkOut)
 		CheckOut    time.Time
 		GuestsCount int
 		TotalPrice  int
+		Discount    int
 		Extras      []ReservationExtra
 		Bathhouse   []BathhouseMessage
 	}
 
+	ReservationCancelledMessage struct {
+		UUID        uuid.UUID
+		HouseName   string
+		GuestUUID   uuid.UUID
+		GuestName   string
+		GuestPhone  string
+		GuestEmail  string
+		GuestTgID   int64
+		GuestLocale string
+		CheckIn     time.Time // [checkIn, checkOut)
+		CheckOut    time.Time
+		TotalPrice  int
+	}
+
 	ReservationExtra struct {
 		ExtraID  int
 		Quantity int
@@ -131,10 +341,49 @@ type (
 		Bathhouse int
 	}
 
+	// GetAvailableHouses: true flags require the amenity, Tags must all be present.
 	GetAvailableHouses struct {
-		CheckIn     time.Time
-		CheckOut    time.Time
-		GuestsCount int
+		CheckIn       time.Time
+		CheckOut      time.Time
+		GuestsCount   int
+		Sauna         bool
+		PetsAllowed   bool
+		Kitchen       bool
+		Fireplace     bool
+		WithBathhouse bool
+		MinArea       int
+		Tags          []string
+	}
+
+	// AvailableHouse is a house free for the requested dates with its bathhouses.
+	AvailableHouse struct {
+		House      House
+		Bathhouses []Bathhouse
+	}
+
+	FlexibleMode string
+
+	// FlexibleSearch looks for stays close to the requested one. Filter.CheckIn and Filter.CheckOut
+	// hold the requested stay for FlexibleShift and the window to fit Nights into for FlexibleNights,
+	// FlexibleWeekend takes Friday to Sunday stays of Month.
+	FlexibleSearch struct {
+		Filter GetAvailableHouses
+		Mode   FlexibleMode
+		Days   int
+		Month  time.Time
+		Nights int
+		Limit  int
+	}
+
+	// DateRange is the half-open range [From, To) of dates.
+	DateRange struct {
+		From time.Time
+		To   time.Time
+	}
+
+	HouseOccupancy struct {
+		HouseID int
+		Busy    []DateRange // sorted by From
 	}
 
 	CheckAvailability struct {
@@ -145,17 +394,59 @@ type (
 
 	VerificationStatus string
 
+	// Verification keeps only TokenHash, the SHA-256 of the deep-link token.
 	Verification struct {
-		ID         string
-		Code       string
-		Email      string
-		Phone      string
-		Name       string
-		TgUserID   *int64
-		Status     VerificationStatus
-		CreatedAt  time.Time
-		VerifiedAt *time.Time
-		ExpiresAt  time.Time
+		ID             string
+		TokenHash      string
+		Email          string
+		Phone          string
+		Name           string
+		TgUserID       *int64
+		Status         VerificationStatus
+		CreatedAt      time.Time
+		VerifiedAt     *time.Time
+		ExpiresAt      time.Time
+		NotifyChannels []NotificationChannel
+		// Attempts counts how many times the token was sent by locked out chats.
+		Attempts int
+	}
+
+	// VerificationFunnel counts verifications created in a period by their current status.
+	VerificationFunnel struct {
+		Created  int
+		Pending  int
+		Approved int
+		Expired  int
+	}
+
+	// VerificationLink is handed to the website: the guest opens Link in Telegram and
+	// the site polls the verification by ID until it's approved.
+	VerificationLink struct {
+		ID        string
+		Link      string
+		ExpiresAt time.Time
+	}
+
+	// VerificationLockout is the wrong-code counter of a Telegram chat.
+	VerificationLockout struct {
+		TgID        int64
+		Failures    int
+		Lockouts    int
+		LockedUntil *time.Time
+	}
+
+	SuspiciousReason string
+
+	// SuspiciousVerification is reported to admins when a chat gets locked out, a pending
+	// link is revoked or its phone or chat belongs to another guest; Name and Phone belong
+	// to that verification.
+	SuspiciousVerification struct {
+		Reason      SuspiciousReason
+		TgID        int64
+		Lockouts    int
+		LockedUntil time.Time
+		Name        string
+		Phone       string
 	}
 
 	BathhouseReservation struct {
@@ -185,10 +476,266 @@ type (
 		Price       int
 	}
 
+	BusyKind string
+
+	BusyPeriod struct {
+		ID       string
+		HouseID  int
+		Kind     BusyKind
+		CheckIn  time.Time // [checkIn, checkOut)
+		CheckOut time.Time
+	}
+
+	CalendarSource struct {
+		ID           int
+		HouseID      int
+		Name         string
+		URL          string
+		LastSyncedAt *time.Time
+	}
+
+	ImportedEvent struct {
+		UID      string
+		Summary  string
+		CheckIn  time.Time // [checkIn, checkOut)
+		CheckOut time.Time
+	}
+
+	Blackout struct {
+		ID        int
+		HouseID   int
+		HouseName string
+		CheckIn   time.Time // [checkIn, checkOut)
+		CheckOut  time.Time
+		Reason    string
+		SourceID  *int
+	}
+
+	ReservationConflict struct {
+		UUID       uuid.UUID
+		CheckIn    time.Time // [checkIn, checkOut)
+		CheckOut   time.Time
+		GuestName  string
+		GuestPhone string
+	}
+
+	CalendarConflictMessage struct {
+		SourceName   string
+		HouseName    string
+		CheckIn      time.Time // [checkIn, checkOut)
+		CheckOut     time.Time
+		Reservations []ReservationConflict
+	}
+
+	NotificationChannel string
+
+	// AdminRecipient is one address of an admin channel: a chat ID, an email, a phone or a URL.
+	AdminRecipient struct {
+		Channel   NotificationChannel
+		Recipient string
+	}
+
+	DeliveryStatus string
+
+	NotificationDelivery struct {
+		Kind      string
+		Channel   NotificationChannel
+		Recipient string
+		GuestUUID *uuid.UUID
+		Reference string
+		Status    DeliveryStatus
+		Error     string
+	}
+
+	OutboxStatus string
+
+	OutboxMessage struct {
+		ID            int64
+		Kind          string
+		Payload       []byte
+		Status        OutboxStatus
+		Attempts      int
+		LastError     string
+		NextAttemptAt time.Time
+		CreatedAt     time.Time
+	}
+
+	MessageTemplate struct {
+		Key       string
+		Locale    string
+		Body      string
+		IsDefault bool
+		UpdatedAt *time.Time
+	}
+
+	ReviewStatus string
+
+	Review struct {
+		ID              int
+		ReservationUUID uuid.UUID
+		HouseID         int
+		GuestName       string
+		Rating          int
+		Comment         string
+		Status          ReviewStatus
+		CreatedAt       time.Time
+		ModeratedAt     *time.Time
+	}
+
+	FeedbackRequest struct {
+		ReservationUUID uuid.UUID
+		HouseName       string
+		GuestName       string
+		GuestTgID       int64
+		GuestLocale     string
+		CheckIn         time.Time
+		CheckOut        time.Time
+	}
+
+	BotAdmin struct {
+		TgID      int64
+		Name      string
+		CreatedAt time.Time
+	}
+
+	AdminReservation struct {
+		UUID        uuid.UUID
+		HouseName   string
+		GuestName   string
+		GuestPhone  string
+		GuestsCount int
+		CheckIn     time.Time // [checkIn, checkOut)
+		CheckOut    time.Time
+		Status      string
+		TotalPrice  int
+	}
+
+	BathhouseSession struct {
+		ReservationUUID uuid.UUID
+		HouseName       string
+		Name            string
+		TimeFrom        string
+		TimeTo          string
+		FillOption      *string
+		GuestName       string
+		GuestPhone      string
+	}
+
+	DayAgenda struct {
+		Date       time.Time
+		Arrivals   []AdminReservation
+		Departures []AdminReservation
+		Bathhouses []BathhouseSession
+		Events     []EventBooking // events going on that day
+	}
+
+	BookingStep string
+
+	// BookingSession is the state of a booking made in the Telegram bot, one per chat.
+	BookingSession struct {
+		ChatID      int64
+		Step        BookingStep
+		Month       time.Time // month shown in the date picker
+		CheckIn     time.Time // [checkIn, checkOut)
+		CheckOut    time.Time
+		GuestsCount int
+		HouseID     int
+		HouseName   string
+		StayPrice   int
+		Extras      []int
+		Bathhouse   []BathhouseReservation
+		UpdatedAt   time.Time
+	}
+
+	BookingSummary struct {
+		HouseName   string
+		CheckIn     time.Time // [checkIn, checkOut)
+		CheckOut    time.Time
+		GuestsCount int
+		StayPrice   int
+		Discount    int
+		Extras      []Extra
+		Bathhouse   []BathhouseMessage
+	}
+
 	NewApplication struct {
 		Name        string
 		Phone       string
 		CheckIn     string
 		GuestsCount int
 	}
+
+	ApplicationStatus string
+
+	EventApplication struct {
+		ID          int
+		Name        string
+		Phone       string
+		CheckIn     string
+		GuestsCount int
+		Status      ApplicationStatus
+		Notes       string
+		Manager     string
+		CreatedAt   time.Time
+		UpdatedAt   time.Time
+	}
+
+	// EventApplicationUpdate changes only the fields that are set.
+	EventApplicationUpdate struct {
+		Status  *ApplicationStatus
+		Notes   *string
+		Manager *string
+	}
+
+	EventBookingStatus string
+
+	// EventBooking reserves several houses and their bathhouses for one event. Bathhouses
+	// are taken for whole days, the price and deposit belong to the event, not the houses.
+	EventBooking struct {
+		UUID           uuid.UUID
+		ApplicationID  *int
+		Title          string
+		ContactName    string
+		ContactPhone   string
+		CheckIn        time.Time // [checkIn, checkOut)
+		CheckOut       time.Time
+		GuestsCount    int
+		HouseIDs       []int
+		HouseNames     []string
+		BathhouseIDs   []int
+		BathhouseNames []string
+		TotalPrice     int
+		Deposit        int
+		Status         EventBookingStatus
+		Notes          string
+		CreatedAt      time.Time
+	}
+
+	MediaOwner string
+
+	// Media is an uploaded image of a house, extra or bathhouse. Key is the storage key of the
+	// original file, Variants are its resized copies from the smallest to the largest.
+	Media struct {
+		ID          int
+		Owner       MediaOwner
+		OwnerID     int
+		Position    int
+		ContentType string
+		Size        int
+		Width       int
+		Height      int
+		Key         string
+		URL         string
+		Variants    []MediaVariant
+		CreatedAt   time.Time
+	}
+
+	MediaVariant struct {
+		Name        string
+		Key         string
+		URL         string
+		ContentType string
+		Width       int
+		Height      int
+	}
 )
@@ -0,0 +1,65 @@
+package calendars
+
+import (
+	"context"
+	"fmt"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/ical"
+	"io"
+	"net/http"
+)
+
+const maxFeedSize = 5 << 20
+
+// HTTPClient is satisfied by *http.Client and lets tests serve feeds from fixtures.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+type Client struct {
+	http HTTPClient
+}
+
+func NewClient(httpClient HTTPClient) (*Client, error) {
+	if httpClient == nil {
+		return nil, errorspkg.NewErrConstructorDependencies("calendars.NewClient", "HTTPClient", "nil")
+	}
+
+	return &Client{http: httpClient}, nil
+}
+
+func (c *Client) Fetch(ctx context.Context, url string) ([]entities.ImportedEvent, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/calendar")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch calendar %s: unexpected status %d", url, resp.StatusCode)
+	}
+
+	events, err := ical.Parse(io.LimitReader(resp.Body, maxFeedSize))
+	if err != nil {
+		return nil, fmt.Errorf("parse calendar %s: %w", url, err)
+	}
+
+	result := make([]entities.ImportedEvent, 0, len(events))
+	for _, e := range events {
+		result = append(result, entities.ImportedEvent{
+			UID:      e.UID,
+			Summary:  e.Summary,
+			CheckIn:  e.Start,
+			CheckOut: e.End,
+		})
+	}
+
+	return result, nil
+}
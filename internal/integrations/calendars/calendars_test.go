@@ -0,0 +1,105 @@
+package calendars
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func newTestClient(t *testing.T, srv *httptest.Server) *Client {
+	t.Helper()
+	client, err := NewClient(srv.Client())
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return client
+}
+
+func TestClientFetch(t *testing.T) {
+	feed, err := os.ReadFile("testdata/feed.ics")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var accept string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accept = r.Header.Get("Accept")
+		w.Header().Set("Content-Type", "text/calendar")
+		_, _ = w.Write(feed)
+	}))
+	defer srv.Close()
+
+	events, err := newTestClient(t, srv).Fetch(context.Background(), srv.URL+"/feed.ics")
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if accept != "text/calendar" {
+		t.Errorf("Accept = %q, want text/calendar", accept)
+	}
+
+	date := func(s string) time.Time {
+		d, _ := time.Parse(time.DateOnly, s)
+		return d
+	}
+	want := []struct {
+		uid, summary      string
+		checkIn, checkOut time.Time
+	}{
+		{"booking-1@example.com", "Reserved, Ivan", date("2099-07-01"), date("2099-07-05")},
+		{"booking-2@example.com", "Not available", date("2099-07-10"), date("2099-07-12")},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d (the cancelled one skipped): %+v", len(events), len(want), events)
+	}
+	for i, w := range want {
+		e := events[i]
+		if e.UID != w.uid || e.Summary != w.summary || !e.CheckIn.Equal(w.checkIn) || !e.CheckOut.Equal(w.checkOut) {
+			t.Errorf("event %d = %+v, want %+v", i, e, w)
+		}
+	}
+}
+
+func TestClientFetchFails(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{
+			name: "server error",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				http.Error(w, "maintenance", http.StatusServiceUnavailable)
+			},
+		},
+		{
+			name: "not a calendar",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte("<html>login required</html>"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(tt.handler)
+			defer srv.Close()
+
+			events, err := newTestClient(t, srv).Fetch(context.Background(), srv.URL)
+			if err == nil {
+				t.Fatalf("Fetch succeeded with %d events, want an error", len(events))
+			}
+		})
+	}
+
+	t.Run("unreachable", func(t *testing.T) {
+		srv := httptest.NewServer(http.NotFoundHandler())
+		client := newTestClient(t, srv)
+		srv.Close()
+
+		if _, err := client.Fetch(context.Background(), srv.URL); err == nil {
+			t.Fatal("Fetch from a closed server succeeded, want an error")
+		}
+	})
+}
@@ -0,0 +1,179 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"github.com/calyrexx/QuietGrooveBackend/internal/configuration"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/calyrexx/QuietGrooveBackend/internal/usecases"
+	"github.com/calyrexx/zeroslog"
+	htmltemplate "html/template"
+	"log/slog"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"slices"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+const (
+	emailService = "email"
+
+	tmplReservationCreated        = "reservation_created"
+	tmplReservationCreatedAdmin   = "reservation_created_admin"
+	tmplReminder                  = "reminder"
+	tmplReservationCancelled      = "reservation_cancelled"
+	tmplReservationCancelledAdmin = "reservation_cancelled_admin"
+	tmplEventApplication          = "event_application"
+)
+
+//go:embed templates/*.txt templates/*.html
+var templatesFS embed.FS
+
+var subjects = map[string]string{
+	tmplReservationCreated:        "Ваше бронирование подтверждено — QuietGrove",
+	tmplReservationCreatedAdmin:   "Новое бронирование",
+	tmplReminder:                  "Скоро заезд — QuietGrove",
+	tmplReservationCancelled:      "Бронирование отменено — QuietGrove",
+	tmplReservationCancelledAdmin: "Бронирование отменено",
+	tmplEventApplication:          "Новая заявка на мероприятие",
+}
+
+var funcs = map[string]any{
+	"date": func(t time.Time) string { return t.Format("02.01.2006") },
+	"dots": func(s string) string { return strings.ReplaceAll(s, "-", ".") },
+}
+
+type Adapter struct {
+	sender      *smtpSender
+	from        *mail.Address
+	adminEmails []string
+	text        *texttemplate.Template
+	html        *htmltemplate.Template
+	logger      *slog.Logger
+}
+
+func NewAdapter(creds *configuration.SMTP, logger *slog.Logger) (*Adapter, error) {
+	if creds == nil {
+		return nil, errorspkg.NewErrConstructorDependencies("email.NewAdapter", "creds", "nil")
+	}
+	if logger == nil {
+		return nil, errorspkg.NewErrConstructorDependencies("email.NewAdapter", "logger", "nil")
+	}
+	from, err := mail.ParseAddress(creds.From)
+	if err != nil {
+		return nil, errorspkg.NewErrConstructorDependencies("email.NewAdapter", "From", err.Error())
+	}
+
+	text, err := texttemplate.New("email").Funcs(funcs).ParseFS(templatesFS, "templates/*.txt")
+	if err != nil {
+		return nil, err
+	}
+	html, err := htmltemplate.New("email").Funcs(funcs).ParseFS(templatesFS, "templates/*.html")
+	if err != nil {
+		return nil, err
+	}
+
+	var auth smtp.Auth
+	if creds.Username != "" {
+		auth = smtp.PlainAuth("", creds.Username, creds.Password, creds.Host)
+	}
+
+	return &Adapter{
+		sender: &smtpSender{
+			addr:        net.JoinHostPort(creds.Host, creds.Port),
+			host:        creds.Host,
+			auth:        auth,
+			implicitTLS: creds.ImplicitTLS,
+			timeout:     creds.Timeout,
+		},
+		from:        from,
+		adminEmails: creds.AdminEmails,
+		text:        text,
+		html:        html,
+		logger:      logger.With(zeroslog.ServiceKey, emailService),
+	}, nil
+}
+
+// AdminRecipients lists the admin addresses the dispatcher delivers to one by one.
+func (a *Adapter) AdminRecipients() []string {
+	return a.adminEmails
+}
+
+// ForAdmin returns the adapter limited to one admin address.
+func (a *Adapter) ForAdmin(recipient string) (usecases.ChannelNotifier, bool) {
+	if !slices.Contains(a.adminEmails, recipient) {
+		return nil, false
+	}
+	scoped := *a
+	scoped.adminEmails = []string{recipient}
+	return &scoped, true
+}
+
+func (a *Adapter) ReservationCreatedForAdmin(msg entities.ReservationCreatedMessage) error {
+	return a.sendAll(a.adminEmails, tmplReservationCreatedAdmin, msg)
+}
+
+func (a *Adapter) ReservationCreatedForUser(msg entities.ReservationCreatedMessage, _ int64) error {
+	return a.sendAll([]string{msg.GuestEmail}, tmplReservationCreated, msg)
+}
+
+func (a *Adapter) ReservationCancelledForAdmin(msg entities.ReservationCancelledMessage) error {
+	return a.sendAll(a.adminEmails, tmplReservationCancelledAdmin, msg)
+}
+
+func (a *Adapter) ReservationCancelledForUser(msg entities.ReservationCancelledMessage) error {
+	return a.sendAll([]string{msg.GuestEmail}, tmplReservationCancelled, msg)
+}
+
+func (a *Adapter) RemindUser(msg []entities.ReservationReminderNotification) error {
+	var errs []error
+	for _, m := range msg {
+		if err := a.sendAll([]string{m.GuestEmail}, tmplReminder, m); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (a *Adapter) NewApplicationForEvent(app entities.EventApplication) error {
+	return a.sendAll(a.adminEmails, tmplEventApplication, app)
+}
+
+// sendAll renders the template once and sends it to every recipient separately; a failure
+// for one address is logged and reported but doesn't stop delivery to the rest.
+func (a *Adapter) sendAll(recipients []string, tmpl string, data any) error {
+	ctx := context.Background()
+
+	var textBody, htmlBody bytes.Buffer
+	if err := a.text.ExecuteTemplate(&textBody, tmpl+".txt", data); err != nil {
+		return fmt.Errorf("render %s.txt: %w", tmpl, err)
+	}
+	if err := a.html.ExecuteTemplate(&htmlBody, tmpl+".html", data); err != nil {
+		return fmt.Errorf("render %s.html: %w", tmpl, err)
+	}
+
+	var errs []error
+	for _, to := range recipients {
+		if to == "" {
+			continue
+		}
+
+		msg, err := buildMessage(a.from.String(), to, subjects[tmpl], textBody.String(), htmlBody.String())
+		if err == nil {
+			err = a.sender.send(ctx, a.from.Address, to, msg)
+		}
+		if err != nil {
+			a.logger.Error("send email", zeroslog.ErrorKey, err, "template", tmpl, "to", to)
+			errs = append(errs, fmt.Errorf("send %s to %s: %w", tmpl, to, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,161 @@
+package email
+
+import (
+	"io"
+	"log/slog"
+	"mime"
+	"mime/multipart"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/calyrexx/QuietGrooveBackend/internal/configuration"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/integrations/email/smtptest"
+	"github.com/google/uuid"
+)
+
+func newTestAdapter(t *testing.T, admins ...string) (*Adapter, *smtptest.Server) {
+	t.Helper()
+
+	srv, err := smtptest.NewServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = srv.Close() })
+
+	host, port := srv.Addr()
+	adapter, err := NewAdapter(&configuration.SMTP{
+		Host:        host,
+		Port:        port,
+		From:        "QuietGrove <booking@quietgrove.test>",
+		AdminEmails: admins,
+		Timeout:     5 * time.Second,
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return adapter, srv
+}
+
+// parsedMessage is a received letter with the subject decoded and the parts by content type.
+type parsedMessage struct {
+	to      string
+	subject string
+	parts   map[string]string
+}
+
+func parse(t *testing.T, msg smtptest.Message) parsedMessage {
+	t.Helper()
+
+	m, err := msg.Parse()
+	if err != nil {
+		t.Fatalf("parse message: %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(m.Header.Get("Subject"))
+	if err != nil {
+		t.Fatalf("decode subject: %v", err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(m.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q, want multipart/alternative", m.Header.Get("Content-Type"))
+	}
+
+	parts := make(map[string]string)
+	mr := multipart.NewReader(m.Body, params["boundary"])
+	for {
+		part, partErr := mr.NextPart()
+		if partErr == io.EOF {
+			break
+		}
+		if partErr != nil {
+			t.Fatalf("read part: %v", partErr)
+		}
+		body, _ := io.ReadAll(part)
+		contentType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		parts[contentType] = string(body)
+	}
+
+	return parsedMessage{to: m.Header.Get("To"), subject: subject, parts: parts}
+}
+
+func TestReservationCreatedForUser(t *testing.T) {
+	adapter, srv := newTestAdapter(t)
+
+	err := adapter.ReservationCreatedForUser(entities.ReservationCreatedMessage{
+		UUID:        uuid.New(),
+		HouseName:   "Лесной",
+		GuestName:   "Анна",
+		GuestEmail:  "anna@example.com",
+		CheckIn:     time.Date(2099, 7, 1, 0, 0, 0, 0, time.UTC),
+		CheckOut:    time.Date(2099, 7, 3, 0, 0, 0, 0, time.UTC),
+		GuestsCount: 2,
+		TotalPrice:  15000,
+		Discount:    5,
+	}, 0)
+	if err != nil {
+		t.Fatalf("ReservationCreatedForUser: %v", err)
+	}
+
+	messages := srv.Messages()
+	if len(messages) != 1 {
+		t.Fatalf("server got %d messages, want 1", len(messages))
+	}
+	if messages[0].From != "booking@quietgrove.test" {
+		t.Errorf("envelope from = %q", messages[0].From)
+	}
+	if len(messages[0].To) != 1 || messages[0].To[0] != "anna@example.com" {
+		t.Errorf("envelope recipients = %v, want [anna@example.com]", messages[0].To)
+	}
+
+	got := parse(t, messages[0])
+	if got.to != "anna@example.com" {
+		t.Errorf("To = %q", got.to)
+	}
+	if got.subject != subjects[tmplReservationCreated] {
+		t.Errorf("Subject = %q, want %q", got.subject, subjects[tmplReservationCreated])
+	}
+	for _, contentType := range []string{"text/plain", "text/html"} {
+		body := got.parts[contentType]
+		for _, want := range []string{"Анна", "Лесной", "01.07.2099", "03.07.2099", "15000", "5%"} {
+			if !strings.Contains(body, want) {
+				t.Errorf("%s part has no %q:\n%s", contentType, want, body)
+			}
+		}
+	}
+}
+
+func TestAdminEmailsAreSentSeparately(t *testing.T) {
+	adapter, srv := newTestAdapter(t, "owner@quietgrove.test", "gone@quietgrove.test", "manager@quietgrove.test")
+	srv.Reject = map[string]bool{"gone@quietgrove.test": true}
+
+	err := adapter.ReservationCancelledForAdmin(entities.ReservationCancelledMessage{
+		UUID:      uuid.New(),
+		HouseName: "Лесной",
+		GuestName: "Анна",
+		CheckIn:   time.Date(2099, 7, 1, 0, 0, 0, 0, time.UTC),
+		CheckOut:  time.Date(2099, 7, 3, 0, 0, 0, 0, time.UTC),
+	})
+	if err == nil || !strings.Contains(err.Error(), "gone@quietgrove.test") {
+		t.Fatalf("error = %v, want one for the rejected address", err)
+	}
+
+	messages := srv.Messages()
+	if len(messages) != 2 {
+		t.Fatalf("server got %d messages, want 2", len(messages))
+	}
+	for i, want := range []string{"owner@quietgrove.test", "manager@quietgrove.test"} {
+		if len(messages[i].To) != 1 || messages[i].To[0] != want {
+			t.Errorf("message %d recipients = %v, want [%s]", i, messages[i].To, want)
+		}
+		got := parse(t, messages[i])
+		if got.subject != subjects[tmplReservationCancelledAdmin] {
+			t.Errorf("message %d Subject = %q", i, got.subject)
+		}
+		if !strings.Contains(got.parts["text/plain"], "Лесной") {
+			t.Errorf("message %d text has no house name:\n%s", i, got.parts["text/plain"])
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package email
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/google/uuid"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"time"
+)
+
+func buildMessage(from, to, subject, textBody, htmlBody string) ([]byte, error) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+
+	headers := []string{
+		"From: " + from,
+		"To: " + to,
+		"Subject: " + mime.BEncoding.Encode("utf-8", subject),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		fmt.Sprintf("Message-ID: <%s@quietgrove>", uuid.NewString()),
+		"MIME-Version: 1.0",
+		fmt.Sprintf("Content-Type: multipart/alternative; boundary=%q", mw.Boundary()),
+	}
+
+	var msg bytes.Buffer
+	for _, h := range headers {
+		msg.WriteString(h + "\r\n")
+	}
+	msg.WriteString("\r\n")
+
+	if err := writePart(mw, "text/plain; charset=utf-8", textBody); err != nil {
+		return nil, err
+	}
+	if err := writePart(mw, "text/html; charset=utf-8", htmlBody); err != nil {
+		return nil, err
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	msg.Write(buf.Bytes())
+	return msg.Bytes(), nil
+}
+
+func writePart(mw *multipart.Writer, contentType, body string) error {
+	part, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+
+	qp := quotedprintable.NewWriter(part)
+	if _, err = qp.Write([]byte(body)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
@@ -0,0 +1,76 @@
+package email
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/smtp"
+	"time"
+)
+
+type smtpSender struct {
+	addr        string
+	host        string
+	auth        smtp.Auth
+	implicitTLS bool
+	timeout     time.Duration
+}
+
+// send delivers one message to one recipient over a fresh connection, so a rejected
+// address never poisons the session for the next recipient.
+func (s *smtpSender) send(ctx context.Context, from, to string, msg []byte) error {
+	dialer := net.Dialer{Timeout: s.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	if s.timeout > 0 {
+		_ = conn.SetDeadline(time.Now().Add(s.timeout))
+	}
+	if s.implicitTLS {
+		conn = tls.Client(conn, &tls.Config{ServerName: s.host})
+	}
+
+	c, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+	defer func() { _ = c.Close() }()
+
+	if !s.implicitTLS {
+		if ok, _ := c.Extension("STARTTLS"); ok {
+			if err = c.StartTLS(&tls.Config{ServerName: s.host}); err != nil {
+				return err
+			}
+		}
+	}
+
+	if s.auth != nil {
+		if ok, _ := c.Extension("AUTH"); ok {
+			if err = c.Auth(s.auth); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err = c.Mail(from); err != nil {
+		return err
+	}
+	if err = c.Rcpt(to); err != nil {
+		return err
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err = w.Write(msg); err != nil {
+		return err
+	}
+	if err = w.Close(); err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
@@ -0,0 +1,152 @@
+// Package smtptest provides a local SMTP server that accepts mail in memory, so the email
+// adapter can be tested without a real mail server.
+package smtptest
+
+import (
+	"io"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"sync"
+)
+
+// Message is one accepted transaction: the envelope and the raw data after DATA.
+type Message struct {
+	From string
+	To   []string
+	Data []byte
+}
+
+// Parse reads the headers and body of the message.
+func (m Message) Parse() (*mail.Message, error) {
+	return mail.ReadMessage(strings.NewReader(string(m.Data)))
+}
+
+// Server speaks just enough SMTP for net/smtp: no TLS and no AUTH. Recipients listed in
+// Reject are refused with 550.
+type Server struct {
+	Reject map[string]bool
+
+	listener net.Listener
+	wg       sync.WaitGroup
+
+	mu       sync.Mutex
+	messages []Message
+}
+
+// NewServer starts a server on a random local port.
+func NewServer() (*Server, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{listener: l}
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// Addr returns the host and port to put into configuration.SMTP.
+func (s *Server) Addr() (host, port string) {
+	host, port, _ = net.SplitHostPort(s.listener.Addr().String())
+	return host, port
+}
+
+func (s *Server) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Message(nil), s.messages...)
+}
+
+func (s *Server) Close() error {
+	err := s.listener.Close()
+	s.wg.Wait()
+	return err
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handle(conn)
+		}()
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+	tp := textproto.NewConn(conn)
+
+	reply := func(code int, msg string) bool {
+		return tp.PrintfLine("%d %s", code, msg) == nil
+	}
+	if !reply(220, "smtptest ready") {
+		return
+	}
+
+	var msg Message
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		cmd, arg, _ := strings.Cut(line, " ")
+
+		switch strings.ToUpper(cmd) {
+		case "EHLO", "HELO":
+			reply(250, "smtptest")
+		case "MAIL":
+			msg = Message{From: address(arg)}
+			reply(250, "OK")
+		case "RCPT":
+			to := address(arg)
+			if s.rejected(to) {
+				reply(550, "mailbox unavailable")
+				continue
+			}
+			msg.To = append(msg.To, to)
+			reply(250, "OK")
+		case "DATA":
+			if !reply(354, "end data with <CR><LF>.<CR><LF>") {
+				return
+			}
+			data, err := io.ReadAll(tp.DotReader())
+			if err != nil {
+				return
+			}
+			msg.Data = data
+			s.mu.Lock()
+			s.messages = append(s.messages, msg)
+			s.mu.Unlock()
+			reply(250, "OK")
+		case "RSET", "NOOP":
+			reply(250, "OK")
+		case "QUIT":
+			reply(221, "bye")
+			return
+		default:
+			reply(502, "command not implemented")
+		}
+	}
+}
+
+func (s *Server) rejected(to string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Reject[to]
+}
+
+// address takes the mailbox out of "FROM:<a@b>" or "TO:<a@b>".
+func address(arg string) string {
+	_, addr, _ := strings.Cut(arg, ":")
+	addr, _, _ = strings.Cut(strings.TrimSpace(addr), " ")
+	return strings.Trim(addr, "<>")
+}
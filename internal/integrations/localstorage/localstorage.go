@@ -0,0 +1,119 @@
+package localstorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/calyrexx/QuietGrooveBackend/internal/configuration"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	defaultDir     = "uploads"
+	defaultBaseURL = "/uploads"
+)
+
+// Adapter keeps media files in a local directory. Handler serves them, BaseURL must
+// point at the path it is mounted on.
+type Adapter struct {
+	dir     string
+	baseURL string
+}
+
+func NewAdapter(creds *configuration.LocalStorage) (*Adapter, error) {
+	if creds == nil {
+		return nil, errorspkg.NewErrConstructorDependencies("localstorage.NewAdapter", "creds", "nil")
+	}
+
+	dir := creds.Dir
+	if dir == "" {
+		dir = defaultDir
+	}
+	baseURL := strings.TrimRight(creds.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &Adapter{
+		dir:     dir,
+		baseURL: baseURL,
+	}, nil
+}
+
+// Put writes the file through a temporary one, so a half-written file is never served.
+func (a *Adapter) Put(_ context.Context, key, _ string, data []byte) (string, error) {
+	path, err := a.path(key)
+	if err != nil {
+		return "", err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return "", err
+	}
+	if err = tmp.Close(); err != nil {
+		return "", err
+	}
+	if err = os.Chmod(tmp.Name(), 0o644); err != nil {
+		return "", err
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return "", err
+	}
+
+	return a.baseURL + "/" + key, nil
+}
+
+// Delete removes the file and its directory once it is empty. A missing file is not an error.
+func (a *Adapter) Delete(_ context.Context, key string) error {
+	path, err := a.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err = os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	_ = os.Remove(filepath.Dir(path))
+
+	return nil
+}
+
+// Handler serves stored files without directory listings.
+func (a *Adapter) Handler() http.Handler {
+	files := http.FileServer(http.Dir(a.dir))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "" || strings.HasSuffix(r.URL.Path, "/") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		files.ServeHTTP(w, r)
+	})
+}
+
+func (a *Adapter) path(key string) (string, error) {
+	if !filepath.IsLocal(filepath.FromSlash(key)) {
+		return "", fmt.Errorf("invalid storage key %q", key)
+	}
+	return filepath.Join(a.dir, filepath.FromSlash(key)), nil
+}
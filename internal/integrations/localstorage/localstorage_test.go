@@ -0,0 +1,100 @@
+package localstorage
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/calyrexx/QuietGrooveBackend/internal/configuration"
+)
+
+func newTestAdapter(t *testing.T) (*Adapter, string) {
+	t.Helper()
+
+	dir := filepath.Join(t.TempDir(), "uploads")
+	adapter, err := NewAdapter(&configuration.LocalStorage{Dir: dir, BaseURL: "https://quietgrove.test/uploads/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return adapter, dir
+}
+
+func TestPutServeDelete(t *testing.T) {
+	adapter, dir := newTestAdapter(t)
+	ctx := context.Background()
+	key := "house/1/abc/original.jpg"
+
+	url, err := adapter.Put(ctx, key, "image/jpeg", []byte("photo"))
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if url != "https://quietgrove.test/uploads/"+key {
+		t.Errorf("url = %q", url)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(dir, "house", "1", "abc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "original.jpg" {
+		t.Errorf("directory has %v, want only the file without temporary ones", entries)
+	}
+
+	srv := httptest.NewServer(http.StripPrefix("/uploads", adapter.Handler()))
+	defer srv.Close()
+
+	resp, err := srv.Client().Get(srv.URL + "/uploads/" + key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || string(body) != "photo" {
+		t.Errorf("GET = %d %q", resp.StatusCode, body)
+	}
+	if resp.Header.Get("Cache-Control") == "" {
+		t.Error("no Cache-Control on a stored file")
+	}
+
+	for _, path := range []string{"/uploads/house/1/", "/uploads/house/1/abc/missing.jpg"} {
+		resp, err = srv.Client().Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("GET %s = %d, want 404", path, resp.StatusCode)
+		}
+	}
+
+	if err = adapter.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err = os.Stat(filepath.Join(dir, "house", "1", "abc")); !os.IsNotExist(err) {
+		t.Errorf("empty directory is kept: %v", err)
+	}
+	if err = adapter.Delete(ctx, key); err != nil {
+		t.Errorf("Delete of a missing file: %v", err)
+	}
+}
+
+func TestKeysStayInsideDir(t *testing.T) {
+	adapter, dir := newTestAdapter(t)
+	ctx := context.Background()
+
+	for _, key := range []string{"../escape.jpg", "house/../../escape.jpg", "/etc/passwd", ""} {
+		if _, err := adapter.Put(ctx, key, "image/jpeg", []byte("x")); err == nil {
+			t.Errorf("Put(%q) succeeded", key)
+		}
+		if err := adapter.Delete(ctx, key); err == nil {
+			t.Errorf("Delete(%q) succeeded", key)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(dir), "escape.jpg")); !os.IsNotExist(err) {
+		t.Errorf("file written outside the storage dir: %v", err)
+	}
+}
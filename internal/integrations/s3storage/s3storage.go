@@ -0,0 +1,190 @@
+package s3storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/calyrexx/QuietGrooveBackend/internal/configuration"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	defaultRegion  = "us-east-1"
+	signAlgorithm  = "AWS4-HMAC-SHA256"
+	amzDateFormat  = "20060102T150405Z"
+	scopeFormat    = "20060102"
+	signedHeaders  = "host;x-amz-content-sha256;x-amz-date"
+	maxErrorLength = 512
+)
+
+// HTTPClient is satisfied by *http.Client.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Adapter stores media in an S3-compatible bucket (AWS S3, MinIO, Yandex Object Storage)
+// with path-style requests signed by AWS Signature Version 4.
+type Adapter struct {
+	http      HTTPClient
+	origin    string
+	prefix    string
+	host      string
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+	publicURL string
+}
+
+func NewAdapter(creds *configuration.S3Storage, httpClient HTTPClient) (*Adapter, error) {
+	if creds == nil {
+		return nil, errorspkg.NewErrConstructorDependencies("s3storage.NewAdapter", "creds", "nil")
+	}
+	if httpClient == nil {
+		return nil, errorspkg.NewErrConstructorDependencies("s3storage.NewAdapter", "HTTPClient", "nil")
+	}
+	if creds.Bucket == "" {
+		return nil, errorspkg.NewErrConstructorDependencies("s3storage.NewAdapter", "Bucket", "empty")
+	}
+
+	endpoint, err := url.Parse(strings.TrimRight(creds.Endpoint, "/"))
+	if err != nil || endpoint.Host == "" {
+		return nil, errorspkg.NewErrConstructorDependencies("s3storage.NewAdapter", "Endpoint", "invalid")
+	}
+
+	region := creds.Region
+	if region == "" {
+		region = defaultRegion
+	}
+
+	publicURL := strings.TrimRight(creds.PublicURL, "/")
+	if publicURL == "" {
+		publicURL = endpoint.String() + "/" + creds.Bucket
+	}
+
+	return &Adapter{
+		http:      httpClient,
+		origin:    endpoint.Scheme + "://" + endpoint.Host,
+		prefix:    endpoint.Path,
+		host:      endpoint.Host,
+		region:    region,
+		bucket:    creds.Bucket,
+		accessKey: creds.AccessKey,
+		secretKey: creds.SecretKey,
+		publicURL: publicURL,
+	}, nil
+}
+
+func (a *Adapter) Put(ctx context.Context, key, contentType string, data []byte) (string, error) {
+	if err := a.do(ctx, http.MethodPut, key, contentType, data); err != nil {
+		return "", fmt.Errorf("put %s: %w", key, err)
+	}
+	return a.publicURL + "/" + escapePath(key), nil
+}
+
+func (a *Adapter) Delete(ctx context.Context, key string) error {
+	if err := a.do(ctx, http.MethodDelete, key, "", nil); err != nil {
+		return fmt.Errorf("delete %s: %w", key, err)
+	}
+	return nil
+}
+
+func (a *Adapter) do(ctx context.Context, method, key, contentType string, body []byte) error {
+	path := escapePath(a.prefix + "/" + a.bucket + "/" + key)
+
+	req, err := http.NewRequestWithContext(ctx, method, a.origin+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	a.sign(req, path, body, time.Now().UTC())
+
+	resp, err := a.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorLength))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	return nil
+}
+
+// sign adds the Signature Version 4 headers, path must already be URI-encoded.
+func (a *Adapter) sign(req *http.Request, path string, body []byte, now time.Time) {
+	payloadHash := sha256Hex(body)
+	amzDate := now.Format(amzDateFormat)
+	scope := now.Format(scopeFormat) + "/" + a.region + "/s3/aws4_request"
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		"",
+		"host:" + a.host,
+		"x-amz-content-sha256:" + payloadHash,
+		"x-amz-date:" + amzDate,
+		"",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	stringToSign := strings.Join([]string{
+		signAlgorithm,
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+a.secretKey), now.Format(scopeFormat))
+	key = hmacSHA256(key, a.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		signAlgorithm, a.accessKey, scope, signedHeaders, hex.EncodeToString(hmacSHA256(key, stringToSign)),
+	))
+}
+
+// escapePath encodes every byte except unreserved characters and slashes, as S3 expects.
+func escapePath(path string) string {
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/':
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,204 @@
+package s3storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/calyrexx/QuietGrooveBackend/internal/configuration"
+)
+
+const (
+	testAccessKey = "minioadmin"
+	testSecretKey = "minio-secret"
+	testRegion    = "ru-central1"
+)
+
+// minio is a path-style S3 stand-in: it checks the Signature Version 4 of every request
+// against the bytes it received and keeps objects in memory.
+type minio struct {
+	mu       sync.Mutex
+	objects  map[string][]byte
+	types    map[string]string
+	requests []string
+	fail     int
+}
+
+func (m *minio) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	path := r.URL.EscapedPath()
+	m.requests = append(m.requests, r.Method+" "+path)
+
+	if m.fail != 0 {
+		w.WriteHeader(m.fail)
+		_, _ = io.WriteString(w, "<Error><Code>SlowDown</Code></Error>")
+		return
+	}
+	if err = verifySignature(r, path, body); err != nil {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = io.WriteString(w, "<Error><Code>SignatureDoesNotMatch</Code><Message>"+err.Error()+"</Message></Error>")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		m.objects[path] = body
+		m.types[path] = r.Header.Get("Content-Type")
+		w.WriteHeader(http.StatusOK)
+	case http.MethodDelete:
+		delete(m.objects, path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func verifySignature(r *http.Request, path string, body []byte) error {
+	var credential, signature string
+	auth, ok := strings.CutPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 ")
+	if !ok {
+		return fmt.Errorf("unsupported authorization %q", r.Header.Get("Authorization"))
+	}
+	for _, part := range strings.Split(auth, ", ") {
+		name, value, _ := strings.Cut(part, "=")
+		switch name {
+		case "Credential":
+			credential = value
+		case "SignedHeaders":
+			if value != "host;x-amz-content-sha256;x-amz-date" {
+				return fmt.Errorf("signed headers %q", value)
+			}
+		case "Signature":
+			signature = value
+		}
+	}
+
+	accessKey, scope, _ := strings.Cut(credential, "/")
+	if accessKey != testAccessKey {
+		return fmt.Errorf("unknown access key %q", accessKey)
+	}
+	date := r.Header.Get("X-Amz-Date")
+	if scope != date[:8]+"/"+testRegion+"/s3/aws4_request" {
+		return fmt.Errorf("scope %q", scope)
+	}
+	payload := sha256.Sum256(body)
+	payloadHash := hex.EncodeToString(payload[:])
+	if r.Header.Get("X-Amz-Content-Sha256") != payloadHash {
+		return fmt.Errorf("payload hash mismatch")
+	}
+
+	canonical := sha256.Sum256([]byte(r.Method + "\n" + path + "\n\n" +
+		"host:" + r.Host + "\nx-amz-content-sha256:" + payloadHash + "\nx-amz-date:" + date + "\n\n" +
+		"host;x-amz-content-sha256;x-amz-date\n" + payloadHash))
+	toSign := "AWS4-HMAC-SHA256\n" + date + "\n" + scope + "\n" + hex.EncodeToString(canonical[:])
+
+	key := []byte("AWS4" + testSecretKey)
+	for _, part := range []string{date[:8], testRegion, "s3", "aws4_request"} {
+		key = mac(key, part)
+	}
+	if want := hex.EncodeToString(mac(key, toSign)); signature != want {
+		return fmt.Errorf("signature %s, want %s", signature, want)
+	}
+	return nil
+}
+
+func mac(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func newTestAdapter(t *testing.T, creds configuration.S3Storage) (*Adapter, *minio, string) {
+	t.Helper()
+
+	m := &minio{objects: map[string][]byte{}, types: map[string]string{}}
+	srv := httptest.NewServer(m)
+	t.Cleanup(srv.Close)
+
+	creds.Endpoint = srv.URL + creds.Endpoint
+	creds.Region = testRegion
+	creds.Bucket = "media"
+	creds.AccessKey = testAccessKey
+	if creds.SecretKey == "" {
+		creds.SecretKey = testSecretKey
+	}
+	adapter, err := NewAdapter(&creds, srv.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return adapter, m, srv.URL
+}
+
+func TestPutAndDelete(t *testing.T) {
+	adapter, m, origin := newTestAdapter(t, configuration.S3Storage{})
+	ctx := context.Background()
+	key := "house/1/Баня у озера.jpg"
+	escaped := "/media/house/1/%D0%91%D0%B0%D0%BD%D1%8F%20%D1%83%20%D0%BE%D0%B7%D0%B5%D1%80%D0%B0.jpg"
+
+	url, err := adapter.Put(ctx, key, "image/jpeg", []byte("photo"))
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if url != origin+escaped {
+		t.Errorf("url = %q, want %q", url, origin+escaped)
+	}
+	if string(m.objects[escaped]) != "photo" || m.types[escaped] != "image/jpeg" {
+		t.Errorf("stored %q as %q", m.objects[escaped], m.types[escaped])
+	}
+
+	if err = adapter.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if len(m.objects) != 0 {
+		t.Errorf("objects left: %v", m.objects)
+	}
+}
+
+func TestEndpointPathAndPublicURL(t *testing.T) {
+	adapter, m, _ := newTestAdapter(t, configuration.S3Storage{
+		Endpoint:  "/storage/",
+		PublicURL: "https://cdn.quietgrove.test/",
+	})
+
+	url, err := adapter.Put(context.Background(), "extra/2/a.png", "image/png", []byte("png"))
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if url != "https://cdn.quietgrove.test/extra/2/a.png" {
+		t.Errorf("url = %q", url)
+	}
+	if want := "PUT /storage/media/extra/2/a.png"; len(m.requests) != 1 || m.requests[0] != want {
+		t.Errorf("requests = %v, want %q", m.requests, want)
+	}
+}
+
+func TestErrorsCarryStatusAndMessage(t *testing.T) {
+	adapter, m, _ := newTestAdapter(t, configuration.S3Storage{SecretKey: "wrong"})
+	ctx := context.Background()
+
+	_, err := adapter.Put(ctx, "house/1/a.jpg", "image/jpeg", []byte("photo"))
+	if err == nil || !strings.Contains(err.Error(), "403") || !strings.Contains(err.Error(), "SignatureDoesNotMatch") {
+		t.Errorf("Put with a wrong secret: %v", err)
+	}
+
+	m.fail = http.StatusServiceUnavailable
+	err = adapter.Delete(ctx, "house/1/a.jpg")
+	if err == nil || !strings.Contains(err.Error(), "delete house/1/a.jpg") || !strings.Contains(err.Error(), "503") {
+		t.Errorf("Delete on an unavailable server: %v", err)
+	}
+}
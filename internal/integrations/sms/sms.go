@@ -0,0 +1,204 @@
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/calyrexx/QuietGrooveBackend/internal/configuration"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/phonepkg"
+	"github.com/calyrexx/QuietGrooveBackend/internal/usecases"
+	"github.com/calyrexx/zeroslog"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+const (
+	smsService     = "sms"
+	defaultBaseURL = "https://sms.ru"
+)
+
+// HTTPClient is satisfied by *http.Client.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Renderer produces localized message texts by template key.
+type Renderer interface {
+	Render(ctx context.Context, key, locale string, data any) (string, error)
+}
+
+// Adapter sends short texts through an SMS.ru compatible gateway.
+type Adapter struct {
+	http        HTTPClient
+	baseURL     string
+	apiKey      string
+	sender      string
+	adminPhones []string
+	texts       Renderer
+	logger      *slog.Logger
+}
+
+type sendResponse struct {
+	Status     string `json:"status"`
+	StatusText string `json:"status_text"`
+	SMS        map[string]struct {
+		Status     string `json:"status"`
+		StatusText string `json:"status_text"`
+	} `json:"sms"`
+}
+
+func NewAdapter(creds *configuration.SMS, httpClient HTTPClient, texts Renderer, logger *slog.Logger) (*Adapter, error) {
+	if creds == nil {
+		return nil, errorspkg.NewErrConstructorDependencies("sms.NewAdapter", "creds", "nil")
+	}
+	if httpClient == nil {
+		return nil, errorspkg.NewErrConstructorDependencies("sms.NewAdapter", "HTTPClient", "nil")
+	}
+	if texts == nil {
+		return nil, errorspkg.NewErrConstructorDependencies("sms.NewAdapter", "Renderer", "nil")
+	}
+	if logger == nil {
+		return nil, errorspkg.NewErrConstructorDependencies("sms.NewAdapter", "logger", "nil")
+	}
+
+	baseURL := creds.BaseURL
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+
+	return &Adapter{
+		http:        httpClient,
+		baseURL:     strings.TrimRight(baseURL, "/"),
+		apiKey:      creds.APIKey,
+		sender:      creds.Sender,
+		adminPhones: creds.AdminPhones,
+		texts:       texts,
+		logger:      logger.With(zeroslog.ServiceKey, smsService),
+	}, nil
+}
+
+// AdminRecipients lists the admin phones the dispatcher delivers to one by one.
+func (a *Adapter) AdminRecipients() []string {
+	return a.adminPhones
+}
+
+// ForAdmin returns the adapter limited to one admin phone.
+func (a *Adapter) ForAdmin(recipient string) (usecases.ChannelNotifier, bool) {
+	if !slices.Contains(a.adminPhones, recipient) {
+		return nil, false
+	}
+	scoped := *a
+	scoped.adminPhones = []string{recipient}
+	return &scoped, true
+}
+
+func (a *Adapter) ReservationCreatedForAdmin(msg entities.ReservationCreatedMessage) error {
+	return a.sendTemplate(a.adminPhones, usecases.TmplSMSCreatedAdmin, "", msg)
+}
+
+func (a *Adapter) ReservationCreatedForUser(msg entities.ReservationCreatedMessage, _ int64) error {
+	return a.sendTemplate([]string{msg.GuestPhone}, usecases.TmplSMSCreatedUser, msg.GuestLocale, msg)
+}
+
+func (a *Adapter) ReservationCancelledForAdmin(msg entities.ReservationCancelledMessage) error {
+	return a.sendTemplate(a.adminPhones, usecases.TmplSMSCancelledAdmin, "", msg)
+}
+
+func (a *Adapter) ReservationCancelledForUser(msg entities.ReservationCancelledMessage) error {
+	return a.sendTemplate([]string{msg.GuestPhone}, usecases.TmplSMSCancelledUser, msg.GuestLocale, msg)
+}
+
+func (a *Adapter) RemindUser(msg []entities.ReservationReminderNotification) error {
+	var errs []error
+	for _, m := range msg {
+		key := usecases.TmplSMSReminder
+		if m.Anchor == "check_out" {
+			key = usecases.TmplSMSReminderCheckOut
+		}
+		if err := a.sendTemplate([]string{m.GuestPhone}, key, m.GuestLocale, m); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (a *Adapter) NewApplicationForEvent(app entities.EventApplication) error {
+	return a.sendTemplate(a.adminPhones, usecases.TmplSMSEventApplication, "", app)
+}
+
+// sendTemplate renders the text once and sends it to every phone. Admin texts use the
+// default locale, as in the other channels.
+func (a *Adapter) sendTemplate(phones []string, key, locale string, data any) error {
+	ctx := context.Background()
+
+	text, err := a.texts.Render(ctx, key, locale, data)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, phone := range phones {
+		if phone == "" {
+			continue
+		}
+		if err = a.send(ctx, phone, text); err != nil {
+			a.logger.Error("send sms", zeroslog.ErrorKey, err, "to", phone)
+			errs = append(errs, fmt.Errorf("send sms to %s: %w", phone, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (a *Adapter) send(ctx context.Context, phone, text string) error {
+	// The gateway takes the international number without "+".
+	to, err := phonepkg.Normalize(phone)
+	if err != nil {
+		return err
+	}
+
+	form := url.Values{}
+	form.Set("api_id", a.apiKey)
+	form.Set("to", strings.TrimPrefix(to, "+"))
+	form.Set("msg", text)
+	form.Set("json", "1")
+	if a.sender != "" {
+		form.Set("from", a.sender)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.baseURL+"/sms/send", strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := a.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var body sendResponse
+	if err = json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	if body.Status != "OK" {
+		return fmt.Errorf("gateway: %s", body.StatusText)
+	}
+	for _, s := range body.SMS {
+		if s.Status != "OK" {
+			return fmt.Errorf("gateway: %s", s.StatusText)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,147 @@
+package sms
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/calyrexx/QuietGrooveBackend/internal/configuration"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/integrations/telegram"
+	"github.com/calyrexx/QuietGrooveBackend/internal/repository"
+	"github.com/calyrexx/QuietGrooveBackend/internal/usecases"
+	"github.com/google/uuid"
+)
+
+// noOverrides is a template store without admin overrides, so the built-in texts are used.
+type noOverrides struct {
+	repository.ITemplates
+}
+
+func (noOverrides) GetAll(context.Context) ([]entities.MessageTemplate, error) {
+	return nil, nil
+}
+
+// gateway records the forms posted to /sms/send and answers like SMS.ru.
+type gateway struct {
+	mu    sync.Mutex
+	forms []url.Values
+}
+
+func (g *gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/sms/send" || r.ParseForm() != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	g.mu.Lock()
+	g.forms = append(g.forms, r.PostForm)
+	g.mu.Unlock()
+	_, _ = io.WriteString(w, `{"status":"OK","sms":{"x":{"status":"OK"}}}`)
+}
+
+func newTestAdapter(t *testing.T, admins ...string) (*Adapter, *gateway) {
+	t.Helper()
+
+	gw := &gateway{}
+	srv := httptest.NewServer(gw)
+	t.Cleanup(srv.Close)
+
+	defaults, err := telegram.DefaultTemplates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	texts, err := usecases.NewTemplates(&usecases.TemplatesDependencies{
+		Repo:          noOverrides{},
+		Defaults:      defaults,
+		DefaultLocale: "ru",
+		Logger:        logger,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	adapter, err := NewAdapter(&configuration.SMS{
+		BaseURL:     srv.URL,
+		APIKey:      "key",
+		Sender:      "QuietGrove",
+		AdminPhones: admins,
+	}, srv.Client(), texts, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return adapter, gw
+}
+
+func TestGuestTextFollowsLocale(t *testing.T) {
+	adapter, gw := newTestAdapter(t)
+
+	msg := entities.ReservationCreatedMessage{
+		UUID:       uuid.New(),
+		HouseName:  "Лесной",
+		GuestPhone: "8 (999) 123-45-67",
+		CheckIn:    time.Date(2099, 7, 1, 0, 0, 0, 0, time.UTC),
+		CheckOut:   time.Date(2099, 7, 3, 0, 0, 0, 0, time.UTC),
+		TotalPrice: 15000,
+	}
+	for _, locale := range []string{"ru", "en"} {
+		msg.GuestLocale = locale
+		if err := adapter.ReservationCreatedForUser(msg, 0); err != nil {
+			t.Fatalf("%s: ReservationCreatedForUser: %v", locale, err)
+		}
+	}
+
+	if len(gw.forms) != 2 {
+		t.Fatalf("gateway got %d messages, want 2", len(gw.forms))
+	}
+	for i, want := range []string{"подтверждена", "is confirmed"} {
+		form := gw.forms[i]
+		if form.Get("to") != "79991234567" {
+			t.Errorf("message %d to = %q, want 79991234567", i, form.Get("to"))
+		}
+		if form.Get("api_id") != "key" || form.Get("from") != "QuietGrove" {
+			t.Errorf("message %d api_id = %q, from = %q", i, form.Get("api_id"), form.Get("from"))
+		}
+		text := form.Get("msg")
+		for _, part := range []string{want, "Лесной", "01.07.2099", "03.07.2099", "15000"} {
+			if !strings.Contains(text, part) {
+				t.Errorf("message %d has no %q: %s", i, part, text)
+			}
+		}
+	}
+}
+
+func TestAdminInvalidPhoneIsSkipped(t *testing.T) {
+	adapter, gw := newTestAdapter(t, "+7 999 000-00-00", "call me", "79990000001")
+
+	err := adapter.NewApplicationForEvent(entities.EventApplication{
+		ID:          12,
+		Name:        "Иван",
+		Phone:       "+79990000002",
+		CheckIn:     "2099-07-11",
+		GuestsCount: 20,
+	})
+	if err == nil || !strings.Contains(err.Error(), "call me") {
+		t.Fatalf("error = %v, want one for the invalid phone", err)
+	}
+
+	if len(gw.forms) != 2 {
+		t.Fatalf("gateway got %d messages, want 2", len(gw.forms))
+	}
+	for i, want := range []string{"79990000000", "79990000001"} {
+		if got := gw.forms[i].Get("to"); got != want {
+			t.Errorf("message %d to = %q, want %q", i, got, want)
+		}
+		if text := gw.forms[i].Get("msg"); !strings.Contains(text, "№12") || !strings.Contains(text, "2099.07.11") {
+			t.Errorf("message %d text = %q", i, text)
+		}
+	}
+}
@@ -0,0 +1,331 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/calyrexx/QuietGrooveBackend/internal/usecases"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"strings"
+	"time"
+)
+
+var adminDateLayouts = []string{"02.01.2006", "2006-01-02"}
+
+// adminOnly drops updates from anyone who is not in bot_admins, the bot doesn't
+// reveal that the commands exist.
+func (a *Adapter) adminOnly(next handlerFunc) handlerFunc {
+	return func(ctx context.Context, b Messenger, update *models.Update) {
+		var tgID int64
+		switch {
+		case update.CallbackQuery != nil:
+			tgID = update.CallbackQuery.From.ID
+		case update.Message != nil && update.Message.From != nil:
+			tgID = update.Message.From.ID
+		default:
+			return
+		}
+
+		if !a.adminSvc.IsAdmin(ctx, tgID) {
+			a.logger.Warn("admin command from unknown user", "tgID", tgID)
+			return
+		}
+		next(ctx, b, update)
+	}
+}
+
+func (a *Adapter) todayHandler(ctx context.Context, b Messenger, update *models.Update) {
+	a.sendAgenda(ctx, b, update, time.Now())
+}
+
+func (a *Adapter) tomorrowHandler(ctx context.Context, b Messenger, update *models.Update) {
+	a.sendAgenda(ctx, b, update, time.Now().AddDate(0, 0, 1))
+}
+
+func (a *Adapter) sendAgenda(ctx context.Context, b Messenger, update *models.Update, day time.Time) {
+	chatID := update.Message.Chat.ID
+	locale := updateLocale(update)
+
+	agenda, err := a.adminSvc.Agenda(ctx, day)
+	if err != nil {
+		a.logger.Error(err.Error())
+		a.reply(ctx, b, chatID, usecases.TmplAdminFailed, locale)
+		return
+	}
+
+	text, err := a.texts.Render(ctx, usecases.TmplAdminAgenda, locale, agenda)
+	if err != nil {
+		a.logger.Error(err.Error())
+		return
+	}
+
+	params := &bot.SendMessageParams{
+		ChatID:    chatID,
+		Text:      text,
+		ParseMode: "Markdown",
+	}
+	if len(agenda.Arrivals) > 0 {
+		params.ReplyMarkup = a.adminReservationsKeyboard(ctx, agenda.Arrivals, locale)
+	}
+
+	if _, err = b.SendMessage(ctx, params); err != nil {
+		a.logger.Error(err.Error())
+	}
+}
+
+// reservationHandler handles /reservation <uuid or phone>.
+func (a *Adapter) reservationHandler(ctx context.Context, b Messenger, update *models.Update) {
+	chatID := update.Message.Chat.ID
+	locale := updateLocale(update)
+
+	_, query, _ := strings.Cut(update.Message.Text, " ")
+	query = strings.TrimSpace(query)
+	if query == "" {
+		a.reply(ctx, b, chatID, usecases.TmplAdminUsageReservation, locale)
+		return
+	}
+
+	reservations, err := a.adminSvc.Find(ctx, query)
+	if err != nil {
+		var notFound *errorspkg.ErrRepoNotFound
+		switch {
+		case errors.As(err, &notFound):
+			a.reply(ctx, b, chatID, usecases.TmplAdminNotFound, locale)
+		case errors.Is(err, errorspkg.ErrPhoneQueryTooShort):
+			a.reply(ctx, b, chatID, usecases.TmplAdminUsageReservation, locale)
+		default:
+			a.logger.Error(err.Error())
+			a.reply(ctx, b, chatID, usecases.TmplAdminFailed, locale)
+		}
+		return
+	}
+
+	switch len(reservations) {
+	case 0:
+		a.reply(ctx, b, chatID, usecases.TmplAdminNotFound, locale)
+	case 1:
+		a.sendAdminReservation(ctx, b, chatID, reservations[0], locale)
+	default:
+		title, renderErr := a.texts.Render(ctx, usecases.TmplAdminFound, locale, nil)
+		if renderErr != nil {
+			a.logger.Error(renderErr.Error())
+			return
+		}
+		_, err = b.SendMessage(ctx, &bot.SendMessageParams{
+			ChatID:      chatID,
+			Text:        title,
+			ReplyMarkup: a.adminReservationsKeyboard(ctx, reservations, locale),
+		})
+		if err != nil {
+			a.logger.Error(err.Error())
+		}
+	}
+}
+
+// blockHandler handles /block <house> <from> <to> [reason], to is the check-out day.
+func (a *Adapter) blockHandler(ctx context.Context, b Messenger, update *models.Update) {
+	chatID := update.Message.Chat.ID
+	locale := updateLocale(update)
+
+	args := strings.Fields(update.Message.Text)
+	if len(args) < 4 {
+		a.reply(ctx, b, chatID, usecases.TmplAdminUsageBlock, locale)
+		return
+	}
+	from, fromErr := parseAdminDate(args[2])
+	to, toErr := parseAdminDate(args[3])
+	if fromErr != nil || toErr != nil {
+		a.reply(ctx, b, chatID, usecases.TmplAdminUsageBlock, locale)
+		return
+	}
+
+	blackout, err := a.adminSvc.Block(ctx, args[1], from, to, strings.Join(args[4:], " "))
+	if err != nil {
+		var unavailable *errorspkg.ErrHouseUnavailable
+		switch {
+		case errors.As(err, &unavailable):
+			a.reply(ctx, b, chatID, usecases.TmplAdminUnavailable, locale)
+		case errors.Is(err, errorspkg.ErrUnknownHouse):
+			a.reply(ctx, b, chatID, usecases.TmplAdminUnknownHouse, locale)
+		case errors.Is(err, errorspkg.ErrInvalidPeriod):
+			a.reply(ctx, b, chatID, usecases.TmplAdminUsageBlock, locale)
+		default:
+			a.logger.Error(err.Error())
+			a.reply(ctx, b, chatID, usecases.TmplAdminFailed, locale)
+		}
+		return
+	}
+
+	text, err := a.texts.Render(ctx, usecases.TmplAdminBlocked, locale, blackout)
+	if err != nil {
+		a.logger.Error(err.Error())
+		return
+	}
+	if _, err = b.SendMessage(ctx, &bot.SendMessageParams{ChatID: chatID, Text: text}); err != nil {
+		a.logger.Error(err.Error())
+	}
+}
+
+func (a *Adapter) adminViewCallback(ctx context.Context, b Messenger, update *models.Update) {
+	q := update.CallbackQuery
+	locale := updateLocale(update)
+
+	reservation, err := a.adminSvc.GetReservation(ctx, strings.TrimPrefix(q.Data, "adm_view_"))
+	if err != nil {
+		a.logger.Error(err.Error())
+		a.alert(ctx, b, q.ID, usecases.TmplAdminNotFound, locale)
+		return
+	}
+
+	a.sendAdminReservation(ctx, b, q.Message.Message.Chat.ID, reservation, locale)
+
+	if _, err = b.AnswerCallbackQuery(ctx, &bot.AnswerCallbackQueryParams{CallbackQueryID: q.ID}); err != nil {
+		a.logger.Error(err.Error())
+	}
+}
+
+func (a *Adapter) adminCancelCallback(ctx context.Context, b Messenger, update *models.Update) {
+	a.adminAction(ctx, b, update, "adm_cancel_", a.adminSvc.Cancel)
+}
+
+func (a *Adapter) adminCheckInCallback(ctx context.Context, b Messenger, update *models.Update) {
+	a.adminAction(ctx, b, update, "adm_checkin_", a.adminSvc.CheckIn)
+}
+
+func (a *Adapter) adminNoShowCallback(ctx context.Context, b Messenger, update *models.Update) {
+	a.adminAction(ctx, b, update, "adm_noshow_", a.adminSvc.NoShow)
+}
+
+// adminAction runs the action on the reservation from the callback data and redraws its card.
+func (a *Adapter) adminAction(
+	ctx context.Context,
+	b Messenger,
+	update *models.Update,
+	prefix string,
+	action func(ctx context.Context, reservationUUID string) error,
+) {
+	q := update.CallbackQuery
+	locale := updateLocale(update)
+	uuid := strings.TrimPrefix(q.Data, prefix)
+
+	if err := action(ctx, uuid); err != nil {
+		a.logger.Error(err.Error())
+		a.alert(ctx, b, q.ID, usecases.TmplAdminFailed, locale)
+		return
+	}
+	a.alert(ctx, b, q.ID, usecases.TmplAdminDone, locale)
+
+	reservation, err := a.adminSvc.GetReservation(ctx, uuid)
+	if err != nil {
+		a.logger.Error(err.Error())
+		return
+	}
+	text, err := a.texts.Render(ctx, usecases.TmplAdminReservation, locale, reservation)
+	if err != nil {
+		a.logger.Error(err.Error())
+		return
+	}
+
+	_, err = b.EditMessageText(ctx, &bot.EditMessageTextParams{
+		ChatID:      q.Message.Message.Chat.ID,
+		MessageID:   q.Message.Message.ID,
+		Text:        text,
+		ParseMode:   "Markdown",
+		ReplyMarkup: a.adminReservationKeyboard(ctx, reservation, locale),
+	})
+	if err != nil {
+		a.logger.Error(err.Error())
+	}
+}
+
+func (a *Adapter) sendAdminReservation(
+	ctx context.Context,
+	b Messenger,
+	chatID int64,
+	reservation entities.AdminReservation,
+	locale string,
+) {
+	text, err := a.texts.Render(ctx, usecases.TmplAdminReservation, locale, reservation)
+	if err != nil {
+		a.logger.Error(err.Error())
+		return
+	}
+
+	_, err = b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID:      chatID,
+		Text:        text,
+		ParseMode:   "Markdown",
+		ReplyMarkup: a.adminReservationKeyboard(ctx, reservation, locale),
+	})
+	if err != nil {
+		a.logger.Error(err.Error())
+	}
+}
+
+// adminReservationKeyboard offers only the transitions allowed from the current status.
+func (a *Adapter) adminReservationKeyboard(
+	ctx context.Context,
+	reservation entities.AdminReservation,
+	locale string,
+) models.ReplyMarkup {
+	var row []models.InlineKeyboardButton
+
+	switch reservation.Status {
+	case "confirmed":
+		row = append(row, models.InlineKeyboardButton{
+			Text:         a.buttonText(ctx, usecases.TmplButtonAdminCheckIn, locale),
+			CallbackData: fmt.Sprintf("adm_checkin_%s", reservation.UUID),
+		})
+		fallthrough
+	case "checked_in":
+		row = append(row, models.InlineKeyboardButton{
+			Text:         a.buttonText(ctx, usecases.TmplButtonAdminNoShow, locale),
+			CallbackData: fmt.Sprintf("adm_noshow_%s", reservation.UUID),
+		})
+		fallthrough
+	case "pending":
+		row = append(row, models.InlineKeyboardButton{
+			Text:         a.buttonText(ctx, usecases.TmplButtonAdminCancel, locale),
+			CallbackData: fmt.Sprintf("adm_cancel_%s", reservation.UUID),
+		})
+	}
+
+	if len(row) == 0 {
+		return nil
+	}
+	return &models.InlineKeyboardMarkup{InlineKeyboard: [][]models.InlineKeyboardButton{row}}
+}
+
+func (a *Adapter) adminReservationsKeyboard(
+	ctx context.Context,
+	reservations []entities.AdminReservation,
+	locale string,
+) *models.InlineKeyboardMarkup {
+	rows := make([][]models.InlineKeyboardButton, 0, len(reservations))
+	for _, res := range reservations {
+		text, err := a.texts.Render(ctx, usecases.TmplAdminReservationButton, locale, res)
+		if err != nil {
+			a.logger.Error(err.Error())
+			text = res.UUID.String()
+		}
+		rows = append(rows, []models.InlineKeyboardButton{{
+			Text:         text,
+			CallbackData: fmt.Sprintf("adm_view_%s", res.UUID),
+		}})
+	}
+	return &models.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+func parseAdminDate(s string) (time.Time, error) {
+	var err error
+	for _, layout := range adminDateLayouts {
+		var t time.Time
+		if t, err = time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, err
+}
@@ -0,0 +1,729 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/calyrexx/QuietGrooveBackend/internal/usecases"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	bookingPrefix   = "bk_"
+	bookingNoop     = bookingPrefix + "noop"
+	guestsPerRow    = 5
+	calendarColumns = 7
+)
+
+// bookingAction handles a booking callback; arg is the part of the callback data after the action.
+type bookingAction func(
+	ctx context.Context,
+	b Messenger,
+	q *models.CallbackQuery,
+	s entities.BookingSession,
+	locale, arg string,
+) error
+
+func (a *Adapter) bookHandler(ctx context.Context, b Messenger, update *models.Update) {
+	chatID := update.Message.Chat.ID
+	locale := updateLocale(update)
+
+	session, err := a.bookingSvc.Start(ctx, chatID, chatID)
+	if err != nil {
+		var notFound *errorspkg.ErrRepoNotFound
+		if errors.As(err, &notFound) {
+			a.reply(ctx, b, chatID, usecases.TmplBookingNotVerified, locale)
+			return
+		}
+		a.logger.Error(err.Error())
+		a.reply(ctx, b, chatID, usecases.TmplBookingFailed, locale)
+		return
+	}
+
+	text, err := a.texts.Render(ctx, usecases.TmplBookingCheckIn, locale, session)
+	if err != nil {
+		a.logger.Error(err.Error())
+		return
+	}
+
+	_, err = b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID:      chatID,
+		Text:        text,
+		ReplyMarkup: a.calendarKeyboard(ctx, session, locale),
+	})
+	if err != nil {
+		a.logger.Error(err.Error())
+	}
+}
+
+// bookingCallback dispatches bk_<action>[_<arg>] callbacks of the booking flow.
+func (a *Adapter) bookingCallback(ctx context.Context, b Messenger, update *models.Update) {
+	q := update.CallbackQuery
+	locale := updateLocale(update)
+	action, arg, _ := strings.Cut(strings.TrimPrefix(q.Data, bookingPrefix), "_")
+
+	var err error
+	switch action {
+	case "noop":
+	case "restart":
+		err = a.bookingRestart(ctx, b, q, locale)
+	default:
+		handler, ok := map[string]bookingAction{
+			"nav":        a.bookingNav,
+			"day":        a.bookingDay,
+			"guests":     a.bookingGuests,
+			"house":      a.bookingHouse,
+			"extra":      a.bookingExtra,
+			"extrasdone": a.bookingExtrasDone,
+			"bath":       a.bookingBath,
+			"fill":       a.bookingFill,
+			"bathdone":   a.bookingBathDone,
+			"confirm":    a.bookingConfirm,
+			"cancel":     a.bookingCancel,
+		}[action]
+		if !ok {
+			a.logger.Warn("unknown booking action", "data", q.Data)
+			return
+		}
+
+		var session entities.BookingSession
+		if session, err = a.bookingSvc.Session(ctx, q.Message.Message.Chat.ID); err == nil {
+			err = handler(ctx, b, q, session, locale, arg)
+		}
+	}
+
+	if err != nil {
+		a.bookingFailed(ctx, b, q.ID, locale, err)
+		return
+	}
+
+	if _, err = b.AnswerCallbackQuery(ctx, &bot.AnswerCallbackQueryParams{CallbackQueryID: q.ID}); err != nil {
+		a.logger.Error(err.Error())
+	}
+}
+
+func (a *Adapter) bookingFailed(ctx context.Context, b Messenger, queryID, locale string, err error) {
+	var (
+		notFound    *errorspkg.ErrRepoNotFound
+		unavailable *errorspkg.ErrHouseUnavailable
+	)
+	switch {
+	case errors.As(err, &notFound), errors.Is(err, errorspkg.ErrBookingStep):
+		a.alert(ctx, b, queryID, usecases.TmplBookingExpired, locale)
+	case errors.Is(err, errorspkg.ErrInvalidPeriod):
+		a.alert(ctx, b, queryID, usecases.TmplBookingInvalidDate, locale)
+	case errors.As(err, &unavailable):
+		a.alert(ctx, b, queryID, usecases.TmplBookingUnavailable, locale)
+	default:
+		a.logger.Error(err.Error())
+		a.alert(ctx, b, queryID, usecases.TmplBookingFailed, locale)
+	}
+}
+
+func (a *Adapter) bookingRestart(ctx context.Context, b Messenger, q *models.CallbackQuery, locale string) error {
+	session, err := a.bookingSvc.Start(ctx, q.Message.Message.Chat.ID, q.From.ID)
+	if err != nil {
+		return err
+	}
+	return a.editBooking(ctx, b, q, usecases.TmplBookingCheckIn, locale, session, a.calendarKeyboard(ctx, session, locale))
+}
+
+func (a *Adapter) bookingNav(
+	ctx context.Context,
+	b Messenger,
+	q *models.CallbackQuery,
+	s entities.BookingSession,
+	locale, arg string,
+) error {
+	month, err := time.ParseInLocation("2006-01", arg, time.Local)
+	if err != nil {
+		return errorspkg.ErrBookingStep
+	}
+	if s, err = a.bookingSvc.ShowMonth(ctx, s, month); err != nil {
+		return err
+	}
+
+	_, err = b.EditMessageReplyMarkup(ctx, &bot.EditMessageReplyMarkupParams{
+		ChatID:      q.Message.Message.Chat.ID,
+		MessageID:   q.Message.Message.ID,
+		ReplyMarkup: a.calendarKeyboard(ctx, s, locale),
+	})
+	return err
+}
+
+func (a *Adapter) bookingDay(
+	ctx context.Context,
+	b Messenger,
+	q *models.CallbackQuery,
+	s entities.BookingSession,
+	locale, arg string,
+) error {
+	day, err := time.ParseInLocation(time.DateOnly, arg, time.Local)
+	if err != nil {
+		return errorspkg.ErrBookingStep
+	}
+	if s, err = a.bookingSvc.PickDate(ctx, s, day); err != nil {
+		return err
+	}
+
+	if s.Step == entities.BookingCheckOut {
+		return a.editBooking(ctx, b, q, usecases.TmplBookingCheckOut, locale, s, a.calendarKeyboard(ctx, s, locale))
+	}
+
+	var (
+		rows [][]models.InlineKeyboardButton
+		row  []models.InlineKeyboardButton
+	)
+	for n := 1; n <= a.bookingSvc.MaxGuests(); n++ {
+		row = append(row, models.InlineKeyboardButton{
+			Text:         strconv.Itoa(n),
+			CallbackData: fmt.Sprintf("bk_guests_%d", n),
+		})
+		if len(row) == guestsPerRow {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+
+	return a.editBooking(ctx, b, q, usecases.TmplBookingGuests, locale, s, &models.InlineKeyboardMarkup{InlineKeyboard: rows})
+}
+
+func (a *Adapter) bookingGuests(
+	ctx context.Context,
+	b Messenger,
+	q *models.CallbackQuery,
+	s entities.BookingSession,
+	locale, arg string,
+) error {
+	count, err := strconv.Atoi(arg)
+	if err != nil {
+		return errorspkg.ErrBookingStep
+	}
+	if s, err = a.bookingSvc.PickGuests(ctx, s, count); err != nil {
+		return err
+	}
+
+	houses, err := a.bookingSvc.Houses(ctx, s)
+	if err != nil {
+		return err
+	}
+
+	if len(houses) == 0 {
+		return a.editBooking(ctx, b, q, usecases.TmplBookingNoHouses, locale, s, &models.InlineKeyboardMarkup{
+			InlineKeyboard: [][]models.InlineKeyboardButton{{{
+				Text:         a.buttonText(ctx, usecases.TmplButtonBookingRestart, locale),
+				CallbackData: "bk_restart",
+			}}},
+		})
+	}
+
+	if err = a.editBooking(ctx, b, q, usecases.TmplBookingHouses, locale, s, nil); err != nil {
+		return err
+	}
+
+	chatID := q.Message.Message.Chat.ID
+	for _, house := range houses {
+		if err = a.sendHouseCard(ctx, b, chatID, house, locale); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *Adapter) sendHouseCard(
+	ctx context.Context,
+	b Messenger,
+	chatID int64,
+	house usecases.GetAvailableHousesResponse,
+	locale string,
+) error {
+	text, err := a.texts.Render(ctx, usecases.TmplBookingHouse, locale, house)
+	if err != nil {
+		return err
+	}
+
+	markup := &models.InlineKeyboardMarkup{
+		InlineKeyboard: [][]models.InlineKeyboardButton{{{
+			Text:         a.buttonText(ctx, usecases.TmplButtonChoose, locale),
+			CallbackData: fmt.Sprintf("bk_house_%d", house.ID),
+		}}},
+	}
+
+	return a.sendPhoto(ctx, b, house.CoverImage, &bot.SendPhotoParams{
+		ChatID:      chatID,
+		Caption:     text,
+		ParseMode:   "Markdown",
+		ReplyMarkup: markup,
+	})
+}
+
+func (a *Adapter) bookingHouse(
+	ctx context.Context,
+	b Messenger,
+	q *models.CallbackQuery,
+	s entities.BookingSession,
+	locale, arg string,
+) error {
+	houseID, err := strconv.Atoi(arg)
+	if err != nil {
+		return errorspkg.ErrBookingStep
+	}
+	if s, _, err = a.bookingSvc.PickHouse(ctx, s, houseID); err != nil {
+		return err
+	}
+
+	extras, err := a.bookingSvc.Extras(ctx)
+	if err != nil {
+		return err
+	}
+
+	// The house card is a photo, so the next step goes into a new message.
+	chatID := q.Message.Message.Chat.ID
+	if len(extras) == 0 {
+		if s, err = a.bookingSvc.Next(ctx, s); err != nil {
+			return err
+		}
+		return a.sendBathhouseStep(ctx, b, chatID, s, locale)
+	}
+
+	text, err := a.texts.Render(ctx, usecases.TmplBookingExtras, locale, s)
+	if err != nil {
+		return err
+	}
+	_, err = b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID:      chatID,
+		Text:        text,
+		ReplyMarkup: a.extrasKeyboard(ctx, s, extras, locale),
+	})
+	return err
+}
+
+func (a *Adapter) bookingExtra(
+	ctx context.Context,
+	b Messenger,
+	q *models.CallbackQuery,
+	s entities.BookingSession,
+	locale, arg string,
+) error {
+	extraID, err := strconv.Atoi(arg)
+	if err != nil {
+		return errorspkg.ErrBookingStep
+	}
+	if s, err = a.bookingSvc.ToggleExtra(ctx, s, extraID); err != nil {
+		return err
+	}
+
+	extras, err := a.bookingSvc.Extras(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = b.EditMessageReplyMarkup(ctx, &bot.EditMessageReplyMarkupParams{
+		ChatID:      q.Message.Message.Chat.ID,
+		MessageID:   q.Message.Message.ID,
+		ReplyMarkup: a.extrasKeyboard(ctx, s, extras, locale),
+	})
+	return err
+}
+
+func (a *Adapter) bookingExtrasDone(
+	ctx context.Context,
+	b Messenger,
+	q *models.CallbackQuery,
+	s entities.BookingSession,
+	locale, _ string,
+) error {
+	if s.Step != entities.BookingExtras {
+		return errorspkg.ErrBookingStep
+	}
+	s, err := a.bookingSvc.Next(ctx, s)
+	if err != nil {
+		return err
+	}
+
+	house, err := a.bookingSvc.House(ctx, s, s.HouseID)
+	if err != nil {
+		return err
+	}
+	if len(house.Bathhouses) == 0 {
+		if s, err = a.bookingSvc.Next(ctx, s); err != nil {
+			return err
+		}
+		return a.editSummary(ctx, b, q, s, locale)
+	}
+
+	return a.editBooking(ctx, b, q, usecases.TmplBookingBathhouse, locale, s, a.bathhouseKeyboard(ctx, s, house, locale))
+}
+
+// sendBathhouseStep starts the bathhouse step in a new message, going straight to the
+// summary if the house has no bathhouse.
+func (a *Adapter) sendBathhouseStep(
+	ctx context.Context,
+	b Messenger,
+	chatID int64,
+	s entities.BookingSession,
+	locale string,
+) error {
+	house, err := a.bookingSvc.House(ctx, s, s.HouseID)
+	if err != nil {
+		return err
+	}
+
+	key := usecases.TmplBookingBathhouse
+	var (
+		data   any = s
+		markup models.ReplyMarkup
+	)
+	if len(house.Bathhouses) == 0 {
+		if s, err = a.bookingSvc.Next(ctx, s); err != nil {
+			return err
+		}
+		summary, summaryErr := a.bookingSvc.Summary(ctx, s)
+		if summaryErr != nil {
+			return summaryErr
+		}
+		key, data, markup = usecases.TmplBookingSummary, summary, a.confirmKeyboard(ctx, locale)
+	} else {
+		markup = a.bathhouseKeyboard(ctx, s, house, locale)
+	}
+
+	text, err := a.texts.Render(ctx, key, locale, data)
+	if err != nil {
+		return err
+	}
+	_, err = b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID:      chatID,
+		Text:        text,
+		ParseMode:   "Markdown",
+		ReplyMarkup: markup,
+	})
+	return err
+}
+
+func (a *Adapter) bookingBath(
+	ctx context.Context,
+	b Messenger,
+	q *models.CallbackQuery,
+	s entities.BookingSession,
+	locale, arg string,
+) error {
+	typeStr, date, _ := strings.Cut(arg, "_")
+	typeID, err := strconv.Atoi(typeStr)
+	if err != nil {
+		return errorspkg.ErrBookingStep
+	}
+
+	if s, _, err = a.bookingSvc.ToggleBathhouse(ctx, s, typeID, date); err != nil {
+		return err
+	}
+
+	house, err := a.bookingSvc.House(ctx, s, s.HouseID)
+	if err != nil {
+		return err
+	}
+
+	if s.Step != entities.BookingFillOption {
+		_, err = b.EditMessageReplyMarkup(ctx, &bot.EditMessageReplyMarkupParams{
+			ChatID:      q.Message.Message.Chat.ID,
+			MessageID:   q.Message.Message.ID,
+			ReplyMarkup: a.bathhouseKeyboard(ctx, s, house, locale),
+		})
+		return err
+	}
+
+	var rows [][]models.InlineKeyboardButton
+	for _, bh := range house.Bathhouses {
+		if bh.TypeID != typeID {
+			continue
+		}
+		for _, option := range bh.FillOption {
+			text := option.Name
+			if option.Price > 0 {
+				text = fmt.Sprintf("%s — %d ₽", option.Name, option.Price)
+			}
+			rows = append(rows, []models.InlineKeyboardButton{{
+				Text:         text,
+				CallbackData: fmt.Sprintf("bk_fill_%d_%s_%d", typeID, date, option.ID),
+			}})
+		}
+	}
+
+	return a.editBooking(ctx, b, q, usecases.TmplBookingFillOption, locale, s, &models.InlineKeyboardMarkup{InlineKeyboard: rows})
+}
+
+func (a *Adapter) bookingFill(
+	ctx context.Context,
+	b Messenger,
+	q *models.CallbackQuery,
+	s entities.BookingSession,
+	locale, arg string,
+) error {
+	parts := strings.Split(arg, "_")
+	if len(parts) != 3 {
+		return errorspkg.ErrBookingStep
+	}
+	typeID, typeErr := strconv.Atoi(parts[0])
+	optionID, optionErr := strconv.Atoi(parts[2])
+	if typeErr != nil || optionErr != nil {
+		return errorspkg.ErrBookingStep
+	}
+
+	s, err := a.bookingSvc.PickFillOption(ctx, s, typeID, parts[1], optionID)
+	if err != nil {
+		return err
+	}
+
+	house, err := a.bookingSvc.House(ctx, s, s.HouseID)
+	if err != nil {
+		return err
+	}
+	return a.editBooking(ctx, b, q, usecases.TmplBookingBathhouse, locale, s, a.bathhouseKeyboard(ctx, s, house, locale))
+}
+
+func (a *Adapter) bookingBathDone(
+	ctx context.Context,
+	b Messenger,
+	q *models.CallbackQuery,
+	s entities.BookingSession,
+	locale, _ string,
+) error {
+	if s.Step != entities.BookingBathhouse {
+		return errorspkg.ErrBookingStep
+	}
+	s, err := a.bookingSvc.Next(ctx, s)
+	if err != nil {
+		return err
+	}
+	return a.editSummary(ctx, b, q, s, locale)
+}
+
+func (a *Adapter) bookingConfirm(
+	ctx context.Context,
+	b Messenger,
+	q *models.CallbackQuery,
+	s entities.BookingSession,
+	locale, _ string,
+) error {
+	reservation, err := a.bookingSvc.Book(ctx, s, q.From.ID)
+	if err != nil {
+		var unavailable *errorspkg.ErrHouseUnavailable
+		if !errors.As(err, &unavailable) {
+			return err
+		}
+		return a.editBooking(ctx, b, q, usecases.TmplBookingUnavailable, locale, s, &models.InlineKeyboardMarkup{
+			InlineKeyboard: [][]models.InlineKeyboardButton{{{
+				Text:         a.buttonText(ctx, usecases.TmplButtonBookingRestart, locale),
+				CallbackData: "bk_restart",
+			}}},
+		})
+	}
+
+	return a.editBooking(ctx, b, q, usecases.TmplBookingCreated, locale, reservation, nil)
+}
+
+func (a *Adapter) bookingCancel(
+	ctx context.Context,
+	b Messenger,
+	q *models.CallbackQuery,
+	s entities.BookingSession,
+	locale, _ string,
+) error {
+	if err := a.bookingSvc.Cancel(ctx, s.ChatID); err != nil {
+		return err
+	}
+	return a.editBooking(ctx, b, q, usecases.TmplBookingCancelled, locale, nil, nil)
+}
+
+func (a *Adapter) editSummary(
+	ctx context.Context,
+	b Messenger,
+	q *models.CallbackQuery,
+	s entities.BookingSession,
+	locale string,
+) error {
+	summary, err := a.bookingSvc.Summary(ctx, s)
+	if err != nil {
+		return err
+	}
+	return a.editBooking(ctx, b, q, usecases.TmplBookingSummary, locale, summary, a.confirmKeyboard(ctx, locale))
+}
+
+// editBooking replaces the text and buttons of the message the callback came from.
+func (a *Adapter) editBooking(
+	ctx context.Context,
+	b Messenger,
+	q *models.CallbackQuery,
+	key, locale string,
+	data any,
+	markup models.ReplyMarkup,
+) error {
+	text, err := a.texts.Render(ctx, key, locale, data)
+	if err != nil {
+		return err
+	}
+
+	_, err = b.EditMessageText(ctx, &bot.EditMessageTextParams{
+		ChatID:      q.Message.Message.Chat.ID,
+		MessageID:   q.Message.Message.ID,
+		Text:        text,
+		ParseMode:   "Markdown",
+		ReplyMarkup: markup,
+	})
+	return err
+}
+
+// calendarKeyboard draws s.Month with Monday first; past days and, when picking the
+// check-out, days up to the check-in can't be pressed.
+func (a *Adapter) calendarKeyboard(ctx context.Context, s entities.BookingSession, locale string) *models.InlineKeyboardMarkup {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	month := s.Month
+	prev, next := month.AddDate(0, -1, 0), month.AddDate(0, 1, 0)
+
+	noop := func(text string) models.InlineKeyboardButton {
+		return models.InlineKeyboardButton{Text: text, CallbackData: bookingNoop}
+	}
+
+	prevButton := noop(" ")
+	if !prev.Before(today.AddDate(0, 0, 1-today.Day())) {
+		prevButton = models.InlineKeyboardButton{Text: "‹", CallbackData: "bk_nav_" + prev.Format("2006-01")}
+	}
+	rows := [][]models.InlineKeyboardButton{{
+		prevButton,
+		noop(month.Format("01.2006")),
+		{Text: "›", CallbackData: "bk_nav_" + next.Format("2006-01")},
+	}}
+
+	weekdays := make([]models.InlineKeyboardButton, 0, calendarColumns)
+	for _, day := range strings.Fields(a.buttonText(ctx, usecases.TmplBookingWeekdays, locale)) {
+		weekdays = append(weekdays, noop(day))
+	}
+	if len(weekdays) == calendarColumns {
+		rows = append(rows, weekdays)
+	}
+
+	row := make([]models.InlineKeyboardButton, 0, calendarColumns)
+	for i := 0; i < (int(month.Weekday())+6)%7; i++ {
+		row = append(row, noop(" "))
+	}
+	for day := month; day.Month() == month.Month(); day = day.AddDate(0, 0, 1) {
+		switch {
+		case day.Before(today), s.Step == entities.BookingCheckOut && !day.After(s.CheckIn):
+			row = append(row, noop("·"))
+		default:
+			row = append(row, models.InlineKeyboardButton{
+				Text:         strconv.Itoa(day.Day()),
+				CallbackData: "bk_day_" + day.Format(time.DateOnly),
+			})
+		}
+		if len(row) == calendarColumns {
+			rows = append(rows, row)
+			row = make([]models.InlineKeyboardButton, 0, calendarColumns)
+		}
+	}
+	if len(row) > 0 {
+		for len(row) < calendarColumns {
+			row = append(row, noop(" "))
+		}
+		rows = append(rows, row)
+	}
+
+	rows = append(rows, []models.InlineKeyboardButton{{
+		Text:         a.buttonText(ctx, usecases.TmplButtonBookingCancel, locale),
+		CallbackData: "bk_cancel",
+	}})
+	return &models.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+func (a *Adapter) extrasKeyboard(
+	ctx context.Context,
+	s entities.BookingSession,
+	extras []entities.Extra,
+	locale string,
+) *models.InlineKeyboardMarkup {
+	rows := make([][]models.InlineKeyboardButton, 0, len(extras)+1)
+	for _, extra := range extras {
+		text, err := a.texts.Render(ctx, usecases.TmplBookingExtraButton, locale, extra)
+		if err != nil {
+			a.logger.Error(err.Error())
+			text = extra.Name
+		}
+		if slices.Contains(s.Extras, extra.ID) {
+			text = "✅ " + text
+		}
+		rows = append(rows, []models.InlineKeyboardButton{{
+			Text:         text,
+			CallbackData: fmt.Sprintf("bk_extra_%d", extra.ID),
+		}})
+	}
+	rows = append(rows, []models.InlineKeyboardButton{{
+		Text:         a.buttonText(ctx, usecases.TmplButtonNext, locale),
+		CallbackData: "bk_extrasdone",
+	}})
+	return &models.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+func (a *Adapter) bathhouseKeyboard(
+	ctx context.Context,
+	s entities.BookingSession,
+	house usecases.GetAvailableHousesResponse,
+	locale string,
+) *models.InlineKeyboardMarkup {
+	var rows [][]models.InlineKeyboardButton
+	for _, bh := range house.Bathhouses {
+		for _, day := range bh.Slots {
+			if len(day.Time) == 0 {
+				continue
+			}
+			text, err := a.texts.Render(ctx, usecases.TmplBookingBathhouseButton, locale, entities.BathhouseMessage{
+				Name:     bh.Name,
+				Date:     day.Date,
+				TimeFrom: day.Time[0].TimeFrom,
+				TimeTo:   day.Time[0].TimeTo,
+			})
+			if err != nil {
+				a.logger.Error(err.Error())
+				text = bh.Name + " " + day.Date
+			}
+			if slices.ContainsFunc(s.Bathhouse, func(r entities.BathhouseReservation) bool {
+				return r.TypeID == bh.TypeID && r.Date == day.Date
+			}) {
+				text = "✅ " + text
+			}
+			rows = append(rows, []models.InlineKeyboardButton{{
+				Text:         text,
+				CallbackData: fmt.Sprintf("bk_bath_%d_%s", bh.TypeID, day.Date),
+			}})
+		}
+	}
+	rows = append(rows, []models.InlineKeyboardButton{{
+		Text:         a.buttonText(ctx, usecases.TmplButtonNext, locale),
+		CallbackData: "bk_bathdone",
+	}})
+	return &models.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+func (a *Adapter) confirmKeyboard(ctx context.Context, locale string) *models.InlineKeyboardMarkup {
+	return &models.InlineKeyboardMarkup{
+		InlineKeyboard: [][]models.InlineKeyboardButton{{
+			{
+				Text:         a.buttonText(ctx, usecases.TmplButtonBookingConfirm, locale),
+				CallbackData: "bk_confirm",
+			},
+			{
+				Text:         a.buttonText(ctx, usecases.TmplButtonBookingCancel, locale),
+				CallbackData: "bk_cancel",
+			},
+		}},
+	}
+}
@@ -0,0 +1,11 @@
+package telegram
+
+import (
+	"context"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/usecases"
+)
+
+func (a *Adapter) ImportedEventOverlaps(msg entities.CalendarConflictMessage) error {
+	return a.notifyAdmins(context.Background(), usecases.TmplCalendarConflict, msg)
+}
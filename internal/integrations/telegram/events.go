@@ -2,38 +2,107 @@ package telegram
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/calyrexx/QuietGrooveBackend/internal/usecases"
 	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"strconv"
 	"strings"
 )
 
-func (a *Adapter) NewApplicationForEvent(res entities.NewApplication) error {
+// applicationPrefix starts the callback data of the status buttons: evt_<status>_<id>.
+const applicationPrefix = "evt_"
+
+func (a *Adapter) NewApplicationForEvent(app entities.EventApplication) error {
 	ctx := context.Background()
+	return a.notifyAdminsWithMarkup(ctx, usecases.TmplEventApplication, app, a.applicationKeyboard(ctx, app, ""))
+}
+
+// applicationStatusCallback moves the application to the chosen status and redraws the message.
+func (a *Adapter) applicationStatusCallback(ctx context.Context, b Messenger, update *models.Update) {
+	q := update.CallbackQuery
+	locale := updateLocale(update)
+
+	status, rawID, _ := strings.Cut(strings.TrimPrefix(q.Data, applicationPrefix), "_")
+	id, err := strconv.Atoi(rawID)
+	if err != nil {
+		a.alert(ctx, b, q.ID, usecases.TmplAdminFailed, locale)
+		return
+	}
+
+	app, err := a.eventsSvc.SetApplicationStatus(ctx, id, entities.ApplicationStatus(status), managerName(q.From))
+	if err != nil {
+		var notFound *errorspkg.ErrRepoNotFound
+		switch {
+		case errors.As(err, &notFound):
+			a.alert(ctx, b, q.ID, usecases.TmplAdminNotFound, locale)
+		default:
+			a.logger.Error(err.Error())
+			a.alert(ctx, b, q.ID, usecases.TmplAdminFailed, locale)
+		}
+		return
+	}
+	a.alert(ctx, b, q.ID, usecases.TmplAdminDone, locale)
+
+	text, err := a.texts.Render(ctx, usecases.TmplEventApplication, locale, app)
+	if err != nil {
+		a.logger.Error(err.Error())
+		return
+	}
 
-	text := fmt.Sprintf(
-		"🎉 *Новая заявка на мероприятие!*\n"+
-			"👤 Имя: %s\n"+
-			"📞 Телефон: %s\n"+
-			"📅 Дата: %s\n"+
-			"👥 Кол-во гостей: %d",
-		res.Name,
-		res.Phone,
-		strings.ReplaceAll(res.CheckIn, "-", "."),
-		res.GuestsCount,
+	_, err = b.EditMessageText(ctx, &bot.EditMessageTextParams{
+		ChatID:      q.Message.Message.Chat.ID,
+		MessageID:   q.Message.Message.ID,
+		Text:        text,
+		ParseMode:   "Markdown",
+		ReplyMarkup: a.applicationKeyboard(ctx, app, locale),
+	})
+	if err != nil {
+		a.logger.Error(err.Error())
+	}
+}
+
+// applicationKeyboard offers every status except the current one, two buttons per row.
+func (a *Adapter) applicationKeyboard(
+	ctx context.Context,
+	app entities.EventApplication,
+	locale string,
+) *models.InlineKeyboardMarkup {
+	var (
+		rows [][]models.InlineKeyboardButton
+		row  []models.InlineKeyboardButton
 	)
+	for _, status := range usecases.ApplicationStatuses {
+		if status == app.Status {
+			continue
+		}
 
-	for _, chatID := range a.adminChatIDs {
-		_, err := a.bot.SendMessage(ctx,
-			&bot.SendMessageParams{
-				ChatID:    chatID,
-				Text:      text,
-				ParseMode: "Markdown",
-			},
-		)
+		text, err := a.texts.Render(ctx, usecases.TmplButtonApplicationStatus, locale, status)
 		if err != nil {
-			return err
+			a.logger.Error(err.Error())
+			text = string(status)
+		}
+		row = append(row, models.InlineKeyboardButton{
+			Text:         text,
+			CallbackData: fmt.Sprintf("%s%s_%d", applicationPrefix, status, app.ID),
+		})
+		if len(row) == 2 {
+			rows = append(rows, row)
+			row = nil
 		}
 	}
-	return nil
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+	return &models.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+func managerName(u models.User) string {
+	if u.Username != "" {
+		return "@" + u.Username
+	}
+	return strings.TrimSpace(u.FirstName + " " + u.LastName)
 }
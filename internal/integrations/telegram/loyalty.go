@@ -0,0 +1,43 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/calyrexx/QuietGrooveBackend/internal/usecases"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// loyaltyHandler shows the guest's tier and what is left to reach the next one.
+func (a *Adapter) loyaltyHandler(ctx context.Context, b Messenger, update *models.Update) {
+	tgID := update.Message.Chat.ID
+	locale := updateLocale(update)
+
+	status, err := a.loyaltySvc.StatusByTgID(ctx, tgID)
+	if err != nil {
+		var notFound *errorspkg.ErrRepoNotFound
+		if errors.As(err, &notFound) {
+			a.reply(ctx, b, tgID, usecases.TmplLoyaltyNotVerified, locale)
+			return
+		}
+		a.logger.Error(err.Error())
+		a.reply(ctx, b, tgID, usecases.TmplLoyaltyFailed, locale)
+		return
+	}
+
+	text, err := a.texts.Render(ctx, usecases.TmplLoyaltyStatus, locale, status)
+	if err != nil {
+		a.logger.Error(err.Error())
+		return
+	}
+
+	_, err = b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID:    tgID,
+		Text:      text,
+		ParseMode: "Markdown",
+	})
+	if err != nil {
+		a.logger.Error(err.Error())
+	}
+}
@@ -0,0 +1,54 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"github.com/calyrexx/zeroslog"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// Messenger is the part of the Bot API the handlers use, *bot.Bot implements it.
+type Messenger interface {
+	GetMe(ctx context.Context) (*models.User, error)
+	SendMessage(ctx context.Context, params *bot.SendMessageParams) (*models.Message, error)
+	SendPhoto(ctx context.Context, params *bot.SendPhotoParams) (*models.Message, error)
+	SendDocument(ctx context.Context, params *bot.SendDocumentParams) (*models.Message, error)
+	EditMessageText(ctx context.Context, params *bot.EditMessageTextParams) (*models.Message, error)
+	EditMessageCaption(ctx context.Context, params *bot.EditMessageCaptionParams) (*models.Message, error)
+	EditMessageReplyMarkup(ctx context.Context, params *bot.EditMessageReplyMarkupParams) (*models.Message, error)
+	AnswerCallbackQuery(ctx context.Context, params *bot.AnswerCallbackQueryParams) (bool, error)
+	DeleteMessage(ctx context.Context, params *bot.DeleteMessageParams) (bool, error)
+}
+
+// handlerFunc is bot.HandlerFunc that talks to Telegram only through Messenger.
+type handlerFunc func(ctx context.Context, b Messenger, update *models.Update)
+
+// handle adapts h to the library, the *bot.Bot it passes is replaced with a.messenger.
+func (a *Adapter) handle(h handlerFunc) bot.HandlerFunc {
+	return func(ctx context.Context, _ *bot.Bot, update *models.Update) {
+		h(ctx, a.messenger, update)
+	}
+}
+
+// ProcessUpdate dispatches the update to the registered handlers as if it came from Telegram.
+func (a *Adapter) ProcessUpdate(ctx context.Context, update *models.Update) {
+	a.bot.ProcessUpdate(ctx, update)
+}
+
+// sendPhoto sends the image at url, or the placeholder when there is none or Telegram can't
+// fetch it, e.g. a house without photos or a link to a storage that isn't public.
+func (a *Adapter) sendPhoto(ctx context.Context, b Messenger, url string, params *bot.SendPhotoParams) error {
+	if url != "" {
+		params.Photo = &models.InputFileString{Data: url}
+		_, err := b.SendPhoto(ctx, params)
+		if err == nil {
+			return nil
+		}
+		a.logger.Warn("send photo, falling back to placeholder", zeroslog.ErrorKey, err, "url", url)
+	}
+
+	params.Photo = &models.InputFileUpload{Filename: "placeholder.jpg", Data: bytes.NewReader(placeholderImage)}
+	_, err := b.SendPhoto(ctx, params)
+	return err
+}
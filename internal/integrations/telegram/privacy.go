@@ -0,0 +1,123 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/calyrexx/QuietGrooveBackend/internal/usecases"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+const (
+	privacyErase      = "privacy_erase"
+	privacyExportFile = "quietgrove-data.json"
+)
+
+// myDataHandler sends the guest linked to the chat everything stored about them as a file.
+func (a *Adapter) myDataHandler(ctx context.Context, b Messenger, update *models.Update) {
+	tgID := update.Message.Chat.ID
+	locale := updateLocale(update)
+
+	export, err := a.guestSvc.ExportByTgID(ctx, tgID)
+	if err != nil {
+		a.reply(ctx, b, tgID, a.privacyErrorTemplate(err), locale)
+		return
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		a.logger.Error(err.Error())
+		a.reply(ctx, b, tgID, usecases.TmplPrivacyFailed, locale)
+		return
+	}
+
+	caption, err := a.texts.Render(ctx, usecases.TmplPrivacyExport, locale, nil)
+	if err != nil {
+		a.logger.Error(err.Error())
+		return
+	}
+
+	_, err = b.SendDocument(ctx, &bot.SendDocumentParams{
+		ChatID:   tgID,
+		Document: &models.InputFileUpload{Filename: privacyExportFile, Data: bytes.NewReader(data)},
+		Caption:  caption,
+	})
+	if err != nil {
+		a.logger.Error(err.Error())
+	}
+}
+
+// forgetMeHandler asks for confirmation, the data is erased by eraseCallback.
+func (a *Adapter) forgetMeHandler(ctx context.Context, b Messenger, update *models.Update) {
+	tgID := update.Message.Chat.ID
+	locale := updateLocale(update)
+
+	text, err := a.texts.Render(ctx, usecases.TmplPrivacyEraseConfirm, locale, nil)
+	if err != nil {
+		a.logger.Error(err.Error())
+		return
+	}
+
+	_, err = b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID: tgID,
+		Text:   text,
+		ReplyMarkup: &models.InlineKeyboardMarkup{
+			InlineKeyboard: [][]models.InlineKeyboardButton{
+				{
+					{
+						Text:         a.buttonText(ctx, usecases.TmplButtonPrivacyErase, locale),
+						CallbackData: privacyErase,
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		a.logger.Error(err.Error())
+	}
+}
+
+func (a *Adapter) eraseCallback(ctx context.Context, b Messenger, update *models.Update) {
+	if update.CallbackQuery == nil {
+		return
+	}
+	q := update.CallbackQuery
+	tgID := q.From.ID
+	locale := updateLocale(update)
+
+	key := usecases.TmplPrivacyErased
+	if err := a.guestSvc.EraseByTgID(ctx, tgID); err != nil {
+		key = a.privacyErrorTemplate(err)
+	}
+
+	text, err := a.texts.Render(ctx, key, locale, nil)
+	if err != nil {
+		a.logger.Error(err.Error())
+		return
+	}
+
+	_, err = b.EditMessageText(ctx, &bot.EditMessageTextParams{
+		ChatID:    tgID,
+		MessageID: q.Message.Message.ID,
+		Text:      text,
+	})
+	if err != nil {
+		a.logger.Error(err.Error())
+	}
+}
+
+func (a *Adapter) privacyErrorTemplate(err error) string {
+	var notFound *errorspkg.ErrRepoNotFound
+	switch {
+	case errors.As(err, &notFound):
+		return usecases.TmplPrivacyNotFound
+	case errors.Is(err, errorspkg.ErrGuestHasActiveStays):
+		return usecases.TmplPrivacyActiveStays
+	default:
+		a.logger.Error(err.Error())
+		return usecases.TmplPrivacyFailed
+	}
+}
@@ -2,119 +2,78 @@ package telegram
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/usecases"
 	"github.com/go-telegram/bot"
 	"github.com/go-telegram/bot/models"
 	"strings"
 )
 
 func (a *Adapter) ReservationCreatedForAdmin(msg entities.ReservationCreatedMessage) error {
-	ctx := context.Background()
-
-	text := fmt.Sprintf(
-		"✅ *Новое бронирование*\n"+
-			"🏠 Дом: %s\n"+
-			"👤 Гость: %s\n"+
-			"📞 %s\n"+
-			"📅 %s → %s\n"+
-			"👥 %d гостей\n"+
-			"💳 %d ₽\n",
-		msg.HouseName, msg.GuestName, msg.GuestPhone,
-		msg.CheckIn.Format("02.01.2006"), msg.CheckOut.Format("02.01.2006"),
-		msg.GuestsCount, msg.TotalPrice,
-	)
-
-	if len(msg.Bathhouse) > 0 {
-		text += "\n🔥 *Забронированы дополнительно:*\n"
-		for _, bath := range msg.Bathhouse {
-			fillOpt := ""
-			if bath.FillOption != nil {
-				fillOpt = "(" + *bath.FillOption + ")"
-			}
-			text += fmt.Sprintf(
-				"• %s: %s с %s до %s %s\n",
-				bath.Name,
-				strings.ReplaceAll(bath.Date, "-", "."),
-				bath.TimeFrom,
-				bath.TimeTo,
-				fillOpt,
-			)
-		}
-	}
-
-	for _, chatID := range a.adminChatIDs {
-		_, err := a.bot.SendMessage(ctx,
-			&bot.SendMessageParams{
-				ChatID:    chatID,
-				Text:      text,
-				ParseMode: "Markdown",
-			},
-		)
-		if err != nil {
-			return err
-		}
-	}
-	return nil
+	return a.notifyAdmins(context.Background(), usecases.TmplReservationCreatedAdmin, msg)
 }
 
 func (a *Adapter) ReservationCreatedForUser(msg entities.ReservationCreatedMessage, tgID int64) error {
 	ctx := context.Background()
 
-	text := fmt.Sprintf(
-		"✅ *Ваше бронирование подтверждено!*\n"+
-			"🏠 Дом: %s\n"+
-			"📅 %s → %s\n"+
-			"👥 %d гостей\n"+
-			"💳 Стоимость проживания: %d ₽\n"+
-			"📞 Наш номер для связи: +79867427283\n",
-		msg.HouseName,
-		msg.CheckIn.Format("02.01.2006"), msg.CheckOut.Format("02.01.2006"),
-		msg.GuestsCount, msg.TotalPrice,
-	)
-
-	if len(msg.Bathhouse) > 0 {
-		text += "\n🔥 *Забронированы дополнительно:*\n"
-		for _, bath := range msg.Bathhouse {
-			fillOpt := ""
-			if bath.FillOption != nil {
-				fillOpt = "(" + *bath.FillOption + ")"
-			}
-			text += fmt.Sprintf(
-				"• %s: %s с %s до %s %s\n",
-				bath.Name,
-				strings.ReplaceAll(bath.Date, "-", "."),
-				bath.TimeFrom,
-				bath.TimeTo,
-				fillOpt,
-			)
-		}
+	text, err := a.texts.Render(ctx, usecases.TmplReservationCreatedUser, msg.GuestLocale, msg)
+	if err != nil {
+		return err
 	}
 
-	_, err := a.bot.SendMessage(ctx,
+	return a.notify(ctx,
 		&bot.SendMessageParams{
 			ChatID:    tgID,
 			Text:      text,
 			ParseMode: "Markdown",
 		},
 	)
+}
+
+func (a *Adapter) ReservationCancelledForAdmin(msg entities.ReservationCancelledMessage) error {
+	return a.notifyAdmins(context.Background(), usecases.TmplReservationCancelledAdmin, msg)
+}
+
+func (a *Adapter) ReservationCancelledForUser(msg entities.ReservationCancelledMessage) error {
+	if msg.GuestTgID == 0 {
+		return nil
+	}
+	ctx := context.Background()
+
+	text, err := a.texts.Render(ctx, usecases.TmplReservationCancelledUser, msg.GuestLocale, msg)
 	if err != nil {
 		return err
 	}
-	return nil
+
+	return a.notify(ctx,
+		&bot.SendMessageParams{
+			ChatID:    msg.GuestTgID,
+			Text:      text,
+			ParseMode: "Markdown",
+		},
+	)
 }
 
 func (a *Adapter) RemindUser(msg []entities.ReservationReminderNotification) error {
 	ctx := context.Background()
 
+	var errs []error
+
 	for _, m := range msg {
-		text := fmt.Sprintf(
-			"Уважаемый гость!\n"+
-				"Ваше бронирование домика *%s* скоро начнётся!",
-			m.HouseName,
-		)
+		text, err := a.texts.Render(ctx, reminderTemplate(m), m.GuestLocale, m)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		button, err := a.texts.Render(ctx, usecases.TmplReminderButton, m.GuestLocale, m)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
 
-		_, err := a.bot.SendMessage(ctx,
+		err = a.notify(ctx,
 			&bot.SendMessageParams{
 				ChatID:    m.UserTgID,
 				Text:      text,
@@ -123,7 +82,7 @@ func (a *Adapter) RemindUser(msg []entities.ReservationReminderNotification) err
 					InlineKeyboard: [][]models.InlineKeyboardButton{
 						{
 							{
-								Text:         "Просмотреть бронирование 👀",
+								Text:         button,
 								CallbackData: fmt.Sprintf("view_resv_%s", m.UUID),
 							},
 						},
@@ -133,18 +92,31 @@ func (a *Adapter) RemindUser(msg []entities.ReservationReminderNotification) err
 		)
 		if err != nil {
 			a.logger.Error(err.Error())
+			errs = append(errs, err)
 		}
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
 
-func (a *Adapter) myReservationsHandler(ctx context.Context, b *bot.Bot, u *models.Update) {
+func reminderTemplate(m entities.ReservationReminderNotification) string {
+	switch {
+	case m.Anchor == "check_out":
+		return usecases.TmplReminderCheckOut
+	case m.DaysBefore == 0:
+		return usecases.TmplReminderCheckIn
+	default:
+		return usecases.TmplReminder
+	}
+}
+
+func (a *Adapter) myReservationsHandler(ctx context.Context, b Messenger, u *models.Update) {
 	var (
 		tgID                  int64
 		messageIdToDeleteBot  int
 		messageIdToDeleteUser int
 	)
+	locale := updateLocale(u)
 	if u.CallbackQuery == nil {
 		tgID = u.Message.Chat.ID
 		messageIdToDeleteUser = u.Message.ID
@@ -154,7 +126,7 @@ func (a *Adapter) myReservationsHandler(ctx context.Context, b *bot.Bot, u *mode
 	}
 
 	if messageIdToDeleteBot > 0 {
-		_, err := a.bot.DeleteMessage(ctx, &bot.DeleteMessageParams{
+		_, err := b.DeleteMessage(ctx, &bot.DeleteMessageParams{
 			ChatID:    tgID,
 			MessageID: messageIdToDeleteBot,
 		})
@@ -164,7 +136,7 @@ func (a *Adapter) myReservationsHandler(ctx context.Context, b *bot.Bot, u *mode
 	}
 
 	if messageIdToDeleteUser > 0 {
-		_, err := a.bot.DeleteMessage(ctx, &bot.DeleteMessageParams{
+		_, err := b.DeleteMessage(ctx, &bot.DeleteMessageParams{
 			ChatID:    tgID,
 			MessageID: messageIdToDeleteUser,
 		})
@@ -175,34 +147,22 @@ func (a *Adapter) myReservationsHandler(ctx context.Context, b *bot.Bot, u *mode
 
 	reservations, err := a.reservationSvc.GetByTelegramID(ctx, tgID)
 	if err != nil {
-		_, err = b.SendMessage(ctx, &bot.SendMessageParams{
-			ChatID: tgID,
-			Text:   "⚠️ Не удалось получить бронирования. Попробуйте позже.",
-		})
-		if err != nil {
-			a.logger.Error(err.Error())
-		}
+		a.reply(ctx, b, tgID, usecases.TmplReservationsLoadFailed, locale)
 		return
 	}
 
 	if len(reservations) == 0 {
-		_, err = b.SendMessage(ctx, &bot.SendMessageParams{
-			ChatID: tgID,
-			Text:   "У вас пока нет бронирований.",
-		})
-		if err != nil {
-			a.logger.Error(err.Error())
-		}
+		a.reply(ctx, b, tgID, usecases.TmplReservationsEmpty, locale)
 		return
 	}
 
 	rows := make([][]models.InlineKeyboardButton, 0, len(reservations))
 	for _, res := range reservations {
-		text := fmt.Sprintf(
-			"📅 %s → %s 🏠 %s",
-			res.CheckIn.Format("02.01"),
-			res.CheckOut.Format("02.01"),
-			res.HouseName)
+		text, renderErr := a.texts.Render(ctx, usecases.TmplReservationButton, locale, res)
+		if renderErr != nil {
+			a.logger.Error(renderErr.Error())
+			return
+		}
 		btn := models.InlineKeyboardButton{
 			Text:         text,
 			CallbackData: fmt.Sprintf("view_resv_%s", res.UUID),
@@ -214,9 +174,15 @@ func (a *Adapter) myReservationsHandler(ctx context.Context, b *bot.Bot, u *mode
 		InlineKeyboard: rows,
 	}
 
+	title, err := a.texts.Render(ctx, usecases.TmplReservationsListTitle, locale, nil)
+	if err != nil {
+		a.logger.Error(err.Error())
+		return
+	}
+
 	_, err = b.SendMessage(ctx, &bot.SendMessageParams{
 		ChatID:      tgID,
-		Text:        "Ваши бронирования:",
+		Text:        title,
 		ReplyMarkup: kb,
 	})
 	if err != nil {
@@ -224,7 +190,7 @@ func (a *Adapter) myReservationsHandler(ctx context.Context, b *bot.Bot, u *mode
 	}
 }
 
-func (a *Adapter) viewReservationCallback(ctx context.Context, b *bot.Bot, update *models.Update) {
+func (a *Adapter) viewReservationCallback(ctx context.Context, b Messenger, update *models.Update) {
 	if update.CallbackQuery == nil {
 		return
 	}
@@ -232,76 +198,34 @@ func (a *Adapter) viewReservationCallback(ctx context.Context, b *bot.Bot, updat
 
 	uuid := strings.TrimPrefix(q.Data, "view_resv_")
 	tgID := q.Message.Message.Chat.ID
+	locale := updateLocale(update)
 
 	reservation, err := a.reservationSvc.GetDetailsByUUID(ctx, tgID, uuid)
 	if err != nil {
-		_, err = b.AnswerCallbackQuery(ctx, &bot.AnswerCallbackQueryParams{
-			CallbackQueryID: q.ID,
-			Text:            "Бронирование не найдено.",
-			ShowAlert:       true,
-		})
-		if err != nil {
-			a.logger.Error(err.Error())
-		}
+		a.alert(ctx, b, q.ID, usecases.TmplReservationNotFound, locale)
 		return
 	}
 
-	var (
-		statusMsg string
-		canCancel bool
-	)
-	switch reservation.Status {
-	case "confirmed":
-		statusMsg = "Подтверждено ✅"
-		canCancel = true
-	case "cancelled":
-		statusMsg = "Отменено ❌"
-	case "checked_in":
-		statusMsg = "В процессе ▶"
-	case "checked_out":
-		statusMsg = "Завершено ✅"
-	}
+	canCancel := reservation.Status == "confirmed"
 
-	msg := fmt.Sprintf(
-		"🏠 Дом: %s\n"+
-			"📅 %s → %s\n"+
-			"👥 %d гостей\n"+
-			"💳 Стоимость проживания: %d₽\n"+
-			"ℹ️ Статус: %s\n",
-		reservation.HouseName,
-		reservation.CheckIn.Format("02.01.2006"),
-		reservation.CheckOut.Format("02.01.2006"),
-		reservation.GuestsCount,
-		reservation.TotalPrice,
-		statusMsg,
-	)
-
-	if len(reservation.Bathhouse) > 0 {
-		msg += "\n🔥 *Забронированы дополнительно*:\n"
-		for _, bath := range reservation.Bathhouse {
-			fillOpt := ""
-			if bath.FillOptionName != nil {
-				fillOpt = "(" + *bath.FillOptionName + ")"
-			}
-			msg += fmt.Sprintf("• %s: %s с %s до %s %s\n", bath.Name, bath.Date, bath.TimeFrom, bath.TimeTo, fillOpt)
-		}
+	msg, err := a.texts.Render(ctx, usecases.TmplReservationDetails, locale, reservation)
+	if err != nil {
+		a.logger.Error(err.Error())
+		return
 	}
 
-	photo := &models.InputFileString{Data: reservation.ImageURL}
-
-	_, err = b.SendPhoto(ctx, &bot.SendPhotoParams{
+	err = a.sendPhoto(ctx, b, reservation.ImageURL, &bot.SendPhotoParams{
 		ChatID:      tgID,
-		Photo:       photo,
 		Caption:     msg,
 		ParseMode:   "Markdown",
-		ReplyMarkup: a.buildReservationDetailKeyboard(uuid, canCancel),
+		ReplyMarkup: a.buildReservationDetailKeyboard(ctx, uuid, locale, canCancel),
 	})
 	if err != nil {
 		a.logger.Error(err.Error())
 		return
 	}
 
-	_, err = a.bot.DeleteMessage(ctx, &bot.DeleteMessageParams{
+	_, err = b.DeleteMessage(ctx, &bot.DeleteMessageParams{
 		ChatID:    tgID,
 		MessageID: q.Message.Message.ID,
 	})
@@ -310,7 +234,7 @@ func (a *Adapter) viewReservationCallback(ctx context.Context, b *bot.Bot, updat
 	}
 }
 
-func (a *Adapter) cancelReservationCallback(ctx context.Context, b *bot.Bot, update *models.Update) {
+func (a *Adapter) cancelReservationCallback(ctx context.Context, b Messenger, update *models.Update) {
 	if update.CallbackQuery == nil {
 		return
 	}
@@ -318,92 +242,59 @@ func (a *Adapter) cancelReservationCallback(ctx context.Context, b *bot.Bot, upd
 
 	uuid := strings.TrimPrefix(q.Data, "cancel_resv_")
 	tgID := q.Message.Message.Chat.ID
+	locale := updateLocale(update)
 
 	reservation, err := a.reservationSvc.GetDetailsByUUID(ctx, tgID, uuid)
 	if err != nil {
-		_, err = b.AnswerCallbackQuery(ctx, &bot.AnswerCallbackQueryParams{
-			CallbackQueryID: q.ID,
-			Text:            "⚠ Бронирование не найдено.",
-			ShowAlert:       true,
-		})
-		if err != nil {
-			a.logger.Error(err.Error())
-		}
+		a.alert(ctx, b, q.ID, usecases.TmplReservationNotFound, locale)
 		return
 	}
 
-	msg := fmt.Sprintf(
-		"🏠 Дом: %s\n"+
-			"📅 %s → %s\n"+
-			"👥 %d гостей\n"+
-			"💳 Стоимость проживания: %d₽\n"+
-			"ℹ️ Статус: Отменено ❌\n",
-		reservation.HouseName,
-		reservation.CheckIn.Format("02.01.2006"),
-		reservation.CheckOut.Format("02.01.2006"),
-		reservation.GuestsCount,
-		reservation.TotalPrice,
-	)
-
-	if len(reservation.Bathhouse) > 0 {
-		msg += "\n🔥 *Забронированы дополнительно*:\n"
-		for _, bath := range reservation.Bathhouse {
-			fillOpt := ""
-			if bath.FillOptionName != nil {
-				fillOpt = "(" + *bath.FillOptionName + ")"
-			}
-			msg += fmt.Sprintf("• %s: %s с %s до %s %s\n", bath.Name, bath.Date, bath.TimeFrom, bath.TimeTo, fillOpt)
-		}
+	reservation.Status = "cancelled"
+	msg, err := a.texts.Render(ctx, usecases.TmplReservationDetails, locale, reservation)
+	if err != nil {
+		a.logger.Error(err.Error())
+		return
 	}
 
 	err = a.reservationSvc.Cancel(ctx, tgID, uuid)
 	if err != nil {
-		_, err = b.AnswerCallbackQuery(ctx, &bot.AnswerCallbackQueryParams{
-			CallbackQueryID: q.ID,
-			Text:            "⚠️Не удалось отменить бронирование. Попробуйте позже.",
-			ShowAlert:       true,
-		})
-		if err != nil {
-			a.logger.Error(err.Error())
-		}
+		a.alert(ctx, b, q.ID, usecases.TmplReservationCancelFailed, locale)
 		return
 	}
 
-	_, err = b.AnswerCallbackQuery(ctx, &bot.AnswerCallbackQueryParams{
-		CallbackQueryID: q.ID,
-		Text:            "Ваше бронирование отменено!",
-		ShowAlert:       true,
-	})
-	if err != nil {
-		a.logger.Error(err.Error())
-	}
+	a.alert(ctx, b, q.ID, usecases.TmplReservationCancelledAlert, locale)
 
 	_, err = b.EditMessageCaption(ctx, &bot.EditMessageCaptionParams{
 		ChatID:      tgID,
 		MessageID:   q.Message.Message.ID,
 		Caption:     msg,
 		ParseMode:   "Markdown",
-		ReplyMarkup: a.buildReservationDetailKeyboard(uuid, false),
+		ReplyMarkup: a.buildReservationDetailKeyboard(ctx, uuid, locale, false),
 	})
 	if err != nil {
 		a.logger.Error(err.Error())
 	}
 }
 
-func (a *Adapter) buildReservationDetailKeyboard(reservationUUID string, canCancel bool) *models.InlineKeyboardMarkup {
+func (a *Adapter) buildReservationDetailKeyboard(
+	ctx context.Context,
+	reservationUUID, locale string,
+	canCancel bool,
+) *models.InlineKeyboardMarkup {
 	var rows [][]models.InlineKeyboardButton
 
 	if canCancel {
 		rows = append(rows, []models.InlineKeyboardButton{
 			{
-				Text:         "Отменить бронирование ❌",
+				Text:         a.buttonText(ctx, usecases.TmplButtonCancelReservation, locale),
 				CallbackData: fmt.Sprintf("cancel_resv_%s", reservationUUID),
 			},
 		})
 	}
 	rows = append(rows, []models.InlineKeyboardButton{
 		{
-			Text:         "⬅️ Назад",
+			Text:         a.buttonText(ctx, usecases.TmplButtonBack, locale),
 			CallbackData: "my_reservations_back",
 		},
 	})
@@ -0,0 +1,138 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/calyrexx/QuietGrooveBackend/internal/usecases"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"strconv"
+	"strings"
+)
+
+func (a *Adapter) RequestFeedback(msg entities.FeedbackRequest) error {
+	ctx := context.Background()
+
+	text, err := a.texts.Render(ctx, usecases.TmplFeedbackRequest, msg.GuestLocale, msg)
+	if err != nil {
+		return err
+	}
+
+	stars := make([]models.InlineKeyboardButton, 0, 5)
+	for rating := 1; rating <= 5; rating++ {
+		stars = append(stars, models.InlineKeyboardButton{
+			Text:         strconv.Itoa(rating) + " ⭐",
+			CallbackData: fmt.Sprintf("review_rate_%d_%s", rating, msg.ReservationUUID),
+		})
+	}
+
+	return a.notify(ctx,
+		&bot.SendMessageParams{
+			ChatID:    msg.GuestTgID,
+			Text:      text,
+			ParseMode: "Markdown",
+			ReplyMarkup: &models.InlineKeyboardMarkup{
+				InlineKeyboard: [][]models.InlineKeyboardButton{stars},
+			},
+		},
+	)
+}
+
+// rateCallback handles review_rate_<rating>_<reservation uuid>.
+func (a *Adapter) rateCallback(ctx context.Context, b Messenger, update *models.Update) {
+	if update.CallbackQuery == nil {
+		return
+	}
+	q := update.CallbackQuery
+	tgID := q.From.ID
+	locale := updateLocale(update)
+
+	ratingStr, uuid, _ := strings.Cut(strings.TrimPrefix(q.Data, "review_rate_"), "_")
+	rating, err := strconv.Atoi(ratingStr)
+	if err == nil {
+		err = a.reviewSvc.Rate(ctx, tgID, uuid, rating)
+	}
+	if err != nil {
+		a.logger.Error(err.Error())
+		a.alert(ctx, b, q.ID, usecases.TmplFeedbackFailed, locale)
+		return
+	}
+
+	text, err := a.texts.Render(ctx, usecases.TmplFeedbackCommentPrompt, locale, nil)
+	if err != nil {
+		a.logger.Error(err.Error())
+		return
+	}
+
+	_, err = b.EditMessageText(ctx, &bot.EditMessageTextParams{
+		ChatID:    tgID,
+		MessageID: q.Message.Message.ID,
+		Text:      text,
+		ReplyMarkup: &models.InlineKeyboardMarkup{
+			InlineKeyboard: [][]models.InlineKeyboardButton{
+				{
+					{
+						Text:         a.buttonText(ctx, usecases.TmplButtonSkip, locale),
+						CallbackData: fmt.Sprintf("review_skip_%s", uuid),
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		a.logger.Error(err.Error())
+	}
+}
+
+func (a *Adapter) skipCommentCallback(ctx context.Context, b Messenger, update *models.Update) {
+	if update.CallbackQuery == nil {
+		return
+	}
+	q := update.CallbackQuery
+	tgID := q.From.ID
+	locale := updateLocale(update)
+
+	if err := a.reviewSvc.SkipComment(ctx, tgID, strings.TrimPrefix(q.Data, "review_skip_")); err != nil {
+		a.logger.Error(err.Error())
+		a.alert(ctx, b, q.ID, usecases.TmplFeedbackFailed, locale)
+		return
+	}
+
+	text, err := a.texts.Render(ctx, usecases.TmplFeedbackThanks, locale, nil)
+	if err != nil {
+		a.logger.Error(err.Error())
+		return
+	}
+
+	_, err = b.EditMessageText(ctx, &bot.EditMessageTextParams{
+		ChatID:    tgID,
+		MessageID: q.Message.Message.ID,
+		Text:      text,
+	})
+	if err != nil {
+		a.logger.Error(err.Error())
+	}
+}
+
+// reviewCommentHandler stores free text as the comment of a review waiting for one;
+// text from guests who have nothing to comment on is ignored.
+func (a *Adapter) reviewCommentHandler(ctx context.Context, b Messenger, update *models.Update) {
+	tgID := update.Message.Chat.ID
+	locale := updateLocale(update)
+
+	err := a.reviewSvc.Comment(ctx, tgID, update.Message.Text)
+	if err != nil {
+		var notFound *errorspkg.ErrRepoNotFound
+		if errors.As(err, &notFound) {
+			return
+		}
+		a.logger.Error(err.Error())
+		a.reply(ctx, b, tgID, usecases.TmplFeedbackFailed, locale)
+		return
+	}
+
+	a.reply(ctx, b, tgID, usecases.TmplFeedbackThanks, locale)
+}
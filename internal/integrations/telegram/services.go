@@ -0,0 +1,103 @@
+package telegram
+
+import (
+	"context"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/usecases"
+	"time"
+)
+
+// The handlers depend on the usecases only through these interfaces, so tests can drive
+// the bot with fakes.
+type (
+	VerificationService interface {
+		Approve(ctx context.Context, token string, tgID int64, locale string) error
+	}
+
+	ReservationService interface {
+		GetByTelegramID(ctx context.Context, userTgID int64) ([]entities.ReservationMessage, error)
+		GetDetailsByUUID(ctx context.Context, userTgID int64, uuid string) (entities.ReservationMessage, error)
+		Cancel(ctx context.Context, userTgID int64, uuid string) error
+	}
+
+	ReviewService interface {
+		Rate(ctx context.Context, tgID int64, reservationUUID string, rating int) error
+		Comment(ctx context.Context, tgID int64, comment string) error
+		SkipComment(ctx context.Context, tgID int64, reservationUUID string) error
+	}
+
+	AdminService interface {
+		IsAdmin(ctx context.Context, tgID int64) bool
+		Agenda(ctx context.Context, day time.Time) (entities.DayAgenda, error)
+		GetReservation(ctx context.Context, reservationUUID string) (entities.AdminReservation, error)
+		Find(ctx context.Context, query string) ([]entities.AdminReservation, error)
+		Cancel(ctx context.Context, reservationUUID string) error
+		CheckIn(ctx context.Context, reservationUUID string) error
+		NoShow(ctx context.Context, reservationUUID string) error
+		Block(ctx context.Context, house string, from, to time.Time, reason string) (entities.Blackout, error)
+	}
+
+	BookingService interface {
+		MaxGuests() int
+		Start(ctx context.Context, chatID, tgID int64) (entities.BookingSession, error)
+		Session(ctx context.Context, chatID int64) (entities.BookingSession, error)
+		Cancel(ctx context.Context, chatID int64) error
+		ShowMonth(ctx context.Context, s entities.BookingSession, month time.Time) (entities.BookingSession, error)
+		PickDate(ctx context.Context, s entities.BookingSession, day time.Time) (entities.BookingSession, error)
+		PickGuests(ctx context.Context, s entities.BookingSession, count int) (entities.BookingSession, error)
+		Houses(ctx context.Context, s entities.BookingSession) ([]usecases.GetAvailableHousesResponse, error)
+		House(ctx context.Context, s entities.BookingSession, houseID int) (usecases.GetAvailableHousesResponse, error)
+		PickHouse(
+			ctx context.Context,
+			s entities.BookingSession,
+			houseID int,
+		) (entities.BookingSession, usecases.GetAvailableHousesResponse, error)
+		Extras(ctx context.Context) ([]entities.Extra, error)
+		ToggleExtra(ctx context.Context, s entities.BookingSession, extraID int) (entities.BookingSession, error)
+		ToggleBathhouse(
+			ctx context.Context,
+			s entities.BookingSession,
+			typeID int,
+			date string,
+		) (session entities.BookingSession, added bool, err error)
+		PickFillOption(
+			ctx context.Context,
+			s entities.BookingSession,
+			typeID int,
+			date string,
+			optionID int,
+		) (entities.BookingSession, error)
+		Next(ctx context.Context, s entities.BookingSession) (entities.BookingSession, error)
+		Summary(ctx context.Context, s entities.BookingSession) (entities.BookingSummary, error)
+		Book(ctx context.Context, s entities.BookingSession, tgID int64) (entities.Reservation, error)
+	}
+
+	GuestService interface {
+		ExportByTgID(ctx context.Context, tgID int64) (usecases.GuestExport, error)
+		EraseByTgID(ctx context.Context, tgID int64) error
+	}
+
+	LoyaltyService interface {
+		StatusByTgID(ctx context.Context, tgID int64) (entities.LoyaltyStatus, error)
+	}
+
+	EventService interface {
+		SetApplicationStatus(
+			ctx context.Context,
+			id int,
+			status entities.ApplicationStatus,
+			manager string,
+		) (entities.EventApplication, error)
+	}
+)
+
+var (
+	_ VerificationService = (*usecases.Verification)(nil)
+	_ ReservationService  = (*usecases.Reservation)(nil)
+	_ ReviewService       = (*usecases.Reviews)(nil)
+	_ AdminService        = (*usecases.Admin)(nil)
+	_ BookingService      = (*usecases.Booking)(nil)
+	_ GuestService        = (*usecases.Guests)(nil)
+	_ LoyaltyService      = (*usecases.Loyalty)(nil)
+	_ EventService        = (*usecases.Events)(nil)
+)
@@ -2,24 +2,68 @@ package telegram
 
 import (
 	"context"
+	"crypto/subtle"
+	"embed"
+	"errors"
 	"github.com/calyrexx/QuietGrooveBackend/internal/configuration"
 	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
 	"github.com/calyrexx/QuietGrooveBackend/internal/usecases"
 	"github.com/calyrexx/zeroslog"
 	"github.com/go-telegram/bot"
 	"github.com/go-telegram/bot/models"
+	"io/fs"
 	"log/slog"
-	"regexp"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 )
 
-const tgBot string = "telegramBot"
+const (
+	tgBot string = "telegramBot"
+
+	webhookSecretHeader  = "X-Telegram-Bot-Api-Secret-Token"
+	webhookMaxBody       = 1 << 20
+	webhookDeleteTimeout = 10 * time.Second
+	getMeTimeout         = 5 * time.Second
+)
+
+//go:embed templates/*/*.tmpl
+var templatesFS embed.FS
+
+//go:embed assets/placeholder.jpg
+var placeholderImage []byte
+
+// DefaultTemplates returns the built-in texts laid out as <locale>/<key>.tmpl.
+func DefaultTemplates() (fs.FS, error) {
+	return fs.Sub(templatesFS, "templates")
+}
+
+// Renderer produces localized message texts by template key.
+type Renderer interface {
+	Render(ctx context.Context, key, locale string, data any) (string, error)
+	Locale(languageCode string) string
+	Locales() []string
+}
 
 type Adapter struct {
 	bot            *bot.Bot
+	messenger      Messenger
 	logger         *slog.Logger
 	adminChatIDs   []int64
-	verifSvc       *usecases.Verification
-	reservationSvc *usecases.Reservation
+	username       string
+	webhookURL     string
+	webhookSecret  string
+	verifSvc       VerificationService
+	reservationSvc ReservationService
+	reviewSvc      ReviewService
+	adminSvc       AdminService
+	bookingSvc     BookingService
+	guestSvc       GuestService
+	loyaltySvc     LoyaltyService
+	eventsSvc      EventService
+	texts          Renderer
 }
 
 func NewAdapter(creds *configuration.TelegramBot, logger *slog.Logger) (*Adapter, error) {
@@ -30,85 +74,258 @@ func NewAdapter(creds *configuration.TelegramBot, logger *slog.Logger) (*Adapter
 		return nil, errorspkg.NewErrConstructorDependencies("NewAdapter", "logger", "nil")
 	}
 
-	newLogger := logger.With(zeroslog.ServiceKey, tgBot)
+	if creds.WebhookURL != "" && creds.WebhookSecret == "" {
+		return nil, errorspkg.NewErrConstructorDependencies("NewAdapter", "WebhookSecret", "empty")
+	}
 
-	b, err := bot.New(creds.Token)
+	b, err := bot.New(creds.Token, bot.WithSkipGetMe())
 	if err != nil {
 		return nil, err
 	}
+	return newAdapter(creds, b, b, logger)
+}
+
+// NewAdapterWithMessenger builds an Adapter that never contacts Telegram: handlers
+// reply through messenger and updates are fed synchronously via ProcessUpdate.
+func NewAdapterWithMessenger(
+	creds *configuration.TelegramBot,
+	messenger Messenger,
+	logger *slog.Logger,
+) (*Adapter, error) {
+	if creds == nil {
+		return nil, errorspkg.NewErrConstructorDependencies("NewAdapterWithMessenger", "creds", "nil")
+	}
+	if messenger == nil {
+		return nil, errorspkg.NewErrConstructorDependencies("NewAdapterWithMessenger", "messenger", "nil")
+	}
+	if logger == nil {
+		return nil, errorspkg.NewErrConstructorDependencies("NewAdapterWithMessenger", "logger", "nil")
+	}
+
+	b, err := bot.New(creds.Token, bot.WithSkipGetMe(), bot.WithNotAsyncHandlers())
+	if err != nil {
+		return nil, err
+	}
+	return newAdapter(creds, b, messenger, logger)
+}
+
+// newAdapter asks Telegram for the bot's username, it is needed for deep links.
+func newAdapter(
+	creds *configuration.TelegramBot,
+	b *bot.Bot,
+	messenger Messenger,
+	logger *slog.Logger,
+) (*Adapter, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), getMeTimeout)
+	defer cancel()
+
+	me, err := messenger.GetMe(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Adapter{
-		bot:          b,
-		logger:       newLogger,
-		adminChatIDs: creds.AdminChatIDs,
+		bot:           b,
+		messenger:     messenger,
+		logger:        logger.With(zeroslog.ServiceKey, tgBot),
+		adminChatIDs:  creds.AdminChatIDs,
+		username:      me.Username,
+		webhookURL:    creds.WebhookURL,
+		webhookSecret: creds.WebhookSecret,
 	}, nil
 }
 
-func (a *Adapter) RegisterHandlers(ver *usecases.Verification, res *usecases.Reservation) {
+func (a *Adapter) RegisterHandlers(
+	ver VerificationService,
+	res ReservationService,
+	reviews ReviewService,
+	admin AdminService,
+	booking BookingService,
+	guests GuestService,
+	loyalty LoyaltyService,
+	events EventService,
+	texts Renderer,
+) {
 	a.verifSvc = ver
 	a.reservationSvc = res
+	a.reviewSvc = reviews
+	a.adminSvc = admin
+	a.bookingSvc = booking
+	a.guestSvc = guests
+	a.loyaltySvc = loyalty
+	a.eventsSvc = events
+	a.texts = texts
+
+	a.bot.RegisterHandlerMatchFunc(
+		func(u *models.Update) bool {
+			return u.Message != nil && a.isMenuButton(u.Message.Text, usecases.TmplMenuMyReservations)
+		},
+		a.handle(a.myReservationsHandler),
+	)
 
-	onlyDigits := regexp.MustCompile(`^\d+$`)
+	a.bot.RegisterHandlerMatchFunc(
+		func(u *models.Update) bool {
+			return u.Message != nil && a.isMenuButton(u.Message.Text, usecases.TmplMenuBook)
+		},
+		a.handle(a.bookHandler),
+	)
 
 	a.bot.RegisterHandlerMatchFunc(
 		func(u *models.Update) bool {
-			return u.Message != nil && onlyDigits.MatchString(u.Message.Text)
+			return u.Message != nil && a.isMenuButton(u.Message.Text, usecases.TmplMenuLoyalty)
 		},
-		a.verificationHandler,
+		a.handle(a.loyaltyHandler),
 	)
 
 	a.bot.RegisterHandler(
 		bot.HandlerTypeMessageText,
-		"🏡 Мои бронирования",
-		bot.MatchTypeExact,
-		a.myReservationsHandler,
+		"book",
+		bot.MatchTypeCommandStartOnly,
+		a.handle(a.bookHandler),
+	)
+
+	a.bot.RegisterHandler(
+		bot.HandlerTypeCallbackQueryData,
+		bookingPrefix,
+		bot.MatchTypePrefix,
+		a.handle(a.bookingCallback),
 	)
 
 	a.bot.RegisterHandler(
 		bot.HandlerTypeCallbackQueryData,
 		"view_resv_",
 		bot.MatchTypePrefix,
-		a.viewReservationCallback,
+		a.handle(a.viewReservationCallback),
 	)
 
 	a.bot.RegisterHandler(
 		bot.HandlerTypeCallbackQueryData,
 		"cancel_resv_",
 		bot.MatchTypePrefix,
-		a.cancelReservationCallback,
+		a.handle(a.cancelReservationCallback),
 	)
 
 	a.bot.RegisterHandler(
 		bot.HandlerTypeCallbackQueryData,
 		"my_reservations_back",
 		bot.MatchTypeExact,
-		a.myReservationsHandler,
+		a.handle(a.myReservationsHandler),
 	)
 
 	a.bot.RegisterHandler(
 		bot.HandlerTypeMessageText,
 		"/start",
 		bot.MatchTypeExact,
-		a.startHandler,
+		a.handle(a.startHandler),
+	)
+
+	a.bot.RegisterHandler(
+		bot.HandlerTypeMessageText,
+		verificationStart,
+		bot.MatchTypePrefix,
+		a.handle(a.verificationHandler),
 	)
 
+	a.bot.RegisterHandler(
+		bot.HandlerTypeCallbackQueryData,
+		"review_rate_",
+		bot.MatchTypePrefix,
+		a.handle(a.rateCallback),
+	)
+
+	a.bot.RegisterHandler(
+		bot.HandlerTypeCallbackQueryData,
+		"review_skip_",
+		bot.MatchTypePrefix,
+		a.handle(a.skipCommentCallback),
+	)
+
+	a.bot.RegisterHandler(
+		bot.HandlerTypeMessageText,
+		"mydata",
+		bot.MatchTypeCommandStartOnly,
+		a.handle(a.myDataHandler),
+	)
+
+	a.bot.RegisterHandler(
+		bot.HandlerTypeMessageText,
+		"forgetme",
+		bot.MatchTypeCommandStartOnly,
+		a.handle(a.forgetMeHandler),
+	)
+
+	a.bot.RegisterHandler(
+		bot.HandlerTypeCallbackQueryData,
+		privacyErase,
+		bot.MatchTypeExact,
+		a.handle(a.eraseCallback),
+	)
+
+	for command, handler := range map[string]handlerFunc{
+		"today":       a.todayHandler,
+		"tomorrow":    a.tomorrowHandler,
+		"reservation": a.reservationHandler,
+		"block":       a.blockHandler,
+	} {
+		a.bot.RegisterHandler(
+			bot.HandlerTypeMessageText,
+			command,
+			bot.MatchTypeCommandStartOnly,
+			a.handle(a.adminOnly(handler)),
+		)
+	}
+
+	for prefix, handler := range map[string]handlerFunc{
+		"adm_view_":       a.adminViewCallback,
+		"adm_cancel_":     a.adminCancelCallback,
+		"adm_checkin_":    a.adminCheckInCallback,
+		"adm_noshow_":     a.adminNoShowCallback,
+		applicationPrefix: a.applicationStatusCallback,
+	} {
+		a.bot.RegisterHandler(
+			bot.HandlerTypeCallbackQueryData,
+			prefix,
+			bot.MatchTypePrefix,
+			a.handle(a.adminOnly(handler)),
+		)
+	}
+
+	// Registered last: any other text is treated as a review comment.
+	a.bot.RegisterHandlerMatchFunc(
+		func(u *models.Update) bool {
+			return u.Message != nil && u.Message.Text != "" && !strings.HasPrefix(u.Message.Text, "/")
+		},
+		a.handle(a.reviewCommentHandler),
+	)
 }
 
-func (a *Adapter) startHandler(ctx context.Context, b *bot.Bot, update *models.Update) {
+func (a *Adapter) startHandler(ctx context.Context, b Messenger, update *models.Update) {
 	tgID := update.Message.Chat.ID
+	locale := updateLocale(update)
+
+	text, err := a.texts.Render(ctx, usecases.TmplStart, locale, nil)
+	if err != nil {
+		a.logger.Error(err.Error())
+		return
+	}
 
 	replyMarkup := &models.ReplyKeyboardMarkup{
 		Keyboard: [][]models.KeyboardButton{
 			{
-				{Text: "🏡 Мои бронирования"},
+				{Text: a.buttonText(ctx, usecases.TmplMenuBook, locale)},
+				{Text: a.buttonText(ctx, usecases.TmplMenuMyReservations, locale)},
+			},
+			{
+				{Text: a.buttonText(ctx, usecases.TmplMenuLoyalty, locale)},
 			},
 		},
 		ResizeKeyboard:  true,
 		OneTimeKeyboard: false,
 	}
 
-	_, err := b.SendMessage(ctx, &bot.SendMessageParams{
+	_, err = b.SendMessage(ctx, &bot.SendMessageParams{
 		ChatID:      tgID,
-		Text:        "Добро пожаловать! Выберите действие:",
+		Text:        text,
 		ReplyMarkup: replyMarkup,
 	})
 	if err != nil {
@@ -116,6 +333,172 @@ func (a *Adapter) startHandler(ctx context.Context, b *bot.Bot, update *models.U
 	}
 }
 
+// Run receives updates until ctx is done: by long polling, or in webhook mode by
+// registering the webhook and processing what WebhookHandler accepts.
 func (a *Adapter) Run(ctx context.Context) {
-	a.bot.Start(ctx)
+	if a.webhookURL == "" {
+		a.bot.Start(ctx)
+		return
+	}
+
+	_, err := a.bot.SetWebhook(ctx, &bot.SetWebhookParams{
+		URL:         a.webhookURL,
+		SecretToken: a.webhookSecret,
+	})
+	if err != nil {
+		a.logger.Error("set webhook", zeroslog.ErrorKey, err)
+	}
+
+	a.bot.StartWebhook(ctx)
+
+	deleteCtx, cancel := context.WithTimeout(context.Background(), webhookDeleteTimeout)
+	defer cancel()
+	if _, err = a.bot.DeleteWebhook(deleteCtx, &bot.DeleteWebhookParams{}); err != nil {
+		a.logger.Error("delete webhook", zeroslog.ErrorKey, err)
+	}
+}
+
+// WebhookHandler accepts updates pushed by Telegram, nil in polling mode. Requests
+// without the secret token set in SetWebhook are rejected.
+func (a *Adapter) WebhookHandler() http.Handler {
+	if a.webhookURL == "" {
+		return nil
+	}
+
+	next := a.bot.WebhookHandler()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.Header.Get(webhookSecretHeader)
+		if subtle.ConstantTimeCompare([]byte(token), []byte(a.webhookSecret)) != 1 {
+			a.logger.Warn("webhook request with invalid secret token", "remoteAddr", r.RemoteAddr)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, webhookMaxBody)
+		next(w, r)
+	})
+}
+
+// notify sends a notification; Telegram's 429 becomes errorspkg.ErrRetryAfter with the
+// delay Telegram asked for, so the outbox worker doesn't hammer the API.
+func (a *Adapter) notify(ctx context.Context, params *bot.SendMessageParams) error {
+	_, err := a.messenger.SendMessage(ctx, params)
+
+	var tooMany *bot.TooManyRequestsError
+	if errors.As(err, &tooMany) {
+		return errorspkg.NewErrRetryAfter(time.Duration(tooMany.RetryAfter)*time.Second, err)
+	}
+	return err
+}
+
+// AdminRecipients lists the admin chats the dispatcher delivers to one by one.
+func (a *Adapter) AdminRecipients() []string {
+	res := make([]string, 0, len(a.adminChatIDs))
+	for _, chatID := range a.adminChatIDs {
+		res = append(res, strconv.FormatInt(chatID, 10))
+	}
+	return res
+}
+
+// ForAdmin returns the adapter limited to one admin chat.
+func (a *Adapter) ForAdmin(recipient string) (usecases.ChannelNotifier, bool) {
+	chatID, err := strconv.ParseInt(recipient, 10, 64)
+	if err != nil || !slices.Contains(a.adminChatIDs, chatID) {
+		return nil, false
+	}
+	scoped := *a
+	scoped.adminChatIDs = []int64{chatID}
+	return &scoped, true
+}
+
+func (a *Adapter) notifyAdmins(ctx context.Context, key string, data any) error {
+	return a.notifyAdminsWithMarkup(ctx, key, data, nil)
+}
+
+func (a *Adapter) notifyAdminsWithMarkup(ctx context.Context, key string, data any, markup models.ReplyMarkup) error {
+	text, err := a.texts.Render(ctx, key, "", data)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, chatID := range a.adminChatIDs {
+		err = a.notify(ctx,
+			&bot.SendMessageParams{
+				ChatID:      chatID,
+				Text:        text,
+				ParseMode:   "Markdown",
+				ReplyMarkup: markup,
+			},
+		)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (a *Adapter) reply(ctx context.Context, b Messenger, chatID int64, key, locale string) {
+	text, err := a.texts.Render(ctx, key, locale, nil)
+	if err != nil {
+		a.logger.Error(err.Error())
+		return
+	}
+
+	_, err = b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID: chatID,
+		Text:   text,
+	})
+	if err != nil {
+		a.logger.Error(err.Error())
+	}
+}
+
+func (a *Adapter) alert(ctx context.Context, b Messenger, queryID, key, locale string) {
+	text, err := a.texts.Render(ctx, key, locale, nil)
+	if err != nil {
+		a.logger.Error(err.Error())
+		return
+	}
+
+	_, err = b.AnswerCallbackQuery(ctx, &bot.AnswerCallbackQueryParams{
+		CallbackQueryID: queryID,
+		Text:            text,
+		ShowAlert:       true,
+	})
+	if err != nil {
+		a.logger.Error(err.Error())
+	}
+}
+
+func (a *Adapter) buttonText(ctx context.Context, key, locale string) string {
+	text, err := a.texts.Render(ctx, key, locale, nil)
+	if err != nil {
+		a.logger.Error(err.Error())
+		return key
+	}
+	return text
+}
+
+// isMenuButton matches the reply keyboard button in any locale, the guest may have
+// received the keyboard before changing Telegram's language.
+func (a *Adapter) isMenuButton(text, key string) bool {
+	ctx := context.Background()
+	for _, locale := range a.texts.Locales() {
+		if button, err := a.texts.Render(ctx, key, locale, nil); err == nil && button == text {
+			return true
+		}
+	}
+	return false
+}
+
+func updateLocale(u *models.Update) string {
+	switch {
+	case u.CallbackQuery != nil:
+		return u.CallbackQuery.From.LanguageCode
+	case u.Message != nil && u.Message.From != nil:
+		return u.Message.From.LanguageCode
+	default:
+		return ""
+	}
 }
@@ -0,0 +1,324 @@
+package telegram_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/calyrexx/QuietGrooveBackend/internal/configuration"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/integrations/telegram"
+	"github.com/calyrexx/QuietGrooveBackend/internal/integrations/telegram/telegramtest"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/calyrexx/QuietGrooveBackend/internal/repository"
+	"github.com/calyrexx/QuietGrooveBackend/internal/usecases"
+	"github.com/go-telegram/bot/models"
+)
+
+const guestID, adminID int64 = 42, 7
+
+type noOverrides struct {
+	repository.ITemplates
+}
+
+func (noOverrides) GetAll(context.Context) ([]entities.MessageTemplate, error) {
+	return nil, nil
+}
+
+type fakeVerification struct {
+	approved []string
+}
+
+func (v *fakeVerification) Approve(_ context.Context, token string, _ int64, locale string) error {
+	switch token {
+	case "good":
+	case "taken":
+		return errorspkg.ErrGuestConflict
+	default:
+		return errorspkg.ErrInvalidVerificationCode
+	}
+	v.approved = append(v.approved, token+"/"+locale)
+	return nil
+}
+
+type fakeReservations struct {
+	list      []entities.ReservationMessage
+	cancelled []string
+}
+
+func (r *fakeReservations) GetByTelegramID(context.Context, int64) ([]entities.ReservationMessage, error) {
+	return r.list, nil
+}
+
+func (r *fakeReservations) GetDetailsByUUID(_ context.Context, _ int64, uuid string) (entities.ReservationMessage, error) {
+	for _, res := range r.list {
+		if res.UUID == uuid {
+			return res, nil
+		}
+	}
+	return entities.ReservationMessage{}, errorspkg.NewErrRepoNotFound("reservation", uuid, "fake")
+}
+
+func (r *fakeReservations) Cancel(_ context.Context, _ int64, uuid string) error {
+	r.cancelled = append(r.cancelled, uuid)
+	return nil
+}
+
+type fakeAdmin struct {
+	telegram.AdminService
+	agendaDays []time.Time
+}
+
+func (a *fakeAdmin) IsAdmin(_ context.Context, tgID int64) bool {
+	return tgID == adminID
+}
+
+func (a *fakeAdmin) Agenda(_ context.Context, day time.Time) (entities.DayAgenda, error) {
+	a.agendaDays = append(a.agendaDays, day)
+	return entities.DayAgenda{Date: day}, nil
+}
+
+type testBot struct {
+	adapter      *telegram.Adapter
+	messenger    *telegramtest.Messenger
+	texts        *usecases.Templates
+	verification *fakeVerification
+	reservations *fakeReservations
+	admin        *fakeAdmin
+}
+
+func newTestBot(t *testing.T) *testBot {
+	t.Helper()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	defaults, err := telegram.DefaultTemplates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	texts, err := usecases.NewTemplates(&usecases.TemplatesDependencies{
+		Repo:          noOverrides{},
+		Defaults:      defaults,
+		DefaultLocale: "ru",
+		Logger:        logger,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	messenger := telegramtest.NewMessenger()
+	adapter, err := telegram.NewAdapterWithMessenger(&configuration.TelegramBot{Token: "1:test"}, messenger, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tb := &testBot{
+		adapter:      adapter,
+		messenger:    messenger,
+		texts:        texts,
+		verification: &fakeVerification{},
+		reservations: &fakeReservations{
+			list: []entities.ReservationMessage{
+				{UUID: "r1", HouseName: "Лесной", Status: "confirmed", CheckIn: time.Date(2099, 7, 1, 0, 0, 0, 0, time.UTC), CheckOut: time.Date(2099, 7, 3, 0, 0, 0, 0, time.UTC)},
+				{UUID: "r2", HouseName: "Шале", Status: "completed", CheckIn: time.Date(2099, 8, 1, 0, 0, 0, 0, time.UTC), CheckOut: time.Date(2099, 8, 2, 0, 0, 0, 0, time.UTC)},
+			},
+		},
+		admin: &fakeAdmin{},
+	}
+	adapter.RegisterHandlers(
+		tb.verification,
+		tb.reservations,
+		struct{ telegram.ReviewService }{},
+		tb.admin,
+		struct{ telegram.BookingService }{},
+		struct{ telegram.GuestService }{},
+		struct{ telegram.LoyaltyService }{},
+		struct{ telegram.EventService }{},
+		texts,
+	)
+
+	return tb
+}
+
+func (tb *testBot) render(t *testing.T, key, locale string, data any) string {
+	t.Helper()
+	text, err := tb.texts.Render(context.Background(), key, locale, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return text
+}
+
+// send delivers a text message, marking a leading command with an entity as Telegram does.
+func (tb *testBot) send(from int64, locale, text string) {
+	msg := &models.Message{
+		ID:   100,
+		Text: text,
+		Chat: models.Chat{ID: from},
+		From: &models.User{ID: from, LanguageCode: locale},
+	}
+	if strings.HasPrefix(text, "/") {
+		command, _, _ := strings.Cut(text, " ")
+		msg.Entities = []models.MessageEntity{{Type: models.MessageEntityTypeBotCommand, Length: len(command)}}
+	}
+	tb.adapter.ProcessUpdate(context.Background(), &models.Update{Message: msg})
+}
+
+func (tb *testBot) press(from int64, messageID int, data string) {
+	tb.adapter.ProcessUpdate(context.Background(), &models.Update{
+		CallbackQuery: &models.CallbackQuery{
+			ID:   "query",
+			Data: data,
+			From: models.User{ID: from},
+			Message: models.MaybeInaccessibleMessage{
+				Type:    models.MaybeInaccessibleMessageTypeMessage,
+				Message: &models.Message{ID: messageID, Chat: models.Chat{ID: from}},
+			},
+		},
+	})
+}
+
+func TestVerificationDeepLink(t *testing.T) {
+	tb := newTestBot(t)
+
+	tb.send(guestID, "en-US", "/start bad")
+	if msg := tb.messenger.LastMessage(); msg == nil || msg.Text != tb.render(t, usecases.TmplVerificationInvalid, "en", nil) {
+		t.Fatalf("reply to a bad token = %+v", msg)
+	}
+
+	tb.messenger.Reset()
+	tb.send(guestID, "en-US", "/start taken")
+	if msg := tb.messenger.LastMessage(); msg == nil || msg.Text != tb.render(t, usecases.TmplVerificationConflict, "en", nil) {
+		t.Fatalf("reply to a token of another guest = %+v", msg)
+	}
+
+	tb.messenger.Reset()
+	tb.send(guestID, "en-US", "/start good")
+	if !slices.Equal(tb.verification.approved, []string{"good/en"}) {
+		t.Errorf("approved = %v, want the token with the guest's locale", tb.verification.approved)
+	}
+
+	messages := tb.messenger.Messages()
+	if len(messages) != 2 {
+		t.Fatalf("sent %d messages, want the confirmation and the menu", len(messages))
+	}
+	if messages[0].Text != tb.render(t, usecases.TmplVerificationSuccess, "en", nil) {
+		t.Errorf("confirmation = %q", messages[0].Text)
+	}
+	menu, ok := messages[1].ReplyMarkup.(*models.ReplyKeyboardMarkup)
+	if !ok {
+		t.Fatalf("menu markup = %T, want a reply keyboard", messages[1].ReplyMarkup)
+	}
+	var buttons []string
+	for _, row := range menu.Keyboard {
+		for _, button := range row {
+			buttons = append(buttons, button.Text)
+		}
+	}
+	want := []string{
+		tb.render(t, usecases.TmplMenuBook, "en", nil),
+		tb.render(t, usecases.TmplMenuMyReservations, "en", nil),
+		tb.render(t, usecases.TmplMenuLoyalty, "en", nil),
+	}
+	if !slices.Equal(buttons, want) {
+		t.Errorf("menu buttons = %v, want %v", buttons, want)
+	}
+}
+
+func TestViewAndCancelReservation(t *testing.T) {
+	tb := newTestBot(t)
+
+	// The menu button lists the reservations, the guest's message is removed.
+	tb.send(guestID, "ru", tb.render(t, usecases.TmplMenuMyReservations, "ru", nil))
+	list := tb.messenger.LastMessage()
+	if list == nil || list.Text != tb.render(t, usecases.TmplReservationsListTitle, "ru", nil) {
+		t.Fatalf("list message = %+v", list)
+	}
+	if got := telegramtest.InlineButtons(list.ReplyMarkup); !slices.Equal(got, []string{"view_resv_r1", "view_resv_r2"}) {
+		t.Errorf("list buttons = %v", got)
+	}
+	if deleted := tb.messenger.Deleted(); len(deleted) != 1 || deleted[0].MessageID != 100 {
+		t.Errorf("deleted = %+v, want the guest's message", deleted)
+	}
+
+	// A confirmed reservation opens with a cancel button.
+	tb.messenger.Reset()
+	tb.press(guestID, 5, "view_resv_r1")
+	photos := tb.messenger.Photos()
+	if len(photos) != 1 {
+		t.Fatalf("sent %d photos, want 1", len(photos))
+	}
+	if photos[0].Caption != tb.render(t, usecases.TmplReservationDetails, "ru", tb.reservations.list[0]) {
+		t.Errorf("details caption = %q", photos[0].Caption)
+	}
+	if got := telegramtest.InlineButtons(photos[0].ReplyMarkup); !slices.Equal(got, []string{"cancel_resv_r1", "my_reservations_back"}) {
+		t.Errorf("details buttons = %v", got)
+	}
+
+	// A completed one can't be cancelled.
+	tb.messenger.Reset()
+	tb.press(guestID, 6, "view_resv_r2")
+	if photos = tb.messenger.Photos(); len(photos) != 1 || !slices.Equal(telegramtest.InlineButtons(photos[0].ReplyMarkup), []string{"my_reservations_back"}) {
+		t.Errorf("completed reservation markup = %+v", photos)
+	}
+
+	// Cancelling shows an alert and replaces the caption and buttons in place.
+	tb.messenger.Reset()
+	tb.press(guestID, 8, "cancel_resv_r1")
+	if !slices.Equal(tb.reservations.cancelled, []string{"r1"}) {
+		t.Errorf("cancelled = %v", tb.reservations.cancelled)
+	}
+	answers := tb.messenger.Answers()
+	if len(answers) != 1 || answers[0].Text != tb.render(t, usecases.TmplReservationCancelledAlert, "ru", nil) {
+		t.Errorf("answers = %+v", answers)
+	}
+	edited := tb.messenger.EditedCaptions()
+	if len(edited) != 1 || edited[0].MessageID != 8 {
+		t.Fatalf("edited captions = %+v, want the details message", edited)
+	}
+	cancelled := tb.reservations.list[0]
+	cancelled.Status = "cancelled"
+	if edited[0].Caption != tb.render(t, usecases.TmplReservationDetails, "ru", cancelled) {
+		t.Errorf("edited caption = %q", edited[0].Caption)
+	}
+	if got := telegramtest.InlineButtons(edited[0].ReplyMarkup); !slices.Equal(got, []string{"my_reservations_back"}) {
+		t.Errorf("buttons after cancel = %v", got)
+	}
+
+	// An unknown reservation only gets an alert.
+	tb.messenger.Reset()
+	tb.press(guestID, 9, "view_resv_gone")
+	if answers = tb.messenger.Answers(); len(answers) != 1 || answers[0].Text != tb.render(t, usecases.TmplReservationNotFound, "ru", nil) {
+		t.Errorf("answers for an unknown reservation = %+v", answers)
+	}
+	if len(tb.messenger.Photos()) != 0 {
+		t.Error("details sent for an unknown reservation")
+	}
+}
+
+func TestAdminCommandsIgnoreGuests(t *testing.T) {
+	tb := newTestBot(t)
+
+	tb.send(guestID, "ru", "/today")
+	if len(tb.messenger.Messages()) != 0 || len(tb.admin.agendaDays) != 0 {
+		t.Fatalf("a guest got the agenda: %+v", tb.messenger.Messages())
+	}
+
+	tb.send(adminID, "ru", "/today")
+	if len(tb.admin.agendaDays) != 1 {
+		t.Fatalf("agenda requested %d times, want 1", len(tb.admin.agendaDays))
+	}
+	msg := tb.messenger.LastMessage()
+	if msg == nil || msg.ChatID != adminID {
+		t.Fatalf("agenda message = %+v", msg)
+	}
+	if msg.Text != tb.render(t, usecases.TmplAdminAgenda, "ru", entities.DayAgenda{Date: tb.admin.agendaDays[0]}) {
+		t.Errorf("agenda text = %q", msg.Text)
+	}
+	if msg.ReplyMarkup != nil {
+		t.Errorf("empty agenda has a keyboard: %+v", msg.ReplyMarkup)
+	}
+}
@@ -0,0 +1,242 @@
+// Package telegramtest provides an in-memory telegram.Messenger that records every call.
+package telegramtest
+
+import (
+	"context"
+	"github.com/calyrexx/QuietGrooveBackend/internal/integrations/telegram"
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"sync"
+)
+
+var _ telegram.Messenger = (*Messenger)(nil)
+
+// Messenger records outgoing Bot API calls instead of sending them. When Err is set
+// every call fails with it. GetMe reports Username, "test_bot" when empty.
+type Messenger struct {
+	Err      error
+	Username string
+
+	mu             sync.Mutex
+	lastMessageID  int
+	messages       []*bot.SendMessageParams
+	photos         []*bot.SendPhotoParams
+	documents      []*bot.SendDocumentParams
+	editedTexts    []*bot.EditMessageTextParams
+	editedCaptions []*bot.EditMessageCaptionParams
+	editedMarkups  []*bot.EditMessageReplyMarkupParams
+	answers        []*bot.AnswerCallbackQueryParams
+	deleted        []*bot.DeleteMessageParams
+}
+
+func NewMessenger() *Messenger {
+	return &Messenger{}
+}
+
+func (m *Messenger) GetMe(_ context.Context) (*models.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	username := m.Username
+	if username == "" {
+		username = "test_bot"
+	}
+	return &models.User{ID: 1, IsBot: true, Username: username}, nil
+}
+
+func (m *Messenger) SendMessage(_ context.Context, params *bot.SendMessageParams) (*models.Message, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	m.messages = append(m.messages, params)
+	return m.message(params.ChatID), nil
+}
+
+func (m *Messenger) SendPhoto(_ context.Context, params *bot.SendPhotoParams) (*models.Message, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	m.photos = append(m.photos, params)
+	return m.message(params.ChatID), nil
+}
+
+func (m *Messenger) SendDocument(_ context.Context, params *bot.SendDocumentParams) (*models.Message, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	m.documents = append(m.documents, params)
+	return m.message(params.ChatID), nil
+}
+
+func (m *Messenger) EditMessageText(_ context.Context, params *bot.EditMessageTextParams) (*models.Message, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	m.editedTexts = append(m.editedTexts, params)
+	return &models.Message{ID: params.MessageID, Text: params.Text}, nil
+}
+
+func (m *Messenger) EditMessageCaption(
+	_ context.Context,
+	params *bot.EditMessageCaptionParams,
+) (*models.Message, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	m.editedCaptions = append(m.editedCaptions, params)
+	return &models.Message{ID: params.MessageID, Caption: params.Caption}, nil
+}
+
+func (m *Messenger) EditMessageReplyMarkup(
+	_ context.Context,
+	params *bot.EditMessageReplyMarkupParams,
+) (*models.Message, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	m.editedMarkups = append(m.editedMarkups, params)
+	return &models.Message{ID: params.MessageID}, nil
+}
+
+func (m *Messenger) AnswerCallbackQuery(_ context.Context, params *bot.AnswerCallbackQueryParams) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return false, m.Err
+	}
+	m.answers = append(m.answers, params)
+	return true, nil
+}
+
+func (m *Messenger) DeleteMessage(_ context.Context, params *bot.DeleteMessageParams) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Err != nil {
+		return false, m.Err
+	}
+	m.deleted = append(m.deleted, params)
+	return true, nil
+}
+
+func (m *Messenger) Messages() []*bot.SendMessageParams {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*bot.SendMessageParams(nil), m.messages...)
+}
+
+// LastMessage returns the most recent SendMessage call, nil if there was none.
+func (m *Messenger) LastMessage() *bot.SendMessageParams {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.messages) == 0 {
+		return nil
+	}
+	return m.messages[len(m.messages)-1]
+}
+
+func (m *Messenger) Photos() []*bot.SendPhotoParams {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*bot.SendPhotoParams(nil), m.photos...)
+}
+
+func (m *Messenger) Documents() []*bot.SendDocumentParams {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*bot.SendDocumentParams(nil), m.documents...)
+}
+
+func (m *Messenger) EditedTexts() []*bot.EditMessageTextParams {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*bot.EditMessageTextParams(nil), m.editedTexts...)
+}
+
+func (m *Messenger) EditedCaptions() []*bot.EditMessageCaptionParams {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*bot.EditMessageCaptionParams(nil), m.editedCaptions...)
+}
+
+func (m *Messenger) EditedMarkups() []*bot.EditMessageReplyMarkupParams {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*bot.EditMessageReplyMarkupParams(nil), m.editedMarkups...)
+}
+
+func (m *Messenger) Answers() []*bot.AnswerCallbackQueryParams {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*bot.AnswerCallbackQueryParams(nil), m.answers...)
+}
+
+func (m *Messenger) Deleted() []*bot.DeleteMessageParams {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*bot.DeleteMessageParams(nil), m.deleted...)
+}
+
+// Reset forgets the recorded calls, message IDs keep growing.
+func (m *Messenger) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages = nil
+	m.photos = nil
+	m.documents = nil
+	m.editedTexts = nil
+	m.editedCaptions = nil
+	m.editedMarkups = nil
+	m.answers = nil
+	m.deleted = nil
+}
+
+// InlineButtons flattens an inline keyboard into its callback data, nil for any other markup.
+func InlineButtons(markup models.ReplyMarkup) []string {
+	keyboard, ok := markup.(*models.InlineKeyboardMarkup)
+	if !ok || keyboard == nil {
+		return nil
+	}
+
+	var data []string
+	for _, row := range keyboard.InlineKeyboard {
+		for _, button := range row {
+			data = append(data, button.CallbackData)
+		}
+	}
+	return data
+}
+
+func (m *Messenger) message(chatID any) *models.Message {
+	m.lastMessageID++
+
+	msg := &models.Message{ID: m.lastMessageID}
+	if id, ok := chatID.(int64); ok {
+		msg.Chat.ID = id
+	}
+	return msg
+}
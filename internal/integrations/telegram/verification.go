@@ -2,47 +2,69 @@ package telegram
 
 import (
 	"context"
+	"errors"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/calyrexx/QuietGrooveBackend/internal/usecases"
 	"github.com/go-telegram/bot"
 	"github.com/go-telegram/bot/models"
+	"net/url"
+	"strings"
 )
 
-func (a *Adapter) verificationHandler(ctx context.Context, b *bot.Bot, u *models.Update) {
-	code := u.Message.Text
+// verificationStart prefixes the message Telegram sends when a deep link is opened.
+const verificationStart = "/start "
+
+// StartLink is the deep link that opens the bot with payload passed to /start.
+func (a *Adapter) StartLink(payload string) string {
+	return "https://t.me/" + a.username + "?start=" + url.QueryEscape(payload)
+}
+
+// verificationHandler handles "/start <token>" sent by a verification deep link.
+func (a *Adapter) verificationHandler(ctx context.Context, b Messenger, u *models.Update) {
+	token := strings.TrimSpace(strings.TrimPrefix(u.Message.Text, verificationStart))
 	tgID := u.Message.Chat.ID
+	locale := updateLocale(u)
 
-	if len(code) != 6 {
-		_, err := b.SendMessage(ctx,
-			&bot.SendMessageParams{
-				ChatID: tgID,
-				Text:   "⚠️ Код должен содержать 6 цифр",
-			},
-		)
-		if err != nil {
+	err := a.verifSvc.Approve(ctx, token, tgID, a.texts.Locale(locale))
+	if err != nil {
+		var locked *errorspkg.ErrVerificationLocked
+		switch {
+		case errors.As(err, &locked):
+			a.replyLocked(ctx, b, tgID, locked, locale)
+		case errors.Is(err, errorspkg.ErrInvalidVerificationCode):
+			a.reply(ctx, b, tgID, usecases.TmplVerificationInvalid, locale)
+		case errors.Is(err, errorspkg.ErrGuestConflict):
+			a.reply(ctx, b, tgID, usecases.TmplVerificationConflict, locale)
+		default:
 			a.logger.Error(err.Error())
+			a.reply(ctx, b, tgID, usecases.TmplVerificationInvalid, locale)
 		}
 		return
 	}
 
-	if err := a.verifSvc.Approve(ctx, code, tgID); err != nil {
-		_, err = b.SendMessage(ctx,
-			&bot.SendMessageParams{
-				ChatID: tgID,
-				Text:   "❌ Код неверный или устарел",
-			},
-		)
-		if err != nil {
-			a.logger.Error(err.Error())
-		}
+	a.reply(ctx, b, tgID, usecases.TmplVerificationSuccess, locale)
+	a.startHandler(ctx, b, u)
+}
+
+func (a *Adapter) replyLocked(
+	ctx context.Context,
+	b Messenger,
+	chatID int64,
+	locked *errorspkg.ErrVerificationLocked,
+	locale string,
+) {
+	text, err := a.texts.Render(ctx, usecases.TmplVerificationLocked, locale, locked)
+	if err != nil {
+		a.logger.Error(err.Error())
 		return
 	}
 
-	_, err := b.SendMessage(ctx,
-		&bot.SendMessageParams{
-			ChatID: tgID,
-			Text:   "✅ Личность подтверждена!",
-		},
-	)
-	if err != nil {
+	if _, err = b.SendMessage(ctx, &bot.SendMessageParams{ChatID: chatID, Text: text}); err != nil {
 		a.logger.Error(err.Error())
 	}
 }
+
+func (a *Adapter) SuspiciousVerification(msg entities.SuspiciousVerification) error {
+	return a.notifyAdmins(context.Background(), usecases.TmplVerificationSuspicious, msg)
+}
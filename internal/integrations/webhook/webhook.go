@@ -0,0 +1,233 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/calyrexx/QuietGrooveBackend/internal/configuration"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/calyrexx/QuietGrooveBackend/internal/usecases"
+	"github.com/calyrexx/zeroslog"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+)
+
+const (
+	webhookService  = "webhook"
+	signatureHeader = "X-QuietGrove-Signature"
+
+	eventReservationCreated   = "reservation.created"
+	eventReservationCancelled = "reservation.cancelled"
+	eventReservationReminder  = "reservation.reminder"
+	eventApplication          = "event.application"
+
+	audienceAdmin = "admin"
+	audienceGuest = "guest"
+)
+
+// HTTPClient is satisfied by *http.Client.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Adapter posts notifications as JSON to external endpoints (CRM, chat bots, automation).
+// Every request is signed with HMAC-SHA256 of the body so receivers can verify the origin.
+type Adapter struct {
+	http   HTTPClient
+	urls   []string
+	secret []byte
+	logger *slog.Logger
+}
+
+type payload struct {
+	Event      string    `json:"event"`
+	Audience   string    `json:"audience"`
+	OccurredAt time.Time `json:"occurredAt"`
+	Data       any       `json:"data"`
+}
+
+type reservationData struct {
+	UUID        string `json:"uuid"`
+	HouseName   string `json:"houseName"`
+	GuestUUID   string `json:"guestUuid"`
+	GuestName   string `json:"guestName"`
+	GuestPhone  string `json:"guestPhone"`
+	GuestEmail  string `json:"guestEmail"`
+	CheckIn     string `json:"checkIn"`
+	CheckOut    string `json:"checkOut"`
+	GuestsCount int    `json:"guestsCount,omitempty"`
+	TotalPrice  int    `json:"totalPrice,omitempty"`
+	Stage       string `json:"stage,omitempty"`
+}
+
+type applicationData struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Phone       string `json:"phone"`
+	CheckIn     string `json:"checkIn"`
+	GuestsCount int    `json:"guestsCount"`
+}
+
+func NewAdapter(creds *configuration.Webhook, httpClient HTTPClient, logger *slog.Logger) (*Adapter, error) {
+	if creds == nil {
+		return nil, errorspkg.NewErrConstructorDependencies("webhook.NewAdapter", "creds", "nil")
+	}
+	if httpClient == nil {
+		return nil, errorspkg.NewErrConstructorDependencies("webhook.NewAdapter", "HTTPClient", "nil")
+	}
+	if logger == nil {
+		return nil, errorspkg.NewErrConstructorDependencies("webhook.NewAdapter", "logger", "nil")
+	}
+
+	return &Adapter{
+		http:   httpClient,
+		urls:   creds.URLs,
+		secret: []byte(creds.Secret),
+		logger: logger.With(zeroslog.ServiceKey, webhookService),
+	}, nil
+}
+
+// AdminRecipients lists the endpoints the dispatcher delivers admin events to one by one.
+func (a *Adapter) AdminRecipients() []string {
+	return a.urls
+}
+
+// ForAdmin returns the adapter limited to one endpoint.
+func (a *Adapter) ForAdmin(recipient string) (usecases.ChannelNotifier, bool) {
+	if !slices.Contains(a.urls, recipient) {
+		return nil, false
+	}
+	scoped := *a
+	scoped.urls = []string{recipient}
+	return &scoped, true
+}
+
+func (a *Adapter) ReservationCreatedForAdmin(msg entities.ReservationCreatedMessage) error {
+	return a.post(eventReservationCreated, audienceAdmin, createdData(msg))
+}
+
+func (a *Adapter) ReservationCreatedForUser(msg entities.ReservationCreatedMessage, _ int64) error {
+	return a.post(eventReservationCreated, audienceGuest, createdData(msg))
+}
+
+func (a *Adapter) ReservationCancelledForAdmin(msg entities.ReservationCancelledMessage) error {
+	return a.post(eventReservationCancelled, audienceAdmin, cancelledData(msg))
+}
+
+func (a *Adapter) ReservationCancelledForUser(msg entities.ReservationCancelledMessage) error {
+	return a.post(eventReservationCancelled, audienceGuest, cancelledData(msg))
+}
+
+func (a *Adapter) RemindUser(msg []entities.ReservationReminderNotification) error {
+	var errs []error
+	for _, m := range msg {
+		data := reservationData{
+			UUID:       m.UUID.String(),
+			HouseName:  m.HouseName,
+			GuestUUID:  m.GuestUUID.String(),
+			GuestName:  m.GuestName,
+			GuestPhone: m.GuestPhone,
+			GuestEmail: m.GuestEmail,
+			CheckIn:    m.CheckIn.Format(time.DateOnly),
+			CheckOut:   m.CheckOut.Format(time.DateOnly),
+			Stage:      m.Stage,
+		}
+		if err := a.post(eventReservationReminder, audienceGuest, data); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (a *Adapter) NewApplicationForEvent(app entities.EventApplication) error {
+	return a.post(eventApplication, audienceAdmin, applicationData{
+		ID:          app.ID,
+		Name:        app.Name,
+		Phone:       app.Phone,
+		CheckIn:     app.CheckIn,
+		GuestsCount: app.GuestsCount,
+	})
+}
+
+func (a *Adapter) post(event, audience string, data any) error {
+	body, err := json.Marshal(payload{
+		Event:      event,
+		Audience:   audience,
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	})
+	if err != nil {
+		return err
+	}
+
+	mac := hmac.New(sha256.New, a.secret)
+	mac.Write(body)
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	var errs []error
+	for _, url := range a.urls {
+		if err = a.send(context.Background(), url, body, signature); err != nil {
+			a.logger.Error("post webhook", zeroslog.ErrorKey, err, "event", event, "url", url)
+			errs = append(errs, fmt.Errorf("post %s to %s: %w", event, url, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (a *Adapter) send(ctx context.Context, url string, body []byte, signature string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(signatureHeader, signature)
+
+	resp, err := a.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func createdData(msg entities.ReservationCreatedMessage) reservationData {
+	return reservationData{
+		UUID:        msg.UUID.String(),
+		HouseName:   msg.HouseName,
+		GuestUUID:   msg.GuestUUID.String(),
+		GuestName:   msg.GuestName,
+		GuestPhone:  msg.GuestPhone,
+		GuestEmail:  msg.GuestEmail,
+		CheckIn:     msg.CheckIn.Format(time.DateOnly),
+		CheckOut:    msg.CheckOut.Format(time.DateOnly),
+		GuestsCount: msg.GuestsCount,
+		TotalPrice:  msg.TotalPrice,
+	}
+}
+
+func cancelledData(msg entities.ReservationCancelledMessage) reservationData {
+	return reservationData{
+		UUID:       msg.UUID.String(),
+		HouseName:  msg.HouseName,
+		GuestUUID:  msg.GuestUUID.String(),
+		GuestName:  msg.GuestName,
+		GuestPhone: msg.GuestPhone,
+		GuestEmail: msg.GuestEmail,
+		CheckIn:    msg.CheckIn.Format(time.DateOnly),
+		CheckOut:   msg.CheckOut.Format(time.DateOnly),
+		TotalPrice: msg.TotalPrice,
+	}
+}
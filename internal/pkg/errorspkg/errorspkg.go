@@ -7,8 +7,40 @@ import (
 )
 
 var (
-	ErrInternalService         = errors.New("internal service error")
-	ErrInvalidVerificationCode = errors.New("code expired or invalid")
+	ErrInternalService          = errors.New("internal service error")
+	ErrUnauthorized             = errors.New("admin token is missing or invalid")
+	ErrInvalidVerificationCode  = errors.New("code expired or invalid")
+	ErrInvalidCalendarToken     = errors.New("calendar token invalid")
+	ErrUnknownNotifyChannel     = errors.New("unknown notification channel")
+	ErrUnknownTemplate          = errors.New("unknown template or locale")
+	ErrInvalidTemplate          = errors.New("invalid template")
+	ErrInvalidReviewStatus      = errors.New("review status must be approved or rejected")
+	ErrInvalidRating            = errors.New("rating must be from 1 to 5")
+	ErrInvalidPeriod            = errors.New("period end must be after its start")
+	ErrPhoneQueryTooShort       = errors.New("phone must contain at least 5 digits")
+	ErrUnknownHouse             = errors.New("unknown house")
+	ErrInvalidTgID              = errors.New("telegram user id must be positive")
+	ErrBookingStep              = errors.New("booking action doesn't match the current step")
+	ErrInvalidPhone             = errors.New("phone must be a valid international or russian number")
+	ErrMergeSameGuest           = errors.New("can't merge a guest into itself")
+	ErrGuestConflict            = errors.New("phone or telegram account belongs to another guest")
+	ErrGuestQueryTooShort       = errors.New("query must contain at least 3 characters")
+	ErrGuestHasActiveStays      = errors.New("guest has upcoming or ongoing reservations")
+	ErrInvalidApplicationStatus = errors.New("application status must be new, contacted, quoted, won or lost")
+	ErrEventWithoutHouses       = errors.New("event must reserve at least one house")
+	ErrEventBathhouse           = errors.New("bathhouse must belong to one of the event houses")
+	ErrBathhouseUnavailable     = errors.New("bathhouse is already booked on the event dates")
+	ErrInvalidDeposit           = errors.New("deposit must be from 0 to the event price")
+	ErrInvalidGuestsCount       = errors.New("guests count must be positive")
+	ErrUnknownMediaOwner        = errors.New("media owner must be house, extra or bathhouse")
+	ErrMediaTooLarge            = errors.New("file is too large")
+	ErrUnsupportedMedia         = errors.New("file must be a jpeg, png or webp image")
+	ErrMediaOrder               = errors.New("order must list every media of the owner exactly once")
+	ErrInvalidBed               = errors.New("bed kind must be double, single, sofa or bunk with a positive count")
+	ErrInvalidArea              = errors.New("area must not be negative")
+	ErrInvalidTag               = errors.New("tag must be 1 to 32 characters long")
+	ErrFlexibleMode             = errors.New("flexible search mode must be shift, weekend or nights")
+	ErrFlexibleRange            = errors.New("flexible search range is out of limits")
 )
 
 type ErrViperReadInConfig struct {
@@ -115,3 +147,33 @@ func (e ErrPanicWrapper) Error() string {
 func NewErrPanicWrapper(err interface{}) error {
 	return &ErrPanicWrapper{err: err}
 }
+
+// ErrRetryAfter marks a temporary failure where the remote side told us how long to wait.
+type ErrRetryAfter struct {
+	After    time.Duration
+	errorMsg error
+}
+
+func (err *ErrRetryAfter) Error() string {
+	return fmt.Sprintf("retry after %s: %v", err.After, err.errorMsg)
+}
+
+func (err *ErrRetryAfter) Unwrap() error {
+	return err.errorMsg
+}
+
+func NewErrRetryAfter(after time.Duration, err error) error {
+	return &ErrRetryAfter{After: after, errorMsg: err}
+}
+
+type ErrVerificationLocked struct {
+	Until time.Time
+}
+
+func (err *ErrVerificationLocked) Error() string {
+	return fmt.Sprintf("verification locked until %s", err.Until.Format(time.RFC3339))
+}
+
+func NewErrVerificationLocked(until time.Time) error {
+	return &ErrVerificationLocked{Until: until}
+}
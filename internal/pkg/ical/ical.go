@@ -0,0 +1,93 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"time"
+)
+
+const (
+	crlf          = "\r\n"
+	maxLineOctets = 75
+	dateLayout    = "20060102"
+	stampLayout   = "20060102T150405Z"
+)
+
+type (
+	// Calendar is a minimal VCALENDAR (RFC 5545) containing all-day events.
+	Calendar struct {
+		ProdID string
+		Name   string
+		Events []Event
+	}
+
+	// Event is an all-day VEVENT, End is exclusive like our dateranges.
+	Event struct {
+		UID     string
+		Summary string
+		Start   time.Time
+		End     time.Time
+		Stamp   time.Time
+	}
+)
+
+func (c Calendar) Encode() []byte {
+	var buf bytes.Buffer
+
+	writeLine(&buf, "BEGIN:VCALENDAR")
+	writeLine(&buf, "VERSION:2.0")
+	writeLine(&buf, "PRODID:"+c.ProdID)
+	writeLine(&buf, "CALSCALE:GREGORIAN")
+	writeLine(&buf, "METHOD:PUBLISH")
+	if c.Name != "" {
+		writeLine(&buf, "X-WR-CALNAME:"+escapeText(c.Name))
+	}
+
+	for _, e := range c.Events {
+		writeLine(&buf, "BEGIN:VEVENT")
+		writeLine(&buf, "UID:"+escapeText(e.UID))
+		writeLine(&buf, "DTSTAMP:"+e.Stamp.UTC().Format(stampLayout))
+		writeLine(&buf, "DTSTART;VALUE=DATE:"+e.Start.Format(dateLayout))
+		writeLine(&buf, "DTEND;VALUE=DATE:"+e.End.Format(dateLayout))
+		writeLine(&buf, "SUMMARY:"+escapeText(e.Summary))
+		writeLine(&buf, "TRANSP:OPAQUE")
+		writeLine(&buf, "END:VEVENT")
+	}
+
+	writeLine(&buf, "END:VCALENDAR")
+
+	return buf.Bytes()
+}
+
+// writeLine folds content lines longer than 75 octets without splitting UTF-8 sequences.
+func writeLine(buf *bytes.Buffer, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(line[cut]) {
+			cut--
+		}
+		buf.WriteString(line[:cut])
+		buf.WriteString(crlf + " ")
+		line = line[cut:]
+		// continuation lines start with a space that counts towards the limit
+		limit = maxLineOctets - 1
+	}
+	buf.WriteString(line)
+	buf.WriteString(crlf)
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+func escapeText(s string) string {
+	r := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	)
+	return r.Replace(s)
+}
@@ -0,0 +1,229 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	dateTimeLayout = "20060102T150405"
+	statusCanceled = "CANCELLED"
+)
+
+var (
+	ErrNotCalendar = errors.New("ical: stream is not a VCALENDAR")
+
+	durationRe = regexp.MustCompile(`^\+?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+)
+
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// Parse reads all-day and timed VEVENTs from an iCal stream. Timed events are reduced to the
+// dates they occupy in their own time zone, so an 11:00 checkout frees that day; cancelled
+// events are skipped.
+func Parse(r io.Reader) ([]Event, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		events     []Event
+		current    *Event
+		cancelled  bool
+		hasEnd     bool
+		duration   time.Duration
+		inCalendar bool
+	)
+
+	for i, line := range lines {
+		if line == "" {
+			continue
+		}
+		prop, pErr := parseProperty(line)
+		if pErr != nil {
+			return nil, fmt.Errorf("ical: line %d: %w", i+1, pErr)
+		}
+
+		switch {
+		case prop.name == "BEGIN" && prop.value == "VCALENDAR":
+			inCalendar = true
+		case prop.name == "BEGIN" && prop.value == "VEVENT":
+			current = &Event{}
+			cancelled, hasEnd, duration = false, false, 0
+		case prop.name == "END" && prop.value == "VEVENT":
+			if current == nil {
+				continue
+			}
+			if !hasEnd {
+				current.End = current.Start.Add(duration)
+			}
+			if e, ok := normalizeEvent(*current); ok && !cancelled {
+				events = append(events, e)
+			}
+			current = nil
+		case current == nil:
+			continue
+		case prop.name == "UID":
+			current.UID = prop.value
+		case prop.name == "SUMMARY":
+			current.Summary = unescapeText(prop.value)
+		case prop.name == "STATUS":
+			cancelled = strings.EqualFold(prop.value, statusCanceled)
+		case prop.name == "DTSTAMP":
+			current.Stamp, _ = parseTime(prop)
+		case prop.name == "DTSTART":
+			if current.Start, err = parseTime(prop); err != nil {
+				return nil, fmt.Errorf("ical: line %d: %w", i+1, err)
+			}
+		case prop.name == "DTEND":
+			if current.End, err = parseTime(prop); err != nil {
+				return nil, fmt.Errorf("ical: line %d: %w", i+1, err)
+			}
+			hasEnd = true
+		case prop.name == "DURATION":
+			if duration, err = parseDuration(prop.value); err != nil {
+				return nil, fmt.Errorf("ical: line %d: %w", i+1, err)
+			}
+		}
+	}
+
+	if !inCalendar {
+		return nil, ErrNotCalendar
+	}
+
+	return events, nil
+}
+
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(line) > 0 && (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	return lines, scanner.Err()
+}
+
+func parseProperty(line string) (property, error) {
+	inQuotes := false
+	colon := -1
+	for i, ch := range line {
+		if ch == '"' {
+			inQuotes = !inQuotes
+		}
+		if ch == ':' && !inQuotes {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return property{}, fmt.Errorf("malformed content line %q", line)
+	}
+
+	head := strings.Split(line[:colon], ";")
+	prop := property{
+		name:   strings.ToUpper(head[0]),
+		params: make(map[string]string, len(head)-1),
+		value:  line[colon+1:],
+	}
+	for _, p := range head[1:] {
+		k, v, _ := strings.Cut(p, "=")
+		prop.params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+	}
+
+	return prop, nil
+}
+
+func parseTime(p property) (time.Time, error) {
+	if p.params["VALUE"] == "DATE" || len(p.value) == len(dateLayout) {
+		return time.Parse(dateLayout, p.value)
+	}
+
+	if strings.HasSuffix(p.value, "Z") {
+		t, err := time.Parse(dateTimeLayout, strings.TrimSuffix(p.value, "Z"))
+		if err != nil {
+			return time.Time{}, err
+		}
+		return t.In(time.Local), nil
+	}
+
+	loc := time.Local
+	if tzid, ok := p.params["TZID"]; ok {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+
+	return time.ParseInLocation(dateTimeLayout, p.value, loc)
+}
+
+func parseDuration(v string) (time.Duration, error) {
+	m := durationRe.FindStringSubmatch(v)
+	if m == nil {
+		return 0, fmt.Errorf("unsupported duration %q", v)
+	}
+
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var d time.Duration
+	for i, unit := range units {
+		if m[i+1] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(m[i+1])
+		if err != nil {
+			return 0, err
+		}
+		d += time.Duration(n) * unit
+	}
+
+	return d, nil
+}
+
+// normalizeEvent turns an event into a [start, end) range of whole dates.
+func normalizeEvent(e Event) (Event, bool) {
+	if e.UID == "" || e.Start.IsZero() {
+		return e, false
+	}
+
+	start := truncateDate(e.Start)
+	end := truncateDate(e.End)
+	if !end.After(start) {
+		end = start.AddDate(0, 0, 1)
+	}
+
+	e.Start, e.End = start, end
+	return e, true
+}
+
+func truncateDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func unescapeText(s string) string {
+	r := strings.NewReplacer(
+		`\\`, `\`,
+		`\;`, ";",
+		`\,`, ",",
+		`\n`, "\n",
+		`\N`, "\n",
+	)
+	return r.Replace(s)
+}
@@ -0,0 +1,88 @@
+// Package imagepkg decodes uploaded photos and makes their scaled down copies in pure Go.
+package imagepkg
+
+import (
+	"bytes"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+const (
+	JPEG = "image/jpeg"
+	PNG  = "image/png"
+	WebP = "image/webp"
+)
+
+var extensions = map[string]string{
+	JPEG: ".jpg",
+	PNG:  ".png",
+	WebP: ".webp",
+}
+
+// Detect returns the content type by the file signature, empty for unsupported formats.
+func Detect(data []byte) string {
+	contentType := http.DetectContentType(data)
+	if _, ok := extensions[contentType]; !ok {
+		return ""
+	}
+	return contentType
+}
+
+func Extension(contentType string) string {
+	return extensions[contentType]
+}
+
+// Size reads the dimensions from the header without decoding the pixels.
+func Size(data []byte) (int, int, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return 0, 0, err
+	}
+	return cfg.Width, cfg.Height, nil
+}
+
+func Decode(data []byte) (image.Image, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	return img, err
+}
+
+// Fit scales img down to width keeping the aspect ratio, narrower images are returned as is.
+func Fit(img image.Image, width int) image.Image {
+	bounds := img.Bounds()
+	if width <= 0 || bounds.Dx() <= width {
+		return img
+	}
+
+	height := max(1, (bounds.Dy()*width+bounds.Dx()/2)/bounds.Dx())
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+	return dst
+}
+
+// Encode writes img as JPEG of the given quality, or as PNG when it has transparent pixels.
+func Encode(img image.Image, quality int) ([]byte, string, error) {
+	var buf bytes.Buffer
+
+	if opaque(img) {
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), JPEG, nil
+	}
+
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), PNG, nil
+}
+
+func opaque(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return o.Opaque()
+	}
+	return false
+}
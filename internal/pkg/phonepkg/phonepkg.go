@@ -0,0 +1,54 @@
+// Package phonepkg brings guest phone numbers to E.164 so one guest has one phone value.
+package phonepkg
+
+import (
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"strings"
+	"unicode"
+)
+
+const (
+	defaultCountryCode = "7"
+	nationalDigits     = 10
+	minDigits          = 8
+	maxDigits          = 15
+)
+
+// Normalize returns the number as +<digits>. Numbers without a country code are
+// treated as Russian: 8XXXXXXXXXX and XXXXXXXXXX both become +7XXXXXXXXXX.
+func Normalize(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	international := strings.HasPrefix(raw, "+") || strings.HasPrefix(raw, "00")
+
+	var digits strings.Builder
+	for _, r := range raw {
+		switch {
+		case unicode.IsDigit(r):
+			digits.WriteRune(r)
+		case unicode.IsSpace(r), r == '+', r == '-', r == '(', r == ')', r == '.':
+		default:
+			return "", errorspkg.ErrInvalidPhone
+		}
+	}
+
+	number := digits.String()
+	switch {
+	case strings.HasPrefix(raw, "00"):
+		number = number[2:]
+	case international:
+	case len(number) == nationalDigits:
+		number = defaultCountryCode + number
+	case len(number) == nationalDigits+1 && number[0] == '8':
+		number = defaultCountryCode + number[1:]
+	}
+
+	if len(number) < minDigits || len(number) > maxDigits || number[0] == '0' {
+		return "", errorspkg.ErrInvalidPhone
+	}
+	return "+" + number, nil
+}
+
+// NormalizeEmail makes emails comparable, the empty string stays empty.
+func NormalizeEmail(raw string) string {
+	return strings.ToLower(strings.TrimSpace(raw))
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/google/uuid"
+	"time"
+)
+
+type IAdmin interface {
+	IsAdmin(ctx context.Context, tgID int64) (bool, error)
+	GetAdmins(ctx context.Context) ([]entities.BotAdmin, error)
+	AddAdmin(ctx context.Context, admin entities.BotAdmin) error
+	DeleteAdmin(ctx context.Context, tgID int64) error
+	GetAgenda(ctx context.Context, day time.Time) (entities.DayAgenda, error)
+	GetReservation(ctx context.Context, reservationUUID uuid.UUID) (entities.AdminReservation, error)
+	FindReservationsByPhone(ctx context.Context, phoneDigits string) ([]entities.AdminReservation, error)
+}
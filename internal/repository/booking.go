@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"time"
+)
+
+type IBookingSessions interface {
+	Get(ctx context.Context, chatID int64, updatedAfter time.Time) (entities.BookingSession, error)
+	Save(ctx context.Context, session entities.BookingSession) error
+	Delete(ctx context.Context, chatID int64) error
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"time"
+)
+
+type ICalendar interface {
+	GetToken(ctx context.Context, houseID int) (string, error)
+	RegenerateToken(ctx context.Context, houseID int) (string, error)
+	GetBusyPeriods(ctx context.Context, houseID int) ([]entities.BusyPeriod, error)
+	GetSources(ctx context.Context) ([]entities.CalendarSource, error)
+	GetSourcesByHouse(ctx context.Context, houseID int) ([]entities.CalendarSource, error)
+	AddSource(ctx context.Context, source entities.CalendarSource) (int, error)
+	DeleteSource(ctx context.Context, houseID, sourceID int) error
+	SyncImported(ctx context.Context, source entities.CalendarSource, events []entities.ImportedEvent) ([]entities.Blackout, error)
+	AddBlackout(ctx context.Context, blackout entities.Blackout) (int, error)
+	GetOverlappingReservations(ctx context.Context, houseID int, checkIn, checkOut time.Time) ([]entities.ReservationConflict, error)
+}
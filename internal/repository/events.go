@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/google/uuid"
+	"time"
+)
+
+type IEventApplications interface {
+	Create(ctx context.Context, req entities.NewApplication) (entities.EventApplication, error)
+	Get(ctx context.Context, id int) (entities.EventApplication, error)
+	GetAll(ctx context.Context, status entities.ApplicationStatus) ([]entities.EventApplication, error)
+	Update(ctx context.Context, id int, upd entities.EventApplicationUpdate) (entities.EventApplication, error)
+}
+
+type IEventBookings interface {
+	Create(ctx context.Context, booking entities.EventBooking) (uuid.UUID, error)
+	Get(ctx context.Context, bookingUUID uuid.UUID) (entities.EventBooking, error)
+	GetAll(ctx context.Context, from, to time.Time) ([]entities.EventBooking, error)
+	Cancel(ctx context.Context, bookingUUID uuid.UUID) error
+}
@@ -4,17 +4,25 @@ import (
 	"context"
 	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
 	"github.com/google/uuid"
+	"time"
 )
 
 type IGuests interface {
-	Get(ctx context.Context, guest entities.Guest) (Guest, error)
-	Create(ctx context.Context, guest entities.Guest) error
+	Find(ctx context.Context, phone, email string) (Guest, error)
+	GetByTgID(ctx context.Context, tgID int64) (Guest, error)
+	Search(ctx context.Context, query string, limit int) ([]entities.GuestProfile, error)
+	Merge(ctx context.Context, targetUUID, sourceUUID uuid.UUID) (entities.GuestMerge, error)
+	ExportData(ctx context.Context, guestUUID uuid.UUID) (entities.GuestData, error)
+	Anonymize(ctx context.Context, guestUUID uuid.UUID) error
+	GetInactive(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, error)
+	GetNotifyChannels(ctx context.Context, guestUUID uuid.UUID) ([]entities.NotificationChannel, error)
 }
 
 type Guest struct {
-	UUID  uuid.UUID
-	Name  string
-	Phone string
-	Email string
-	TgId  int64
+	UUID   uuid.UUID
+	Name   string
+	Phone  string
+	Email  string
+	TgId   int64
+	Locale string
 }
@@ -8,7 +8,11 @@ import (
 type IHouses interface {
 	GetAll(ctx context.Context) ([]entities.House, error)
 	GetOne(ctx context.Context, id int) (entities.House, error)
+	GetByIDs(ctx context.Context, ids []int) ([]entities.House, error)
 	Add(ctx context.Context, house []entities.House) error
 	Update(ctx context.Context, house entities.House) error
 	Delete(ctx context.Context, id int) error
+	UpdateAmenities(ctx context.Context, id int, amenities entities.HouseAmenities) error
+	UpdateTags(ctx context.Context, id int, tags []string) error
+	GetTags(ctx context.Context) ([]entities.HouseTag, error)
 }
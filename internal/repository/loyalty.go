@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"context"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/google/uuid"
+)
+
+type ILoyalty interface {
+	GetStats(ctx context.Context, guestUUID uuid.UUID) (entities.LoyaltyStats, error)
+}
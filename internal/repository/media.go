@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+)
+
+type IMedia interface {
+	Create(ctx context.Context, media entities.Media) (entities.Media, error)
+	Get(ctx context.Context, id int) (entities.Media, error)
+	GetByOwner(ctx context.Context, owner entities.MediaOwner, ownerID int) ([]entities.Media, error)
+	Reorder(ctx context.Context, owner entities.MediaOwner, ownerID int, ids []int) error
+	Delete(ctx context.Context, id int) (entities.Media, error)
+}
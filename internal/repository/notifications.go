@@ -0,0 +1,10 @@
+package repository
+
+import (
+	"context"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+)
+
+type INotifications interface {
+	AddDelivery(ctx context.Context, d entities.NotificationDelivery) error
+}
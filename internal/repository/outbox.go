@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"time"
+)
+
+type IOutbox interface {
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]entities.OutboxMessage, error)
+	MarkSent(ctx context.Context, id int64) error
+	MarkRetry(ctx context.Context, id int64, attempts int, nextAttemptAt time.Time, lastErr string) error
+	MarkFailed(ctx context.Context, id int64, attempts int, lastErr string) error
+	GetFailed(ctx context.Context) ([]entities.OutboxMessage, error)
+	Requeue(ctx context.Context, id int64) error
+}
@@ -0,0 +1,251 @@
+package postgres
+
+import (
+	"context"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"strconv"
+	"time"
+)
+
+const adminReservationColumns = `
+	r.uuid,
+	h.name,
+	g.name,
+	COALESCE(g.phone, ''),
+	r.guests_count,
+	LOWER(r.stay),
+	UPPER(r.stay),
+	r.status,
+	r.total_price
+`
+
+type AdminRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewAdminRepo(pool *pgxpool.Pool) *AdminRepo {
+	return &AdminRepo{pool: pool}
+}
+
+func (r *AdminRepo) IsAdmin(ctx context.Context, tgID int64) (bool, error) {
+	const method = "adminRepo.IsAdmin"
+
+	var exists bool
+	err := r.pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM bot_admins WHERE tg_user_id = $1)`, tgID).Scan(&exists)
+	if err != nil {
+		return false, errorspkg.NewErrRepoFailed("QueryRow", method, err)
+	}
+	return exists, nil
+}
+
+func (r *AdminRepo) GetAdmins(ctx context.Context) ([]entities.BotAdmin, error) {
+	const method = "adminRepo.GetAdmins"
+
+	rows, err := r.pool.Query(ctx, `SELECT tg_user_id, name, created_at FROM bot_admins ORDER BY created_at`)
+	if err != nil {
+		return nil, errorspkg.NewErrRepoFailed("Query", method, err)
+	}
+	defer rows.Close()
+
+	var result []entities.BotAdmin
+	for rows.Next() {
+		var a entities.BotAdmin
+		if err = rows.Scan(&a.TgID, &a.Name, &a.CreatedAt); err != nil {
+			return nil, errorspkg.NewErrRepoFailed("Scan", method, err)
+		}
+		result = append(result, a)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errorspkg.NewErrRepoFailed("rows.Err", method, err)
+	}
+
+	return result, nil
+}
+
+func (r *AdminRepo) AddAdmin(ctx context.Context, admin entities.BotAdmin) error {
+	const method = "adminRepo.AddAdmin"
+
+	query := `
+		INSERT INTO bot_admins (tg_user_id, name)
+		VALUES ($1, $2)
+		ON CONFLICT (tg_user_id) DO UPDATE SET name = EXCLUDED.name
+	`
+
+	if _, err := r.pool.Exec(ctx, query, admin.TgID, admin.Name); err != nil {
+		return errorspkg.NewErrRepoFailed("Exec", method, err)
+	}
+	return nil
+}
+
+func (r *AdminRepo) DeleteAdmin(ctx context.Context, tgID int64) error {
+	const method = "adminRepo.DeleteAdmin"
+
+	tag, err := r.pool.Exec(ctx, `DELETE FROM bot_admins WHERE tg_user_id = $1`, tgID)
+	if err != nil {
+		return errorspkg.NewErrRepoFailed("Exec", method, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errorspkg.NewErrRepoNotFound("admin", strconv.FormatInt(tgID, 10), method)
+	}
+	return nil
+}
+
+// GetAgenda returns arrivals, departures and bathhouse sessions of active reservations on the day.
+func (r *AdminRepo) GetAgenda(ctx context.Context, day time.Time) (entities.DayAgenda, error) {
+	const method = "adminRepo.GetAgenda"
+
+	agenda := entities.DayAgenda{Date: day}
+	date := day.Format(time.DateOnly)
+
+	var err error
+	agenda.Arrivals, err = r.queryReservations(ctx, method, `
+		SELECT `+adminReservationColumns+`
+		FROM reservations r
+		JOIN guests g ON r.guest_uuid = g.uuid
+		JOIN houses h ON r.house_id = h.id
+		WHERE LOWER(r.stay) = $1::date AND r.status IN ('pending', 'confirmed', 'checked_in')
+		ORDER BY h.name
+	`, date)
+	if err != nil {
+		return agenda, err
+	}
+
+	agenda.Departures, err = r.queryReservations(ctx, method, `
+		SELECT `+adminReservationColumns+`
+		FROM reservations r
+		JOIN guests g ON r.guest_uuid = g.uuid
+		JOIN houses h ON r.house_id = h.id
+		WHERE UPPER(r.stay) = $1::date AND r.status IN ('confirmed', 'checked_in', 'checked_out')
+		ORDER BY h.name
+	`, date)
+	if err != nil {
+		return agenda, err
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT
+			r.uuid,
+			h.name,
+			b.name,
+			to_char(br.time_from, 'HH24:MI'),
+			to_char(br.time_to, 'HH24:MI'),
+			fo.name,
+			g.name,
+			COALESCE(g.phone, '')
+		FROM bathhouse_reservations br
+		JOIN reservations r ON br.reservation_uuid = r.uuid
+		JOIN bathhouses b ON br.bathhouse_id = b.id
+		JOIN houses h ON r.house_id = h.id
+		JOIN guests g ON r.guest_uuid = g.uuid
+		LEFT JOIN bathhouse_fill_options fo ON br.fill_option_id = fo.id
+		WHERE br.date = $1::date AND r.status IN ('pending', 'confirmed', 'checked_in')
+		ORDER BY br.time_from, h.name
+	`, date)
+	if err != nil {
+		return agenda, errorspkg.NewErrRepoFailed("Query Bathhouses", method, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var s entities.BathhouseSession
+		if err = rows.Scan(
+			&s.ReservationUUID,
+			&s.HouseName,
+			&s.Name,
+			&s.TimeFrom,
+			&s.TimeTo,
+			&s.FillOption,
+			&s.GuestName,
+			&s.GuestPhone,
+		); err != nil {
+			return agenda, errorspkg.NewErrRepoFailed("Scan Bathhouses", method, err)
+		}
+		agenda.Bathhouses = append(agenda.Bathhouses, s)
+	}
+	if err = rows.Err(); err != nil {
+		return agenda, errorspkg.NewErrRepoFailed("rows.Err", method, err)
+	}
+
+	// Event houses have no guest and are left out above, the event is listed once instead.
+	agenda.Events, err = queryEventBookings(ctx, r.pool, method, `
+		SELECT `+eventBookingColumns+`
+		FROM event_bookings e
+		WHERE e.stay @> $1::date AND e.status = $2
+		ORDER BY LOWER(e.stay)
+	`, date, entities.EventConfirmed)
+	if err != nil {
+		return agenda, err
+	}
+
+	return agenda, nil
+}
+
+func (r *AdminRepo) GetReservation(ctx context.Context, reservationUUID uuid.UUID) (entities.AdminReservation, error) {
+	const method = "adminRepo.GetReservation"
+
+	res, err := r.queryReservations(ctx, method, `
+		SELECT `+adminReservationColumns+`
+		FROM reservations r
+		JOIN guests g ON r.guest_uuid = g.uuid
+		JOIN houses h ON r.house_id = h.id
+		WHERE r.uuid = $1
+	`, reservationUUID)
+	if err != nil {
+		return entities.AdminReservation{}, err
+	}
+	if len(res) == 0 {
+		return entities.AdminReservation{}, errorspkg.NewErrRepoNotFound("reservation", reservationUUID.String(), method)
+	}
+	return res[0], nil
+}
+
+// FindReservationsByPhone matches the guest phone by its trailing digits, so +7, 8 and
+// formatting differences don't matter. Only recent and upcoming stays are returned.
+func (r *AdminRepo) FindReservationsByPhone(ctx context.Context, phoneDigits string) ([]entities.AdminReservation, error) {
+	const method = "adminRepo.FindReservationsByPhone"
+
+	return r.queryReservations(ctx, method, `
+		SELECT `+adminReservationColumns+`
+		FROM reservations r
+		JOIN guests g ON r.guest_uuid = g.uuid
+		JOIN houses h ON r.house_id = h.id
+		WHERE regexp_replace(COALESCE(g.phone, ''), '\D', '', 'g') LIKE '%' || $1
+			AND UPPER(r.stay) >= current_date - 30
+		ORDER BY LOWER(r.stay)
+	`, phoneDigits)
+}
+
+func (r *AdminRepo) queryReservations(ctx context.Context, method, query string, args ...any) ([]entities.AdminReservation, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, errorspkg.NewErrRepoFailed("Query", method, err)
+	}
+	defer rows.Close()
+
+	var result []entities.AdminReservation
+	for rows.Next() {
+		var res entities.AdminReservation
+		if err = rows.Scan(
+			&res.UUID,
+			&res.HouseName,
+			&res.GuestName,
+			&res.GuestPhone,
+			&res.GuestsCount,
+			&res.CheckIn,
+			&res.CheckOut,
+			&res.Status,
+			&res.TotalPrice,
+		); err != nil {
+			return nil, errorspkg.NewErrRepoFailed("Scan", method, err)
+		}
+		result = append(result, res)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errorspkg.NewErrRepoFailed("rows.Err", method, err)
+	}
+
+	return result, nil
+}
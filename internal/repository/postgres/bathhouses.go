@@ -85,65 +85,9 @@ func (r *BathhousesRepo) GetAll(ctx context.Context) ([]entities.Bathhouse, erro
 func (r *BathhousesRepo) GetByHouse(ctx context.Context, houseID int) ([]entities.Bathhouse, error) {
 	const method = "BathhousesRepo.GetByHouse"
 
-	rows, err := r.pool.Query(ctx, `
-		SELECT
-			b.id, b.house_id, b.name, b.price, b.description, b.images,
-			f.id, f.name, f.image, f.description, f.price
-		FROM bathhouses b
-		LEFT JOIN bathhouse_fill_options f ON f.bathhouse_id = b.id
-		WHERE b.house_id = $1
-		ORDER BY b.id, f.id
-	`, houseID)
+	result, err := bathhousesByHouses(ctx, r.pool, []int{houseID})
 	if err != nil {
-		return nil, errorspkg.NewErrRepoFailed("pool.Query", method, err)
-	}
-	defer rows.Close()
-
-	bathhouseMap := make(map[int]*entities.Bathhouse)
-
-	for rows.Next() {
-		var (
-			bathhouseID, houseIDDb, fillID                 sql.NullInt64
-			name, description, fillName, fillImg, fillDesc sql.NullString
-			price, fillPrice                               sql.NullFloat64
-			images                                         []string
-		)
-		err = rows.Scan(&bathhouseID, &houseIDDb, &name, &price, &description, &images,
-			&fillID, &fillName, &fillImg, &fillDesc, &fillPrice,
-		)
-		if err != nil {
-			return nil, errorspkg.NewErrRepoFailed("rows.Scan", method, err)
-		}
-
-		bh, exists := bathhouseMap[int(bathhouseID.Int64)]
-		if !exists {
-			bh = &entities.Bathhouse{
-				ID:          int(bathhouseID.Int64),
-				HouseID:     int(houseIDDb.Int64),
-				Name:        name.String,
-				Price:       int(price.Float64),
-				Description: description.String,
-				Images:      images,
-				FillOptions: []entities.BathhouseFillOption{},
-			}
-			bathhouseMap[bh.ID] = bh
-		}
-
-		if fillID.Valid {
-			bh.FillOptions = append(bh.FillOptions, entities.BathhouseFillOption{
-				ID:          int(fillID.Int64),
-				BathhouseID: int(bathhouseID.Int64),
-				Name:        fillName.String,
-				Image:       fillImg.String,
-				Description: fillDesc.String,
-				Price:       int(fillPrice.Float64),
-			})
-		}
-	}
-
-	result := make([]entities.Bathhouse, 0, len(bathhouseMap))
-	for _, bh := range bathhouseMap {
-		result = append(result, *bh)
+		return nil, errorspkg.NewErrRepoFailed("bathhousesByHouses", method, err)
 	}
 	return result, nil
 }
@@ -254,7 +198,10 @@ func (r *BathhousesRepo) Update(ctx context.Context, bh entities.Bathhouse) erro
 	defer func() { _ = tx.Rollback(ctx) }()
 
 	_, err = tx.Exec(ctx, `
-		UPDATE bathhouses SET name=$1, price=$2, description=$3, images=$4 WHERE id=$5
+		UPDATE bathhouses SET name=$1, price=$2, description=$3, images=CASE
+			WHEN EXISTS (SELECT 1 FROM media WHERE owner_type = 'bathhouse' AND owner_id = bathhouses.id) THEN images
+			ELSE $4
+		END WHERE id=$5
 	`, bh.Name, bh.Price, bh.Description, bh.Images, bh.ID)
 	if err != nil {
 		return errorspkg.NewErrRepoFailed("Update bathhouse", method, err)
@@ -285,3 +232,64 @@ func (r *BathhousesRepo) Delete(ctx context.Context, id int) error {
 	}
 	return nil
 }
+
+// bathhousesByHouses loads the bathhouses of all the houses with their fill options in one query,
+// ordered by id.
+func bathhousesByHouses(ctx context.Context, pool *pgxpool.Pool, houseIDs []int) ([]entities.Bathhouse, error) {
+	rows, err := pool.Query(ctx, `
+		SELECT
+			b.id, b.house_id, b.name, b.price, b.description, b.images,
+			f.id, f.name, f.image, f.description, f.price
+		FROM bathhouses b
+		LEFT JOIN bathhouse_fill_options f ON f.bathhouse_id = b.id
+		WHERE b.house_id = ANY($1)
+		ORDER BY b.id, f.id
+	`, houseIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]entities.Bathhouse, 0)
+
+	for rows.Next() {
+		var (
+			bathhouseID, houseID, fillID                   sql.NullInt64
+			name, description, fillName, fillImg, fillDesc sql.NullString
+			price, fillPrice                               sql.NullFloat64
+			images                                         []string
+		)
+		err = rows.Scan(&bathhouseID, &houseID, &name, &price, &description, &images,
+			&fillID, &fillName, &fillImg, &fillDesc, &fillPrice,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(result) == 0 || result[len(result)-1].ID != int(bathhouseID.Int64) {
+			result = append(result, entities.Bathhouse{
+				ID:          int(bathhouseID.Int64),
+				HouseID:     int(houseID.Int64),
+				Name:        name.String,
+				Price:       int(price.Float64),
+				Description: description.String,
+				Images:      images,
+				FillOptions: []entities.BathhouseFillOption{},
+			})
+		}
+		bh := &result[len(result)-1]
+
+		if fillID.Valid {
+			bh.FillOptions = append(bh.FillOptions, entities.BathhouseFillOption{
+				ID:          int(fillID.Int64),
+				BathhouseID: bh.ID,
+				Name:        fillName.String,
+				Image:       fillImg.String,
+				Description: fillDesc.String,
+				Price:       int(fillPrice.Float64),
+			})
+		}
+	}
+
+	return result, rows.Err()
+}
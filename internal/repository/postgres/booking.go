@@ -0,0 +1,111 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"strconv"
+	"time"
+)
+
+type BookingSessionsRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewBookingSessionsRepo(pool *pgxpool.Pool) *BookingSessionsRepo {
+	return &BookingSessionsRepo{pool: pool}
+}
+
+// bookingDraft is the jsonb layout of booking_sessions.data.
+type bookingDraft struct {
+	Month       time.Time                       `json:"month"`
+	CheckIn     time.Time                       `json:"checkIn"`
+	CheckOut    time.Time                       `json:"checkOut"`
+	GuestsCount int                             `json:"guestsCount"`
+	HouseID     int                             `json:"houseId"`
+	HouseName   string                          `json:"houseName"`
+	StayPrice   int                             `json:"stayPrice"`
+	Extras      []int                           `json:"extras"`
+	Bathhouse   []entities.BathhouseReservation `json:"bathhouse"`
+}
+
+// Get returns the chat's session unless it was last touched before updatedAfter.
+func (r *BookingSessionsRepo) Get(
+	ctx context.Context,
+	chatID int64,
+	updatedAfter time.Time,
+) (entities.BookingSession, error) {
+	const method = "bookingSessionsRepo.Get"
+
+	query := `
+		SELECT step, data, updated_at
+		FROM booking_sessions
+		WHERE chat_id = $1 AND updated_at > $2
+	`
+
+	var (
+		session = entities.BookingSession{ChatID: chatID}
+		draft   bookingDraft
+	)
+	err := r.pool.QueryRow(ctx, query, chatID, updatedAfter).Scan(&session.Step, &draft, &session.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return session, errorspkg.NewErrRepoNotFound("booking session", strconv.FormatInt(chatID, 10), method)
+		}
+		return session, errorspkg.NewErrRepoFailed("QueryRow", method, err)
+	}
+
+	session.Month = draft.Month
+	session.CheckIn = draft.CheckIn
+	session.CheckOut = draft.CheckOut
+	session.GuestsCount = draft.GuestsCount
+	session.HouseID = draft.HouseID
+	session.HouseName = draft.HouseName
+	session.StayPrice = draft.StayPrice
+	session.Extras = draft.Extras
+	session.Bathhouse = draft.Bathhouse
+
+	return session, nil
+}
+
+func (r *BookingSessionsRepo) Save(ctx context.Context, session entities.BookingSession) error {
+	const method = "bookingSessionsRepo.Save"
+
+	query := `
+		INSERT INTO booking_sessions (chat_id, step, data, updated_at)
+		VALUES ($1, $2, $3, now())
+		ON CONFLICT (chat_id) DO UPDATE
+		SET step = EXCLUDED.step,
+			data = EXCLUDED.data,
+			updated_at = now()
+	`
+
+	draft := bookingDraft{
+		Month:       session.Month,
+		CheckIn:     session.CheckIn,
+		CheckOut:    session.CheckOut,
+		GuestsCount: session.GuestsCount,
+		HouseID:     session.HouseID,
+		HouseName:   session.HouseName,
+		StayPrice:   session.StayPrice,
+		Extras:      session.Extras,
+		Bathhouse:   session.Bathhouse,
+	}
+
+	if _, err := r.pool.Exec(ctx, query, session.ChatID, session.Step, draft); err != nil {
+		return errorspkg.NewErrRepoFailed("Exec", method, err)
+	}
+	return nil
+}
+
+func (r *BookingSessionsRepo) Delete(ctx context.Context, chatID int64) error {
+	const method = "bookingSessionsRepo.Delete"
+
+	if _, err := r.pool.Exec(ctx, `DELETE FROM booking_sessions WHERE chat_id = $1`, chatID); err != nil {
+		return errorspkg.NewErrRepoFailed("Exec", method, err)
+	}
+	return nil
+}
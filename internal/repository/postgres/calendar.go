@@ -0,0 +1,355 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"strconv"
+	"time"
+)
+
+// exclusionViolation is the Postgres error code of a violated EXCLUDE constraint.
+const exclusionViolation = "23P01"
+
+type CalendarRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewCalendarRepo(pool *pgxpool.Pool) *CalendarRepo {
+	return &CalendarRepo{pool: pool}
+}
+
+func (r *CalendarRepo) GetToken(ctx context.Context, houseID int) (string, error) {
+	const method = "calendarRepo.GetToken"
+
+	query := `
+		SELECT calendar_token FROM houses
+			WHERE id = $1
+	`
+
+	var token string
+	err := r.pool.QueryRow(ctx, query, houseID).Scan(&token)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", errorspkg.NewErrRepoNotFound("house", strconv.Itoa(houseID), method)
+		}
+		return "", errorspkg.NewErrRepoFailed("QueryRow", method, err)
+	}
+
+	return token, nil
+}
+
+func (r *CalendarRepo) RegenerateToken(ctx context.Context, houseID int) (string, error) {
+	const method = "calendarRepo.RegenerateToken"
+
+	query := `
+		UPDATE houses
+		SET
+			calendar_token = replace(gen_random_uuid()::text, '-', ''),
+			updated_at     = now()
+		WHERE id = $1
+		RETURNING calendar_token
+	`
+
+	var token string
+	err := r.pool.QueryRow(ctx, query, houseID).Scan(&token)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", errorspkg.NewErrRepoNotFound("house", strconv.Itoa(houseID), method)
+		}
+		return "", errorspkg.NewErrRepoFailed("QueryRow", method, err)
+	}
+
+	return token, nil
+}
+
+// GetBusyPeriods returns local reservations and manual blackouts only: blackouts imported
+// from other platforms are left out, so the platforms don't get their own events back.
+func (r *CalendarRepo) GetBusyPeriods(ctx context.Context, houseID int) ([]entities.BusyPeriod, error) {
+	const method = "calendarRepo.GetBusyPeriods"
+
+	query := `
+		SELECT
+			COALESCE(r.event_uuid, r.uuid)::text,
+			CASE WHEN r.event_uuid IS NULL THEN $2 ELSE $4 END AS kind,
+			LOWER(r.stay),
+			UPPER(r.stay)
+		FROM reservations r
+		WHERE r.house_id = $1
+			AND r.status <> 'cancelled'
+			AND UPPER(r.stay) >= current_date
+		UNION ALL
+		SELECT
+			b.id::text,
+			$3 AS kind,
+			LOWER(b.period),
+			UPPER(b.period)
+		FROM blackouts b
+		WHERE b.house_id = $1
+			AND b.source_id IS NULL
+			AND UPPER(b.period) >= current_date
+		ORDER BY 3
+	`
+
+	rows, err := r.pool.Query(ctx, query, houseID, entities.BusyReservation, entities.BusyBlackout, entities.BusyEvent)
+	if err != nil {
+		return nil, errorspkg.NewErrRepoFailed("Query", method, err)
+	}
+	defer rows.Close()
+
+	var result []entities.BusyPeriod
+	for rows.Next() {
+		var (
+			p  entities.BusyPeriod
+			id string
+		)
+		if err = rows.Scan(&id, &p.Kind, &p.CheckIn, &p.CheckOut); err != nil {
+			return nil, errorspkg.NewErrRepoFailed("Scan", method, err)
+		}
+		p.ID = fmt.Sprintf("%s-%s", p.Kind, id)
+		p.HouseID = houseID
+		result = append(result, p)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errorspkg.NewErrRepoFailed("rows.Err", method, err)
+	}
+
+	return result, nil
+}
+
+func (r *CalendarRepo) GetSources(ctx context.Context) ([]entities.CalendarSource, error) {
+	const method = "calendarRepo.GetSources"
+
+	query := `
+		SELECT id, house_id, name, url, last_synced_at
+		FROM calendar_sources
+		ORDER BY id
+	`
+
+	return r.querySources(ctx, method, query)
+}
+
+func (r *CalendarRepo) GetSourcesByHouse(ctx context.Context, houseID int) ([]entities.CalendarSource, error) {
+	const method = "calendarRepo.GetSourcesByHouse"
+
+	query := `
+		SELECT id, house_id, name, url, last_synced_at
+		FROM calendar_sources
+		WHERE house_id = $1
+		ORDER BY id
+	`
+
+	return r.querySources(ctx, method, query, houseID)
+}
+
+func (r *CalendarRepo) querySources(ctx context.Context, method, query string, args ...any) ([]entities.CalendarSource, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, errorspkg.NewErrRepoFailed("Query", method, err)
+	}
+	defer rows.Close()
+
+	var result []entities.CalendarSource
+	for rows.Next() {
+		var src entities.CalendarSource
+		if err = rows.Scan(
+			&src.ID,
+			&src.HouseID,
+			&src.Name,
+			&src.URL,
+			&src.LastSyncedAt,
+		); err != nil {
+			return nil, errorspkg.NewErrRepoFailed("Scan", method, err)
+		}
+		result = append(result, src)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errorspkg.NewErrRepoFailed("rows.Err", method, err)
+	}
+
+	return result, nil
+}
+
+func (r *CalendarRepo) AddSource(ctx context.Context, source entities.CalendarSource) (int, error) {
+	const method = "calendarRepo.AddSource"
+
+	query := `
+		INSERT INTO calendar_sources (house_id, name, url)
+		VALUES ($1, $2, $3)
+		RETURNING id
+	`
+
+	var id int
+	err := r.pool.QueryRow(ctx, query, source.HouseID, source.Name, source.URL).Scan(&id)
+	if err != nil {
+		return 0, errorspkg.NewErrRepoFailed("QueryRow", method, err)
+	}
+
+	return id, nil
+}
+
+func (r *CalendarRepo) DeleteSource(ctx context.Context, houseID, sourceID int) error {
+	const method = "calendarRepo.DeleteSource"
+
+	tag, err := r.pool.Exec(ctx, `DELETE FROM calendar_sources WHERE id = $1 AND house_id = $2`, sourceID, houseID)
+	if err != nil {
+		return errorspkg.NewErrRepoFailed("Exec", method, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errorspkg.NewErrRepoNotFound("calendar source", strconv.Itoa(sourceID), method)
+	}
+
+	return nil
+}
+
+// SyncImported replaces the blackouts of the source with the given events and returns the
+// blackouts that were created or moved to new dates.
+func (r *CalendarRepo) SyncImported(ctx context.Context, source entities.CalendarSource, events []entities.ImportedEvent) ([]entities.Blackout, error) {
+	const method = "calendarRepo.SyncImported"
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, errorspkg.NewErrRepoFailed("Begin", method, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	upsertQuery := `
+		INSERT INTO blackouts (house_id, period, reason, source_id, external_uid)
+		VALUES ($1, daterange($2::date, $3::date), $4, $5, $6)
+		ON CONFLICT (source_id, external_uid) DO UPDATE
+			SET period = EXCLUDED.period,
+				reason = EXCLUDED.reason
+			WHERE blackouts.period <> EXCLUDED.period
+		RETURNING id, LOWER(period), UPPER(period), reason
+	`
+
+	changed := make([]entities.Blackout, 0)
+	uids := make([]string, 0, len(events))
+	for _, e := range events {
+		uids = append(uids, e.UID)
+
+		reason := source.Name
+		if e.Summary != "" {
+			reason = fmt.Sprintf("%s: %s", source.Name, e.Summary)
+		}
+
+		b := entities.Blackout{
+			HouseID:  source.HouseID,
+			SourceID: &source.ID,
+		}
+		err = tx.QueryRow(ctx, upsertQuery,
+			source.HouseID,
+			e.CheckIn.Format(time.DateOnly),
+			e.CheckOut.Format(time.DateOnly),
+			reason,
+			source.ID,
+			e.UID,
+		).Scan(&b.ID, &b.CheckIn, &b.CheckOut, &b.Reason)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				continue
+			}
+			return nil, errorspkg.NewErrRepoFailed("QueryRow Upsert Blackout", method, err)
+		}
+		changed = append(changed, b)
+	}
+
+	_, err = tx.Exec(ctx, `
+		DELETE FROM blackouts
+		WHERE source_id = $1 AND NOT (external_uid = ANY($2))
+	`, source.ID, uids)
+	if err != nil {
+		return nil, errorspkg.NewErrRepoFailed("Exec Delete Blackouts", method, err)
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE calendar_sources SET last_synced_at = now() WHERE id = $1`, source.ID)
+	if err != nil {
+		return nil, errorspkg.NewErrRepoFailed("Exec Update Source", method, err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, errorspkg.NewErrRepoFailed("Commit", method, err)
+	}
+
+	return changed, nil
+}
+
+// AddBlackout creates a manual blackout; an overlap with another manual one is reported
+// as errorspkg.ErrHouseUnavailable.
+func (r *CalendarRepo) AddBlackout(ctx context.Context, blackout entities.Blackout) (int, error) {
+	const method = "calendarRepo.AddBlackout"
+
+	query := `
+		INSERT INTO blackouts (house_id, period, reason)
+		VALUES ($1, daterange($2::date, $3::date), $4)
+		RETURNING id
+	`
+
+	var id int
+	err := r.pool.QueryRow(ctx, query,
+		blackout.HouseID,
+		blackout.CheckIn.Format(time.DateOnly),
+		blackout.CheckOut.Format(time.DateOnly),
+		blackout.Reason,
+	).Scan(&id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == exclusionViolation {
+			return 0, errorspkg.NewErrHouseUnavailable(blackout.HouseID, blackout.CheckIn, blackout.CheckOut)
+		}
+		return 0, errorspkg.NewErrRepoFailed("QueryRow", method, err)
+	}
+
+	return id, nil
+}
+
+func (r *CalendarRepo) GetOverlappingReservations(ctx context.Context, houseID int, checkIn, checkOut time.Time) ([]entities.ReservationConflict, error) {
+	const method = "calendarRepo.GetOverlappingReservations"
+
+	query := `
+		SELECT
+			r.uuid,
+			LOWER(r.stay),
+			UPPER(r.stay),
+			COALESCE(g.name, e.title, ''),
+			COALESCE(g.phone, e.contact_phone, '')
+		FROM reservations r
+		LEFT JOIN guests g ON r.guest_uuid = g.uuid
+		LEFT JOIN event_bookings e ON r.event_uuid = e.uuid
+		WHERE r.house_id = $1
+			AND r.stay && daterange($2::date, $3::date)
+			AND r.status NOT IN ('cancelled', 'checked_out')
+		ORDER BY LOWER(r.stay)
+	`
+
+	rows, err := r.pool.Query(ctx, query, houseID, checkIn.Format(time.DateOnly), checkOut.Format(time.DateOnly))
+	if err != nil {
+		return nil, errorspkg.NewErrRepoFailed("Query", method, err)
+	}
+	defer rows.Close()
+
+	var result []entities.ReservationConflict
+	for rows.Next() {
+		var c entities.ReservationConflict
+		if err = rows.Scan(
+			&c.UUID,
+			&c.CheckIn,
+			&c.CheckOut,
+			&c.GuestName,
+			&c.GuestPhone,
+		); err != nil {
+			return nil, errorspkg.NewErrRepoFailed("Scan", method, err)
+		}
+		result = append(result, c)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errorspkg.NewErrRepoFailed("rows.Err", method, err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,462 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"slices"
+	"strconv"
+	"time"
+)
+
+const applicationColumns = `id, name, phone, check_in, guests_count, status, notes, manager, created_at, updated_at`
+
+const eventBookingColumns = `
+	e.uuid,
+	e.application_id,
+	e.title,
+	e.contact_name,
+	e.contact_phone,
+	LOWER(e.stay),
+	UPPER(e.stay),
+	e.guests_count,
+	e.house_ids,
+	ARRAY(SELECT h.name FROM houses h WHERE h.id = ANY(e.house_ids) ORDER BY h.id),
+	e.bathhouse_ids,
+	ARRAY(SELECT b.name FROM bathhouses b WHERE b.id = ANY(e.bathhouse_ids) ORDER BY b.id),
+	e.total_price,
+	e.deposit,
+	e.status,
+	e.notes,
+	e.created_at
+`
+
+type EventApplicationsRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewEventApplicationsRepo(pool *pgxpool.Pool) *EventApplicationsRepo {
+	return &EventApplicationsRepo{pool: pool}
+}
+
+func (r *EventApplicationsRepo) Create(
+	ctx context.Context,
+	req entities.NewApplication,
+) (entities.EventApplication, error) {
+	const method = "eventApplicationsRepo.Create"
+
+	query := `
+		INSERT INTO event_applications (name, phone, check_in, guests_count)
+		VALUES ($1, $2, $3, $4)
+		RETURNING ` + applicationColumns
+
+	app, err := scanApplication(r.pool.QueryRow(ctx, query, req.Name, req.Phone, req.CheckIn, req.GuestsCount))
+	if err != nil {
+		return app, errorspkg.NewErrRepoFailed("QueryRow", method, err)
+	}
+
+	return app, nil
+}
+
+func (r *EventApplicationsRepo) Get(ctx context.Context, id int) (entities.EventApplication, error) {
+	const method = "eventApplicationsRepo.Get"
+
+	query := `SELECT ` + applicationColumns + ` FROM event_applications WHERE id = $1`
+
+	app, err := scanApplication(r.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return app, errorspkg.NewErrRepoNotFound("event application", strconv.Itoa(id), method)
+		}
+		return app, errorspkg.NewErrRepoFailed("QueryRow", method, err)
+	}
+
+	return app, nil
+}
+
+// GetAll returns the newest applications first, an empty status means all of them.
+func (r *EventApplicationsRepo) GetAll(
+	ctx context.Context,
+	status entities.ApplicationStatus,
+) ([]entities.EventApplication, error) {
+	const method = "eventApplicationsRepo.GetAll"
+
+	query := `
+		SELECT ` + applicationColumns + `
+		FROM event_applications
+		WHERE $1 = '' OR status = $1
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.pool.Query(ctx, query, status)
+	if err != nil {
+		return nil, errorspkg.NewErrRepoFailed("Query", method, err)
+	}
+	defer rows.Close()
+
+	var result []entities.EventApplication
+	for rows.Next() {
+		app, scanErr := scanApplication(rows)
+		if scanErr != nil {
+			return nil, errorspkg.NewErrRepoFailed("Scan", method, scanErr)
+		}
+		result = append(result, app)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errorspkg.NewErrRepoFailed("rows.Err", method, err)
+	}
+
+	return result, nil
+}
+
+func (r *EventApplicationsRepo) Update(
+	ctx context.Context,
+	id int,
+	upd entities.EventApplicationUpdate,
+) (entities.EventApplication, error) {
+	const method = "eventApplicationsRepo.Update"
+
+	query := `
+		UPDATE event_applications
+		SET
+			status     = COALESCE($2, status),
+			notes      = COALESCE($3, notes),
+			manager    = COALESCE($4, manager),
+			updated_at = now()
+		WHERE id = $1
+		RETURNING ` + applicationColumns
+
+	app, err := scanApplication(r.pool.QueryRow(ctx, query, id, upd.Status, upd.Notes, upd.Manager))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return app, errorspkg.NewErrRepoNotFound("event application", strconv.Itoa(id), method)
+		}
+		return app, errorspkg.NewErrRepoFailed("QueryRow", method, err)
+	}
+
+	return app, nil
+}
+
+func scanApplication(row pgx.Row) (entities.EventApplication, error) {
+	var app entities.EventApplication
+	err := row.Scan(
+		&app.ID,
+		&app.Name,
+		&app.Phone,
+		&app.CheckIn,
+		&app.GuestsCount,
+		&app.Status,
+		&app.Notes,
+		&app.Manager,
+		&app.CreatedAt,
+		&app.UpdatedAt,
+	)
+	return app, err
+}
+
+type EventBookingsRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewEventBookingsRepo(pool *pgxpool.Pool) *EventBookingsRepo {
+	return &EventBookingsRepo{pool: pool}
+}
+
+// Create reserves every house of the event and its bathhouses for whole days in one
+// transaction. Houses are checked against the same rows no_overlap would reject, so the
+// error names the busy house; the constraint itself still guards against races.
+func (r *EventBookingsRepo) Create(ctx context.Context, booking entities.EventBooking) (uuid.UUID, error) {
+	const method = "eventBookingsRepo.Create"
+
+	checkIn := booking.CheckIn.Format(time.DateOnly)
+	checkOut := booking.CheckOut.Format(time.DateOnly)
+	unavailable := func(houseID int) error {
+		return errorspkg.NewErrHouseUnavailable(houseID, booking.CheckIn, booking.CheckOut)
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return uuid.Nil, errorspkg.NewErrRepoFailed("BeginTx", method, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var known int
+	err = tx.QueryRow(ctx, `SELECT COUNT(*) FROM houses WHERE id = ANY($1)`, booking.HouseIDs).Scan(&known)
+	if err != nil {
+		return uuid.Nil, errorspkg.NewErrRepoFailed("QueryRow Houses", method, err)
+	}
+	if known != len(booking.HouseIDs) {
+		return uuid.Nil, errorspkg.ErrUnknownHouse
+	}
+
+	if len(booking.BathhouseIDs) > 0 {
+		rows, queryErr := tx.Query(ctx, `
+			SELECT id, house_id FROM bathhouses
+			WHERE id = ANY($1)
+			FOR UPDATE
+		`, booking.BathhouseIDs)
+		if queryErr != nil {
+			return uuid.Nil, errorspkg.NewErrRepoFailed("Query Bathhouses", method, queryErr)
+		}
+		found := 0
+		for rows.Next() {
+			var id, houseID int
+			if err = rows.Scan(&id, &houseID); err != nil {
+				rows.Close()
+				return uuid.Nil, errorspkg.NewErrRepoFailed("Scan Bathhouses", method, err)
+			}
+			if !slices.Contains(booking.HouseIDs, houseID) {
+				rows.Close()
+				return uuid.Nil, errorspkg.ErrEventBathhouse
+			}
+			found++
+		}
+		rows.Close()
+		if err = rows.Err(); err != nil {
+			return uuid.Nil, errorspkg.NewErrRepoFailed("rows.Err Bathhouses", method, err)
+		}
+		if found != len(booking.BathhouseIDs) {
+			return uuid.Nil, errorspkg.ErrEventBathhouse
+		}
+
+		var busy bool
+		err = tx.QueryRow(ctx, `
+			SELECT EXISTS (
+				SELECT 1 FROM bathhouse_reservations br
+				JOIN reservations r ON br.reservation_uuid = r.uuid
+				WHERE br.bathhouse_id = ANY($1)
+					AND br.date >= $2::date AND br.date < $3::date
+					AND r.status <> 'cancelled'
+			)
+		`, booking.BathhouseIDs, checkIn, checkOut).Scan(&busy)
+		if err != nil {
+			return uuid.Nil, errorspkg.NewErrRepoFailed("QueryRow Bathhouse Sessions", method, err)
+		}
+		if busy {
+			return uuid.Nil, errorspkg.ErrBathhouseUnavailable
+		}
+	}
+
+	var busyHouse int
+	err = tx.QueryRow(ctx, `
+		SELECT house_id FROM reservations
+		WHERE house_id = ANY($1) AND stay && daterange($2::date, $3::date)
+		UNION ALL
+		SELECT house_id FROM blackouts
+		WHERE house_id = ANY($1) AND period && daterange($2::date, $3::date)
+		LIMIT 1
+	`, booking.HouseIDs, checkIn, checkOut).Scan(&busyHouse)
+	switch {
+	case err == nil:
+		return uuid.Nil, unavailable(busyHouse)
+	case !errors.Is(err, pgx.ErrNoRows):
+		return uuid.Nil, errorspkg.NewErrRepoFailed("QueryRow Busy Houses", method, err)
+	}
+
+	if booking.ApplicationID != nil {
+		tag, execErr := tx.Exec(ctx, `
+			UPDATE event_applications
+			SET status = $2, updated_at = now()
+			WHERE id = $1
+		`, *booking.ApplicationID, entities.ApplicationWon)
+		if execErr != nil {
+			return uuid.Nil, errorspkg.NewErrRepoFailed("Exec Update Application", method, execErr)
+		}
+		if tag.RowsAffected() == 0 {
+			return uuid.Nil, errorspkg.NewErrRepoNotFound("event application", strconv.Itoa(*booking.ApplicationID), method)
+		}
+	}
+
+	bathhouseIDs := booking.BathhouseIDs
+	if bathhouseIDs == nil {
+		bathhouseIDs = []int{}
+	}
+
+	bookingUUID := uuid.New()
+	_, err = tx.Exec(ctx, `
+		INSERT INTO event_bookings (
+			uuid, application_id, title, contact_name, contact_phone, stay, guests_count,
+			house_ids, bathhouse_ids, total_price, deposit, status, notes
+		) VALUES (
+			$1, $2, $3, $4, $5, daterange($6::date, $7::date), $8, $9, $10, $11, $12, $13, $14
+		)
+	`,
+		bookingUUID,
+		booking.ApplicationID,
+		booking.Title,
+		booking.ContactName,
+		booking.ContactPhone,
+		checkIn,
+		checkOut,
+		booking.GuestsCount,
+		booking.HouseIDs,
+		bathhouseIDs,
+		booking.TotalPrice,
+		booking.Deposit,
+		entities.EventConfirmed,
+		booking.Notes,
+	)
+	if err != nil {
+		return uuid.Nil, errorspkg.NewErrRepoFailed("Exec Insert Event", method, err)
+	}
+
+	// The house is taken as a whole, its capacity stands in for the guests count.
+	_, err = tx.Exec(ctx, `
+		INSERT INTO reservations (uuid, house_id, stay, guests_count, status, total_price, event_uuid)
+		SELECT gen_random_uuid(), h.id, daterange($2::date, $3::date), h.capacity, 'confirmed', 0, $1
+		FROM houses h
+		WHERE h.id = ANY($4)
+	`, bookingUUID, checkIn, checkOut, booking.HouseIDs)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == exclusionViolation {
+			return uuid.Nil, unavailable(booking.HouseIDs[0])
+		}
+		return uuid.Nil, errorspkg.NewErrRepoFailed("Exec Insert Reservations", method, err)
+	}
+
+	if len(booking.BathhouseIDs) > 0 {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO bathhouse_reservations (reservation_uuid, bathhouse_id, date, time_from, time_to)
+			SELECT r.uuid, b.id, d::date, '00:00'::time, '24:00'::time
+			FROM bathhouses b
+			JOIN reservations r ON r.house_id = b.house_id AND r.event_uuid = $1
+			CROSS JOIN generate_series($3::date, $4::date - 1, interval '1 day') d
+			WHERE b.id = ANY($2)
+		`, bookingUUID, booking.BathhouseIDs, checkIn, checkOut)
+		if err != nil {
+			return uuid.Nil, errorspkg.NewErrRepoFailed("Exec Insert Bathhouses", method, err)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return uuid.Nil, errorspkg.NewErrRepoFailed("Commit", method, err)
+	}
+
+	return bookingUUID, nil
+}
+
+func (r *EventBookingsRepo) Get(ctx context.Context, bookingUUID uuid.UUID) (entities.EventBooking, error) {
+	const method = "eventBookingsRepo.Get"
+
+	query := `SELECT ` + eventBookingColumns + ` FROM event_bookings e WHERE e.uuid = $1`
+
+	booking, err := scanEventBooking(r.pool.QueryRow(ctx, query, bookingUUID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return booking, errorspkg.NewErrRepoNotFound("event booking", bookingUUID.String(), method)
+		}
+		return booking, errorspkg.NewErrRepoFailed("QueryRow", method, err)
+	}
+
+	return booking, nil
+}
+
+// GetAll returns events overlapping [from, to), a zero to leaves the range open.
+func (r *EventBookingsRepo) GetAll(ctx context.Context, from, to time.Time) ([]entities.EventBooking, error) {
+	const method = "eventBookingsRepo.GetAll"
+
+	query := `
+		SELECT ` + eventBookingColumns + `
+		FROM event_bookings e
+		WHERE e.stay && daterange($1::date, $2::date)
+		ORDER BY LOWER(e.stay), e.created_at
+	`
+
+	var upper any
+	if !to.IsZero() {
+		upper = to.Format(time.DateOnly)
+	}
+
+	return queryEventBookings(ctx, r.pool, method, query, from.Format(time.DateOnly), upper)
+}
+
+// Cancel frees the houses by deleting their reservations instead of cancelling them:
+// no_overlap covers cancelled rows as well and would keep the dates blocked.
+func (r *EventBookingsRepo) Cancel(ctx context.Context, bookingUUID uuid.UUID) error {
+	const method = "eventBookingsRepo.Cancel"
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return errorspkg.NewErrRepoFailed("BeginTx", method, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE event_bookings
+		SET status = $2, updated_at = now()
+		WHERE uuid = $1
+	`, bookingUUID, entities.EventCancelled)
+	if err != nil {
+		return errorspkg.NewErrRepoFailed("Exec Update Event", method, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errorspkg.NewErrRepoNotFound("event booking", bookingUUID.String(), method)
+	}
+
+	if _, err = tx.Exec(ctx, `DELETE FROM reservations WHERE event_uuid = $1`, bookingUUID); err != nil {
+		return errorspkg.NewErrRepoFailed("Exec Delete Reservations", method, err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return errorspkg.NewErrRepoFailed("Commit", method, err)
+	}
+
+	return nil
+}
+
+func queryEventBookings(
+	ctx context.Context,
+	pool *pgxpool.Pool,
+	method, query string,
+	args ...any,
+) ([]entities.EventBooking, error) {
+	rows, err := pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, errorspkg.NewErrRepoFailed("Query", method, err)
+	}
+	defer rows.Close()
+
+	var result []entities.EventBooking
+	for rows.Next() {
+		booking, scanErr := scanEventBooking(rows)
+		if scanErr != nil {
+			return nil, errorspkg.NewErrRepoFailed("Scan", method, scanErr)
+		}
+		result = append(result, booking)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errorspkg.NewErrRepoFailed("rows.Err", method, err)
+	}
+
+	return result, nil
+}
+
+func scanEventBooking(row pgx.Row) (entities.EventBooking, error) {
+	var booking entities.EventBooking
+	err := row.Scan(
+		&booking.UUID,
+		&booking.ApplicationID,
+		&booking.Title,
+		&booking.ContactName,
+		&booking.ContactPhone,
+		&booking.CheckIn,
+		&booking.CheckOut,
+		&booking.GuestsCount,
+		&booking.HouseIDs,
+		&booking.HouseNames,
+		&booking.BathhouseIDs,
+		&booking.BathhouseNames,
+		&booking.TotalPrice,
+		&booking.Deposit,
+		&booking.Status,
+		&booking.Notes,
+		&booking.CreatedAt,
+	)
+	return booking, err
+}
@@ -110,7 +110,10 @@ func (r *ExtrasRepo) Update(ctx context.Context, extra entities.Extra) error {
 		    name        = $1,
 		    description = $2,
 		    price       = $3,
-		    images      = $4,
+		    images      = CASE
+		        WHEN EXISTS (SELECT 1 FROM media WHERE owner_type = 'extra' AND owner_id = extras.id) THEN images
+		        ELSE $4
+		    END,
 		    short_text   = $5,
 		    updated_at  = now()
 		WHERE id = $6
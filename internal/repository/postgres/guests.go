@@ -10,6 +10,8 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"strconv"
+	"time"
 )
 
 type GuestsRepo struct {
@@ -20,51 +22,479 @@ func NewGuestsRepo(pool *pgxpool.Pool) *GuestsRepo {
 	return &GuestsRepo{pool: pool}
 }
 
-func (r *GuestsRepo) Create(ctx context.Context, guest entities.Guest) error {
-	const method = "guestsRepo.Create"
+const guestColumns = `uuid, name, email, coalesce(phone, ''), coalesce(tg_user_id, 0), locale`
+
+// Find looks the guest up by phone and, when nothing matches it, by email.
+func (r *GuestsRepo) Find(ctx context.Context, phone, email string) (repository.Guest, error) {
+	const method = "guestsRepo.Find"
 
 	query := `
-		INSERT INTO guests (uuid, name, email, phone, tg_user_id)
-		VALUES ($1, $2, $3, $4, $5)
+		SELECT ` + guestColumns + `
+		FROM guests
+		WHERE phone = $1 OR ($2 <> '' AND email = $2)
+		ORDER BY phone = $1 DESC, created_at DESC
+		LIMIT 1
 	`
 
-	_, err := r.pool.Exec(ctx, query,
-		uuid.New(),
-		guest.Name,
-		guest.Email,
-		guest.Phone,
-		guest.TgID,
+	guest, err := scanGuest(r.pool.QueryRow(ctx, query, phone, email))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return guest, errorspkg.NewErrRepoNotFound("guest", fmt.Sprintf("%s %s", phone, email), method)
+		}
+		return guest, errorspkg.NewErrRepoFailed("QueryRow", method, err)
+	}
+
+	return guest, nil
+}
+
+func (r *GuestsRepo) GetByTgID(ctx context.Context, tgID int64) (repository.Guest, error) {
+	const method = "guestsRepo.GetByTgID"
+
+	query := `SELECT ` + guestColumns + ` FROM guests WHERE tg_user_id = $1`
+
+	guest, err := scanGuest(r.pool.QueryRow(ctx, query, tgID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return guest, errorspkg.NewErrRepoNotFound("guest", strconv.FormatInt(tgID, 10), method)
+		}
+		return guest, errorspkg.NewErrRepoFailed("QueryRow", method, err)
+	}
+
+	return guest, nil
+}
+
+// Search matches the query against names and emails, and against phones when it has
+// at least five digits.
+func (r *GuestsRepo) Search(ctx context.Context, query string, limit int) ([]entities.GuestProfile, error) {
+	const method = "guestsRepo.Search"
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+guestColumns+`, created_at
+		FROM guests
+		WHERE name ILIKE '%' || $1 || '%'
+		   OR email ILIKE '%' || $1 || '%'
+		   OR (length(regexp_replace($1, '\D', '', 'g')) >= 5
+		       AND phone LIKE '%' || regexp_replace($1, '\D', '', 'g') || '%')
+		ORDER BY created_at DESC
+		LIMIT $2
+	`, query, limit)
+	if err != nil {
+		return nil, errorspkg.NewErrRepoFailed("Query", method, err)
+	}
+	defer rows.Close()
+
+	res := make([]entities.GuestProfile, 0)
+	for rows.Next() {
+		var g entities.GuestProfile
+		if err = rows.Scan(&g.UUID, &g.Name, &g.Email, &g.Phone, &g.TgID, &g.Locale, &g.CreatedAt); err != nil {
+			return nil, errorspkg.NewErrRepoFailed("Scan", method, err)
+		}
+		res = append(res, g)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errorspkg.NewErrRepoFailed("Rows", method, err)
+	}
+
+	return res, nil
+}
+
+// Merge moves reservations, reviews and delivery history of the source guest to the
+// target, fills the target's empty contacts from the source and deletes the source.
+func (r *GuestsRepo) Merge(ctx context.Context, targetUUID, sourceUUID uuid.UUID) (entities.GuestMerge, error) {
+	const method = "guestsRepo.Merge"
+
+	var res entities.GuestMerge
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return res, errorspkg.NewErrRepoFailed("Begin", method, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// Both rows are locked, source ends up holding the guest being merged away.
+	var source repository.Guest
+	for _, id := range []uuid.UUID{targetUUID, sourceUUID} {
+		source, err = scanGuest(tx.QueryRow(ctx, `SELECT `+guestColumns+` FROM guests WHERE uuid = $1 FOR UPDATE`, id))
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return res, errorspkg.NewErrRepoNotFound("guest", id.String(), method)
+			}
+			return res, errorspkg.NewErrRepoFailed("QueryRow Lock", method, err)
+		}
+	}
+
+	moves := []struct {
+		table string
+		count *int64
+	}{
+		{"reservations", &res.Reservations},
+		{"reviews", &res.Reviews},
+		{"notification_deliveries", &res.Notifications},
+	}
+	for _, m := range moves {
+		tag, err := tx.Exec(ctx, `UPDATE `+m.table+` SET guest_uuid = $1 WHERE guest_uuid = $2`, targetUUID, sourceUUID)
+		if err != nil {
+			return res, errorspkg.NewErrRepoFailed("Exec Move "+m.table, method, err)
+		}
+		*m.count = tag.RowsAffected()
+	}
+
+	if _, err = tx.Exec(ctx, `DELETE FROM guests WHERE uuid = $1`, sourceUUID); err != nil {
+		return res, errorspkg.NewErrRepoFailed("Exec Delete", method, err)
+	}
+
+	err = tx.QueryRow(ctx, `
+		UPDATE guests
+		SET email = coalesce(nullif(email, ''), $2),
+			phone = coalesce(phone, nullif($3, '')),
+			tg_user_id = coalesce(tg_user_id, nullif($4, 0)),
+			locale = coalesce(nullif(locale, ''), $5)
+		WHERE uuid = $1
+		RETURNING `+guestColumns+`, created_at
+	`, targetUUID, source.Email, source.Phone, source.TgId, source.Locale).Scan(
+		&res.Guest.UUID,
+		&res.Guest.Name,
+		&res.Guest.Email,
+		&res.Guest.Phone,
+		&res.Guest.TgID,
+		&res.Guest.Locale,
+		&res.Guest.CreatedAt,
+	)
+	if err != nil {
+		return res, errorspkg.NewErrRepoFailed("QueryRow Update", method, err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return res, errorspkg.NewErrRepoFailed("Commit", method, err)
+	}
+	return res, nil
+}
+
+// ExportData reads the guest and everything linked to it in one snapshot. Verifications
+// aren't linked to guests, they are matched by the guest's phone and email.
+func (r *GuestsRepo) ExportData(ctx context.Context, guestUUID uuid.UUID) (entities.GuestData, error) {
+	const method = "guestsRepo.ExportData"
+
+	var data entities.GuestData
+
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return data, errorspkg.NewErrRepoFailed("Begin", method, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var channels []string
+	p := &data.Profile
+	err = tx.QueryRow(ctx, `SELECT `+guestColumns+`, created_at, notify_channels FROM guests WHERE uuid = $1`, guestUUID).Scan(
+		&p.UUID, &p.Name, &p.Email, &p.Phone, &p.TgID, &p.Locale, &p.CreatedAt, &channels,
 	)
 	if err != nil {
-		return errorspkg.NewErrRepoFailed("Exec", method, err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return data, errorspkg.NewErrRepoNotFound("guest", guestUUID.String(), method)
+		}
+		return data, errorspkg.NewErrRepoFailed("QueryRow Guest", method, err)
+	}
+	data.NotifyChannels = stringsToChannels(channels)
+
+	rows, err := tx.Query(ctx, `
+		SELECT r.uuid, h.name, LOWER(r.stay), UPPER(r.stay), r.guests_count, r.status::text, r.total_price, r.created_at
+		FROM reservations r
+		JOIN houses h ON h.id = r.house_id
+		WHERE r.guest_uuid = $1
+		ORDER BY LOWER(r.stay)
+	`, guestUUID)
+	if err != nil {
+		return data, errorspkg.NewErrRepoFailed("Query Reservations", method, err)
+	}
+	for rows.Next() {
+		var res entities.GuestDataReservation
+		err = rows.Scan(&res.UUID, &res.HouseName, &res.CheckIn, &res.CheckOut, &res.GuestsCount, &res.Status, &res.TotalPrice, &res.CreatedAt)
+		if err != nil {
+			rows.Close()
+			return data, errorspkg.NewErrRepoFailed("Scan Reservations", method, err)
+		}
+		data.Reservations = append(data.Reservations, res)
+	}
+	if err = rows.Err(); err != nil {
+		return data, errorspkg.NewErrRepoFailed("Rows Reservations", method, err)
+	}
+
+	rows, err = tx.Query(ctx, `
+		SELECT reservation_uuid, rating, comment, status, created_at
+		FROM reviews
+		WHERE guest_uuid = $1
+		ORDER BY created_at
+	`, guestUUID)
+	if err != nil {
+		return data, errorspkg.NewErrRepoFailed("Query Reviews", method, err)
+	}
+	for rows.Next() {
+		var review entities.GuestDataReview
+		if err = rows.Scan(&review.ReservationUUID, &review.Rating, &review.Comment, &review.Status, &review.CreatedAt); err != nil {
+			rows.Close()
+			return data, errorspkg.NewErrRepoFailed("Scan Reviews", method, err)
+		}
+		data.Reviews = append(data.Reviews, review)
+	}
+	if err = rows.Err(); err != nil {
+		return data, errorspkg.NewErrRepoFailed("Rows Reviews", method, err)
+	}
+
+	rows, err = tx.Query(ctx, `
+		SELECT kind, channel, recipient, status, created_at
+		FROM notification_deliveries
+		WHERE guest_uuid = $1
+		ORDER BY created_at
+	`, guestUUID)
+	if err != nil {
+		return data, errorspkg.NewErrRepoFailed("Query Notifications", method, err)
+	}
+	for rows.Next() {
+		var n entities.GuestDataNotification
+		if err = rows.Scan(&n.Kind, &n.Channel, &n.Recipient, &n.Status, &n.CreatedAt); err != nil {
+			rows.Close()
+			return data, errorspkg.NewErrRepoFailed("Scan Notifications", method, err)
+		}
+		data.Notifications = append(data.Notifications, n)
+	}
+	if err = rows.Err(); err != nil {
+		return data, errorspkg.NewErrRepoFailed("Rows Notifications", method, err)
+	}
+
+	rows, err = tx.Query(ctx, `
+		SELECT name, email, phone, status, COALESCE(created_at, expires_at), verified_at
+		FROM verifications
+		WHERE phone = $1 OR ($2 <> '' AND email = $2)
+		ORDER BY created_at
+	`, p.Phone, p.Email)
+	if err != nil {
+		return data, errorspkg.NewErrRepoFailed("Query Verifications", method, err)
+	}
+	for rows.Next() {
+		var v entities.GuestDataVerification
+		if err = rows.Scan(&v.Name, &v.Email, &v.Phone, &v.Status, &v.CreatedAt, &v.VerifiedAt); err != nil {
+			rows.Close()
+			return data, errorspkg.NewErrRepoFailed("Scan Verifications", method, err)
+		}
+		data.Verifications = append(data.Verifications, v)
+	}
+	if err = rows.Err(); err != nil {
+		return data, errorspkg.NewErrRepoFailed("Rows Verifications", method, err)
+	}
+
+	return data, nil
+}
+
+// Anonymize wipes the guest's personal data and everything that repeats it, reservations
+// keep their dates and prices. Guests with upcoming or ongoing stays are refused.
+func (r *GuestsRepo) Anonymize(ctx context.Context, guestUUID uuid.UUID) error {
+	const method = "guestsRepo.Anonymize"
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return errorspkg.NewErrRepoFailed("Begin", method, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	guest, err := scanGuest(tx.QueryRow(ctx, `SELECT `+guestColumns+` FROM guests WHERE uuid = $1 FOR UPDATE`, guestUUID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errorspkg.NewErrRepoNotFound("guest", guestUUID.String(), method)
+		}
+		return errorspkg.NewErrRepoFailed("QueryRow Lock", method, err)
+	}
+
+	var active bool
+	err = tx.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM reservations
+			WHERE guest_uuid = $1
+			  AND status IN ('pending', 'confirmed', 'checked_in')
+			  AND UPPER(stay) >= CURRENT_DATE
+		)
+	`, guestUUID).Scan(&active)
+	if err != nil {
+		return errorspkg.NewErrRepoFailed("QueryRow Active", method, err)
+	}
+	if active {
+		return errorspkg.ErrGuestHasActiveStays
+	}
+
+	type step struct {
+		name  string
+		query string
+		args  []any
+	}
+	steps := []step{
+		{"Guest", `
+			UPDATE guests
+			SET name = '', email = '', phone = NULL, tg_user_id = NULL,
+				notify_channels = '{}', locale = '', anonymized_at = NOW()
+			WHERE uuid = $1
+		`, []any{guestUUID}},
+		{"Reviews", `UPDATE reviews SET comment = '', awaiting_comment = false WHERE guest_uuid = $1`, []any{guestUUID}},
+		{"Notifications", `UPDATE notification_deliveries SET recipient = '' WHERE guest_uuid = $1`, []any{guestUUID}},
+		{"Outbox", `
+			DELETE FROM notification_outbox
+			WHERE status <> 'pending'
+			  AND (payload->>'GuestUUID' = $1 OR payload->'Message'->>'GuestUUID' = $1)
+		`, []any{guestUUID.String()}},
+		{"Verifications", `DELETE FROM verifications WHERE phone = $1 OR ($2 <> '' AND email = $2)`, []any{guest.Phone, guest.Email}},
+	}
+	if guest.TgId != 0 {
+		steps = append(steps,
+			step{"Booking", `DELETE FROM booking_sessions WHERE chat_id = $1`, []any{guest.TgId}},
+			step{"Lockouts", `DELETE FROM verification_lockouts WHERE tg_user_id = $1`, []any{guest.TgId}},
+		)
+	}
+	for _, st := range steps {
+		if _, err = tx.Exec(ctx, st.query, st.args...); err != nil {
+			return errorspkg.NewErrRepoFailed("Exec "+st.name, method, err)
+		}
 	}
 
+	if err = tx.Commit(ctx); err != nil {
+		return errorspkg.NewErrRepoFailed("Commit", method, err)
+	}
 	return nil
 }
 
-func (r *GuestsRepo) Get(ctx context.Context, req entities.Guest) (repository.Guest, error) {
-	const method = "guestsRepo.Get"
+// GetInactive returns guests who registered and last stayed before the given time.
+func (r *GuestsRepo) GetInactive(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, error) {
+	const method = "guestsRepo.GetInactive"
 
-	query := `
-		SELECT uuid, name, email, phone, tg_user_id 
-		FROM guests 
-		WHERE email = $1 AND phone = $2 AND name = $3
-	`
+	rows, err := r.pool.Query(ctx, `
+		SELECT g.uuid
+		FROM guests g
+		WHERE g.anonymized_at IS NULL
+		  AND g.created_at < $1
+		  AND NOT EXISTS (
+				SELECT 1 FROM reservations r
+				WHERE r.guest_uuid = g.uuid
+				  AND (UPPER(r.stay) >= $1::date OR r.created_at >= $1)
+			)
+		ORDER BY g.created_at
+		LIMIT $2
+	`, before, limit)
+	if err != nil {
+		return nil, errorspkg.NewErrRepoFailed("Query", method, err)
+	}
+	defer rows.Close()
+
+	res := make([]uuid.UUID, 0)
+	for rows.Next() {
+		var id uuid.UUID
+		if err = rows.Scan(&id); err != nil {
+			return nil, errorspkg.NewErrRepoFailed("Scan", method, err)
+		}
+		res = append(res, id)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errorspkg.NewErrRepoFailed("Rows", method, err)
+	}
 
+	return res, nil
+}
+
+func (r *GuestsRepo) GetNotifyChannels(ctx context.Context, guestUUID uuid.UUID) ([]entities.NotificationChannel, error) {
+	const method = "guestsRepo.GetNotifyChannels"
+
+	var channels []string
+	err := r.pool.QueryRow(ctx, `SELECT notify_channels FROM guests WHERE uuid = $1`, guestUUID).Scan(&channels)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errorspkg.NewErrRepoNotFound("guest", guestUUID.String(), method)
+		}
+		return nil, errorspkg.NewErrRepoFailed("QueryRow", method, err)
+	}
+
+	return stringsToChannels(channels), nil
+}
+
+// saveGuest stores the verified guest inside tx, so it is saved together with the approval
+// it comes from. The guest is matched by phone and keeps its name; the Telegram account is
+// bound only when the guest has none or already has this one. A phone bound to another
+// account, or an account that belongs to another guest, is left for an admin to merge and
+// reported as ErrGuestConflict.
+func saveGuest(ctx context.Context, tx pgx.Tx, guest entities.Guest) error {
+	var (
+		id   uuid.UUID
+		tgID int64
+	)
+	err := tx.QueryRow(ctx,
+		`SELECT uuid, coalesce(tg_user_id, 0) FROM guests WHERE phone = $1 FOR UPDATE`,
+		guest.Phone,
+	).Scan(&id, &tgID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("select by phone: %w", err)
+	}
+	if tgID != 0 && tgID != guest.TgID {
+		return errorspkg.ErrGuestConflict
+	}
+
+	var owner uuid.UUID
+	err = tx.QueryRow(ctx,
+		`SELECT uuid FROM guests WHERE tg_user_id = $1 AND uuid <> $2`,
+		guest.TgID, id,
+	).Scan(&owner)
+	switch {
+	case err == nil:
+		return errorspkg.ErrGuestConflict
+	case !errors.Is(err, pgx.ErrNoRows):
+		return fmt.Errorf("select by tg id: %w", err)
+	}
+
+	channels := channelsToStrings(guest.NotifyChannels)
+	if id == uuid.Nil {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO guests (uuid, name, email, phone, tg_user_id, notify_channels, locale)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`, uuid.New(), guest.Name, guest.Email, guest.Phone, guest.TgID, channels, guest.Locale)
+		if err != nil {
+			return fmt.Errorf("insert: %w", err)
+		}
+		return nil
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE guests
+		SET email = coalesce(nullif($3, ''), email),
+			tg_user_id = $2,
+			notify_channels = CASE WHEN cardinality($4::text[]) > 0 THEN $4 ELSE notify_channels END,
+			locale = coalesce(nullif($5, ''), locale)
+		WHERE uuid = $1
+	`, id, guest.TgID, guest.Email, channels, guest.Locale)
+	if err != nil {
+		return fmt.Errorf("update: %w", err)
+	}
+	return nil
+}
+
+func channelsToStrings(channels []entities.NotificationChannel) []string {
+	res := make([]string, 0, len(channels))
+	for _, c := range channels {
+		res = append(res, string(c))
+	}
+	return res
+}
+
+func stringsToChannels(channels []string) []entities.NotificationChannel {
+	res := make([]entities.NotificationChannel, 0, len(channels))
+	for _, c := range channels {
+		res = append(res, entities.NotificationChannel(c))
+	}
+	return res
+}
+
+func scanGuest(row pgx.Row) (repository.Guest, error) {
 	var guest repository.Guest
-	err := r.pool.QueryRow(ctx, query, req.Email, req.Phone, req.Name).Scan(
+	err := row.Scan(
 		&guest.UUID,
 		&guest.Name,
 		&guest.Email,
 		&guest.Phone,
 		&guest.TgId,
+		&guest.Locale,
 	)
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return guest, errorspkg.NewErrRepoNotFound("guest", fmt.Sprintf("%s %s %s", req.Name, req.Phone, req.Email), method)
-		}
-		return guest, errorspkg.NewErrRepoFailed("QueryRow", method, err)
-	}
-
-	return guest, nil
+	return guest, err
 }
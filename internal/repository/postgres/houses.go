@@ -10,6 +10,37 @@ import (
 	"strconv"
 )
 
+// houseColumns are read by scanHouse, unqualified so that queries may alias houses.
+const houseColumns = `
+	id,
+	name,
+	description,
+
+	capacity,
+	base_price,
+
+	COALESCE(NULLIF(cover_image, ''), images[1], ''),
+	images,
+
+	check_in_from,
+	check_out_until,
+	directions,
+
+	sauna,
+	pets_allowed,
+	kitchen,
+	fireplace,
+	beds,
+	area,
+	tags
+`
+
+// houseBed is the jsonb layout of a houses.beds element.
+type houseBed struct {
+	Kind  string `json:"kind"`
+	Count int    `json:"count"`
+}
+
 type HousesRepo struct {
 	pool *pgxpool.Pool
 }
@@ -20,45 +51,30 @@ func NewHousesRepo(pool *pgxpool.Pool) *HousesRepo {
 
 func (r *HousesRepo) GetAll(ctx context.Context) ([]entities.House, error) {
 	const method = "housesRepo.GetAll"
-	rows, err := r.pool.Query(ctx, `
-		SELECT 
-			id,
-			name,
-			description,
-			
-			capacity,
-			base_price,
-			
-			images,
-			
-			check_in_from,
-			check_out_until
-		FROM houses
-	`)
+	rows, err := r.pool.Query(ctx, `SELECT `+houseColumns+` FROM houses`)
 	if err != nil {
 		return nil, errorspkg.NewErrRepoFailed("pool.Query", method, err)
 	}
-	defer rows.Close()
 
-	var results []entities.House
-	for rows.Next() {
-		var house entities.House
-		if err = rows.Scan(
-			&house.ID,
-			&house.Name,
-			&house.Description,
-			&house.Capacity,
-			&house.BasePrice,
-			&house.Images,
-			&house.CheckInFrom,
-			&house.CheckOutUntil,
-		); err != nil {
-			return nil, errorspkg.NewErrRepoFailed("rows.Scan", method, err)
-		}
-		results = append(results, house)
+	results, err := collectHouses(rows)
+	if err != nil {
+		return nil, errorspkg.NewErrRepoFailed("collectHouses", method, err)
 	}
-	if err = rows.Err(); err != nil {
-		return nil, errorspkg.NewErrRepoFailed("rows.Err", method, err)
+
+	return results, nil
+}
+
+// GetByIDs returns the houses found among ids, ordered by id.
+func (r *HousesRepo) GetByIDs(ctx context.Context, ids []int) ([]entities.House, error) {
+	const method = "housesRepo.GetByIDs"
+	rows, err := r.pool.Query(ctx, `SELECT `+houseColumns+` FROM houses WHERE id = ANY($1) ORDER BY id`, ids)
+	if err != nil {
+		return nil, errorspkg.NewErrRepoFailed("pool.Query", method, err)
+	}
+
+	results, err := collectHouses(rows)
+	if err != nil {
+		return nil, errorspkg.NewErrRepoFailed("collectHouses", method, err)
 	}
 
 	return results, nil
@@ -67,36 +83,7 @@ func (r *HousesRepo) GetAll(ctx context.Context) ([]entities.House, error) {
 func (r *HousesRepo) GetOne(ctx context.Context, id int) (entities.House, error) {
 	const method = "housesRepo.GetOne"
 
-	query := `
-        SELECT 
-            id,
-            name,
-            description,
-            
-            capacity,
-            base_price,
-            
-            images,
-            
-            check_in_from,
-            check_out_until
-        FROM houses
-        WHERE id = $1
-    `
-
-	var house entities.House
-
-	err := r.pool.QueryRow(ctx, query, id).Scan(
-		&house.ID,
-		&house.Name,
-		&house.Description,
-		&house.Capacity,
-		&house.BasePrice,
-		&house.Images,
-		&house.CheckInFrom,
-		&house.CheckOutUntil,
-	)
-
+	house, err := scanHouse(r.pool.QueryRow(ctx, `SELECT `+houseColumns+` FROM houses WHERE id = $1`, id))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return entities.House{}, errorspkg.NewErrRepoNotFound("house", strconv.Itoa(id), method)
@@ -120,13 +107,25 @@ func (r *HousesRepo) Add(ctx context.Context, houses []entities.House) error {
 			base_price,
 			images,
 			check_in_from,
-			check_out_until
+			check_out_until,
+			directions,
+			cover_image,
+			sauna,
+			pets_allowed,
+			kitchen,
+			fireplace,
+			beds,
+			area,
+			tags
 		)
 		VALUES (
 			$1, $2, $3,
 			$4, $5,
 			$6,
-			$7, $8
+			$7, $8, $9,
+			$10,
+			$11, $12, $13, $14,
+			$15, $16, $17
 		)
 	`
 
@@ -140,6 +139,15 @@ func (r *HousesRepo) Add(ctx context.Context, houses []entities.House) error {
 			house.Images,
 			house.CheckInFrom,
 			house.CheckOutUntil,
+			house.Directions,
+			house.CoverImage,
+			house.Amenities.Sauna,
+			house.Amenities.PetsAllowed,
+			house.Amenities.Kitchen,
+			house.Amenities.Fireplace,
+			bedsFromEntities(house.Amenities.Beds),
+			house.Amenities.Area,
+			tagsOrEmpty(house.Tags),
 		)
 	}
 
@@ -164,11 +172,16 @@ func (r *HousesRepo) Update(ctx context.Context, house entities.House) error {
 			capacity        = $2,
 			base_price      = $3,
 			description     = $4,
-		    images          = $5,
+		    images          = CASE
+		        WHEN EXISTS (SELECT 1 FROM media WHERE owner_type = 'house' AND owner_id = houses.id) THEN images
+		        ELSE $5
+		    END,
 		    check_in_from   = $6,
 		    check_out_until = $7,
+		    directions      = $8,
+		    cover_image     = $10,
 		    updated_at      = now()
-		WHERE id = $8
+		WHERE id = $9
 	`
 
 	rows, err := r.pool.Exec(ctx, query,
@@ -179,7 +192,9 @@ func (r *HousesRepo) Update(ctx context.Context, house entities.House) error {
 		house.Images,
 		house.CheckInFrom,
 		house.CheckOutUntil,
+		house.Directions,
 		house.ID,
+		house.CoverImage,
 	)
 	if err != nil {
 		return errorspkg.NewErrRepoFailed("Exec", method, err)
@@ -205,3 +220,155 @@ func (r *HousesRepo) Delete(ctx context.Context, id int) error {
 	}
 	return nil
 }
+
+func (r *HousesRepo) UpdateAmenities(ctx context.Context, id int, amenities entities.HouseAmenities) error {
+	const method = "housesRepo.UpdateAmenities"
+	query := `
+		UPDATE houses
+		SET
+			sauna        = $1,
+			pets_allowed = $2,
+			kitchen      = $3,
+			fireplace    = $4,
+			beds         = $5,
+			area         = $6,
+			updated_at   = now()
+		WHERE id = $7
+	`
+
+	rows, err := r.pool.Exec(ctx, query,
+		amenities.Sauna,
+		amenities.PetsAllowed,
+		amenities.Kitchen,
+		amenities.Fireplace,
+		bedsFromEntities(amenities.Beds),
+		amenities.Area,
+		id,
+	)
+	if err != nil {
+		return errorspkg.NewErrRepoFailed("Exec", method, err)
+	}
+	if rows.RowsAffected() == 0 {
+		return errorspkg.NewErrRepoNotFound("house", strconv.Itoa(id), method)
+	}
+	return nil
+}
+
+func (r *HousesRepo) UpdateTags(ctx context.Context, id int, tags []string) error {
+	const method = "housesRepo.UpdateTags"
+	query := `
+		UPDATE houses
+		SET tags = $1, updated_at = now()
+		WHERE id = $2
+	`
+
+	rows, err := r.pool.Exec(ctx, query, tagsOrEmpty(tags), id)
+	if err != nil {
+		return errorspkg.NewErrRepoFailed("Exec", method, err)
+	}
+	if rows.RowsAffected() == 0 {
+		return errorspkg.NewErrRepoNotFound("house", strconv.Itoa(id), method)
+	}
+	return nil
+}
+
+// GetTags returns every tag in use with the number of houses carrying it, most used first.
+func (r *HousesRepo) GetTags(ctx context.Context) ([]entities.HouseTag, error) {
+	const method = "housesRepo.GetTags"
+	rows, err := r.pool.Query(ctx, `
+		SELECT tag, count(*)
+		FROM houses, unnest(tags) AS tag
+		GROUP BY tag
+		ORDER BY count(*) DESC, tag
+	`)
+	if err != nil {
+		return nil, errorspkg.NewErrRepoFailed("Query", method, err)
+	}
+	defer rows.Close()
+
+	results := make([]entities.HouseTag, 0)
+	for rows.Next() {
+		var tag entities.HouseTag
+		if err = rows.Scan(&tag.Name, &tag.Houses); err != nil {
+			return nil, errorspkg.NewErrRepoFailed("Scan", method, err)
+		}
+		results = append(results, tag)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errorspkg.NewErrRepoFailed("rows.Err", method, err)
+	}
+
+	return results, nil
+}
+
+func bedsToEntities(beds []houseBed) []entities.HouseBed {
+	res := make([]entities.HouseBed, 0, len(beds))
+	for _, b := range beds {
+		res = append(res, entities.HouseBed{Kind: entities.BedKind(b.Kind), Count: b.Count})
+	}
+	return res
+}
+
+func bedsFromEntities(beds []entities.HouseBed) []houseBed {
+	res := make([]houseBed, 0, len(beds))
+	for _, b := range beds {
+		res = append(res, houseBed{Kind: string(b.Kind), Count: b.Count})
+	}
+	return res
+}
+
+// tagsOrEmpty keeps a nil slice from being written as NULL into the NOT NULL column.
+func tagsOrEmpty(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
+
+func scanHouse(row pgx.Row) (entities.House, error) {
+	var (
+		house entities.House
+		beds  []houseBed
+	)
+	err := row.Scan(
+		&house.ID,
+		&house.Name,
+		&house.Description,
+		&house.Capacity,
+		&house.BasePrice,
+		&house.CoverImage,
+		&house.Images,
+		&house.CheckInFrom,
+		&house.CheckOutUntil,
+		&house.Directions,
+		&house.Amenities.Sauna,
+		&house.Amenities.PetsAllowed,
+		&house.Amenities.Kitchen,
+		&house.Amenities.Fireplace,
+		&beds,
+		&house.Amenities.Area,
+		&house.Tags,
+	)
+	if err != nil {
+		return house, err
+	}
+	house.Amenities.Beds = bedsToEntities(beds)
+
+	return house, nil
+}
+
+// collectHouses scans every row with scanHouse and closes rows.
+func collectHouses(rows pgx.Rows) ([]entities.House, error) {
+	defer rows.Close()
+
+	var results []entities.House
+	for rows.Next() {
+		house, err := scanHouse(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, house)
+	}
+
+	return results, rows.Err()
+}
@@ -0,0 +1,35 @@
+package postgres
+
+import (
+	"context"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type LoyaltyRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewLoyaltyRepo(pool *pgxpool.Pool) *LoyaltyRepo {
+	return &LoyaltyRepo{pool: pool}
+}
+
+// GetStats counts only checked out reservations, the price is the one the guest paid.
+func (r *LoyaltyRepo) GetStats(ctx context.Context, guestUUID uuid.UUID) (entities.LoyaltyStats, error) {
+	const method = "loyaltyRepo.GetStats"
+
+	query := `
+		SELECT COUNT(*), COALESCE(SUM(total_price), 0)::int
+		FROM reservations
+		WHERE guest_uuid = $1 AND status = 'checked_out'
+	`
+
+	var stats entities.LoyaltyStats
+	if err := r.pool.QueryRow(ctx, query, guestUUID).Scan(&stats.Stays, &stats.Spend); err != nil {
+		return stats, errorspkg.NewErrRepoFailed("QueryRow", method, err)
+	}
+
+	return stats, nil
+}
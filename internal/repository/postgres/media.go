@@ -0,0 +1,346 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"strconv"
+)
+
+const mediaColumns = `
+	id,
+	owner_type,
+	owner_id,
+	position,
+	content_type,
+	size,
+	width,
+	height,
+	storage_key,
+	url,
+	variants,
+	created_at
+`
+
+// mediaOwnerTables maps an owner to the table whose images column mirrors its media.
+var mediaOwnerTables = map[entities.MediaOwner]string{
+	entities.MediaHouse:     "houses",
+	entities.MediaExtra:     "extras",
+	entities.MediaBathhouse: "bathhouses",
+}
+
+// mediaVariant is the jsonb layout of a media.variants element.
+type mediaVariant struct {
+	Name        string `json:"name"`
+	Key         string `json:"key"`
+	URL         string `json:"url"`
+	ContentType string `json:"contentType"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+}
+
+type MediaRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewMediaRepo(pool *pgxpool.Pool) *MediaRepo {
+	return &MediaRepo{pool: pool}
+}
+
+// Create appends the media to the end of the owner's gallery.
+func (r *MediaRepo) Create(ctx context.Context, media entities.Media) (entities.Media, error) {
+	const method = "mediaRepo.Create"
+
+	table, ok := mediaOwnerTables[media.Owner]
+	if !ok {
+		return media, errorspkg.ErrUnknownMediaOwner
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return media, errorspkg.NewErrRepoFailed("Begin", method, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	exists, err := lockMediaOwner(ctx, tx, table, media.OwnerID)
+	if err != nil {
+		return media, errorspkg.NewErrRepoFailed("lock owner", method, err)
+	}
+	if !exists {
+		return media, errorspkg.NewErrRepoNotFound(string(media.Owner), strconv.Itoa(media.OwnerID), method)
+	}
+
+	err = tx.QueryRow(ctx, `
+		SELECT COALESCE(MAX(position), 0) + 1
+		FROM media
+		WHERE owner_type = $1 AND owner_id = $2
+	`, media.Owner, media.OwnerID).Scan(&media.Position)
+	if err != nil {
+		return media, errorspkg.NewErrRepoFailed("select position", method, err)
+	}
+
+	variants := make([]mediaVariant, 0, len(media.Variants))
+	for _, v := range media.Variants {
+		variants = append(variants, mediaVariant(v))
+	}
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO media (
+			owner_type, owner_id, position,
+			content_type, size, width, height,
+			storage_key, url, variants
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, created_at
+	`,
+		media.Owner,
+		media.OwnerID,
+		media.Position,
+		media.ContentType,
+		media.Size,
+		media.Width,
+		media.Height,
+		media.Key,
+		media.URL,
+		variants,
+	).Scan(&media.ID, &media.CreatedAt)
+	if err != nil {
+		return media, errorspkg.NewErrRepoFailed("insert media", method, err)
+	}
+
+	if err = syncOwnerImages(ctx, tx, table, media.Owner, media.OwnerID); err != nil {
+		return media, errorspkg.NewErrRepoFailed("sync images", method, err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return media, errorspkg.NewErrRepoFailed("Commit", method, err)
+	}
+
+	return media, nil
+}
+
+func (r *MediaRepo) Get(ctx context.Context, id int) (entities.Media, error) {
+	const method = "mediaRepo.Get"
+
+	media, err := scanMedia(r.pool.QueryRow(ctx, `SELECT `+mediaColumns+` FROM media WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return media, errorspkg.NewErrRepoNotFound("media", strconv.Itoa(id), method)
+		}
+		return media, errorspkg.NewErrRepoFailed("QueryRow", method, err)
+	}
+
+	return media, nil
+}
+
+func (r *MediaRepo) GetByOwner(ctx context.Context, owner entities.MediaOwner, ownerID int) ([]entities.Media, error) {
+	const method = "mediaRepo.GetByOwner"
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+mediaColumns+`
+		FROM media
+		WHERE owner_type = $1 AND owner_id = $2
+		ORDER BY position
+	`, owner, ownerID)
+	if err != nil {
+		return nil, errorspkg.NewErrRepoFailed("Query", method, err)
+	}
+	defer rows.Close()
+
+	result := make([]entities.Media, 0)
+	for rows.Next() {
+		media, scanErr := scanMedia(rows)
+		if scanErr != nil {
+			return nil, errorspkg.NewErrRepoFailed("Scan", method, scanErr)
+		}
+		result = append(result, media)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errorspkg.NewErrRepoFailed("rows.Err", method, err)
+	}
+
+	return result, nil
+}
+
+// Reorder puts the owner's media in the order of ids, which must list all of them.
+func (r *MediaRepo) Reorder(ctx context.Context, owner entities.MediaOwner, ownerID int, ids []int) error {
+	const method = "mediaRepo.Reorder"
+
+	table, ok := mediaOwnerTables[owner]
+	if !ok {
+		return errorspkg.ErrUnknownMediaOwner
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return errorspkg.NewErrRepoFailed("Begin", method, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	exists, err := lockMediaOwner(ctx, tx, table, ownerID)
+	if err != nil {
+		return errorspkg.NewErrRepoFailed("lock owner", method, err)
+	}
+	if !exists {
+		return errorspkg.NewErrRepoNotFound(string(owner), strconv.Itoa(ownerID), method)
+	}
+
+	rows, err := tx.Query(ctx, `SELECT id FROM media WHERE owner_type = $1 AND owner_id = $2`, owner, ownerID)
+	if err != nil {
+		return errorspkg.NewErrRepoFailed("select ids", method, err)
+	}
+	current, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return errorspkg.NewErrRepoFailed("CollectRows", method, err)
+	}
+	if !sameIDs(current, ids) {
+		return errorspkg.ErrMediaOrder
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE media m
+		SET position = o.ord
+		FROM unnest($1::int[]) WITH ORDINALITY AS o(id, ord)
+		WHERE m.id = o.id
+	`, ids)
+	if err != nil {
+		return errorspkg.NewErrRepoFailed("update positions", method, err)
+	}
+
+	if err = syncOwnerImages(ctx, tx, table, owner, ownerID); err != nil {
+		return errorspkg.NewErrRepoFailed("sync images", method, err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return errorspkg.NewErrRepoFailed("Commit", method, err)
+	}
+
+	return nil
+}
+
+// Delete removes the media and returns it, so the caller can drop its files from the storage.
+func (r *MediaRepo) Delete(ctx context.Context, id int) (entities.Media, error) {
+	const method = "mediaRepo.Delete"
+
+	media, err := r.Get(ctx, id)
+	if err != nil {
+		return media, err
+	}
+	table := mediaOwnerTables[media.Owner]
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return media, errorspkg.NewErrRepoFailed("Begin", method, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// The owner may be gone already, then only the media row is left to delete.
+	if _, err = lockMediaOwner(ctx, tx, table, media.OwnerID); err != nil {
+		return media, errorspkg.NewErrRepoFailed("lock owner", method, err)
+	}
+
+	err = tx.QueryRow(ctx, `DELETE FROM media WHERE id = $1 RETURNING position`, id).Scan(&media.Position)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return media, errorspkg.NewErrRepoNotFound("media", strconv.Itoa(id), method)
+		}
+		return media, errorspkg.NewErrRepoFailed("delete media", method, err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE media
+		SET position = position - 1
+		WHERE owner_type = $1 AND owner_id = $2 AND position > $3
+	`, media.Owner, media.OwnerID, media.Position)
+	if err != nil {
+		return media, errorspkg.NewErrRepoFailed("shift positions", method, err)
+	}
+
+	if err = syncOwnerImages(ctx, tx, table, media.Owner, media.OwnerID); err != nil {
+		return media, errorspkg.NewErrRepoFailed("sync images", method, err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return media, errorspkg.NewErrRepoFailed("Commit", method, err)
+	}
+
+	return media, nil
+}
+
+// lockMediaOwner serializes gallery changes of one owner and reports whether it exists.
+func lockMediaOwner(ctx context.Context, tx pgx.Tx, table string, ownerID int) (bool, error) {
+	var id int
+	err := tx.QueryRow(ctx, `SELECT id FROM `+table+` WHERE id = $1 FOR UPDATE`, ownerID).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// syncOwnerImages rewrites the owner's images with its media in gallery order,
+// taking the largest variant of each.
+func syncOwnerImages(ctx context.Context, tx pgx.Tx, table string, owner entities.MediaOwner, ownerID int) error {
+	_, err := tx.Exec(ctx, `
+		UPDATE `+table+`
+		SET images = ARRAY(
+			SELECT COALESCE(m.variants -> -1 ->> 'url', m.url)
+			FROM media m
+			WHERE m.owner_type = $1 AND m.owner_id = $2
+			ORDER BY m.position
+		)
+		WHERE id = $2
+	`, owner, ownerID)
+	return err
+}
+
+// sameIDs reports whether ids holds every id of current exactly once.
+func sameIDs(current, ids []int) bool {
+	if len(current) != len(ids) {
+		return false
+	}
+	seen := make(map[int]bool, len(current))
+	for _, id := range current {
+		seen[id] = true
+	}
+	for _, id := range ids {
+		if !seen[id] {
+			return false
+		}
+		delete(seen, id)
+	}
+	return true
+}
+
+func scanMedia(row pgx.Row) (entities.Media, error) {
+	var (
+		media    entities.Media
+		variants []mediaVariant
+	)
+	err := row.Scan(
+		&media.ID,
+		&media.Owner,
+		&media.OwnerID,
+		&media.Position,
+		&media.ContentType,
+		&media.Size,
+		&media.Width,
+		&media.Height,
+		&media.Key,
+		&media.URL,
+		&variants,
+		&media.CreatedAt,
+	)
+	if err != nil {
+		return media, err
+	}
+
+	media.Variants = make([]entities.MediaVariant, 0, len(variants))
+	for _, v := range variants {
+		media.Variants = append(media.Variants, entities.MediaVariant(v))
+	}
+
+	return media, nil
+}
@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"context"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type NotificationsRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewNotificationsRepo(pool *pgxpool.Pool) *NotificationsRepo {
+	return &NotificationsRepo{pool: pool}
+}
+
+func (r *NotificationsRepo) AddDelivery(ctx context.Context, d entities.NotificationDelivery) error {
+	const method = "notificationsRepo.AddDelivery"
+
+	query := `
+		INSERT INTO notification_deliveries (
+			kind, channel, recipient, guest_uuid, reference, status, error
+		) VALUES (
+			$1, $2, $3, $4, NULLIF($5, ''), $6, NULLIF($7, '')
+		)
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		d.Kind,
+		d.Channel,
+		d.Recipient,
+		d.GuestUUID,
+		d.Reference,
+		d.Status,
+		d.Error,
+	)
+	if err != nil {
+		return errorspkg.NewErrRepoFailed("Exec", method, err)
+	}
+
+	return nil
+}
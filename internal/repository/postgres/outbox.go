@@ -0,0 +1,167 @@
+package postgres
+
+import (
+	"context"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"strconv"
+	"time"
+)
+
+type OutboxRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewOutboxRepo(pool *pgxpool.Pool) *OutboxRepo {
+	return &OutboxRepo{pool: pool}
+}
+
+// Claim picks due messages and pushes their next_attempt_at forward by lease, so a
+// parallel or overlapping worker run doesn't take them while they are being sent.
+func (r *OutboxRepo) Claim(ctx context.Context, limit int, lease time.Duration) ([]entities.OutboxMessage, error) {
+	const method = "outboxRepo.Claim"
+
+	query := `
+		UPDATE notification_outbox o
+		SET next_attempt_at = now() + $2 * interval '1 second',
+			updated_at = now()
+		WHERE o.id IN (
+			SELECT id
+			FROM notification_outbox
+			WHERE status = 'pending' AND next_attempt_at <= now()
+			ORDER BY next_attempt_at, id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING o.id, o.kind, o.payload, o.status, o.attempts, COALESCE(o.last_error, ''), o.next_attempt_at, o.created_at
+	`
+
+	rows, err := r.pool.Query(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, errorspkg.NewErrRepoFailed("Query", method, err)
+	}
+	defer rows.Close()
+
+	return scanOutbox(rows, method)
+}
+
+func (r *OutboxRepo) MarkSent(ctx context.Context, id int64) error {
+	const method = "outboxRepo.MarkSent"
+
+	query := `
+		UPDATE notification_outbox
+		SET status = 'sent', attempts = attempts + 1, last_error = NULL, updated_at = now()
+		WHERE id = $1
+	`
+
+	if _, err := r.pool.Exec(ctx, query, id); err != nil {
+		return errorspkg.NewErrRepoFailed("Exec", method, err)
+	}
+	return nil
+}
+
+func (r *OutboxRepo) MarkRetry(ctx context.Context, id int64, attempts int, nextAttemptAt time.Time, lastErr string) error {
+	const method = "outboxRepo.MarkRetry"
+
+	query := `
+		UPDATE notification_outbox
+		SET attempts = $2, next_attempt_at = $3, last_error = $4, updated_at = now()
+		WHERE id = $1
+	`
+
+	if _, err := r.pool.Exec(ctx, query, id, attempts, nextAttemptAt, lastErr); err != nil {
+		return errorspkg.NewErrRepoFailed("Exec", method, err)
+	}
+	return nil
+}
+
+func (r *OutboxRepo) MarkFailed(ctx context.Context, id int64, attempts int, lastErr string) error {
+	const method = "outboxRepo.MarkFailed"
+
+	query := `
+		UPDATE notification_outbox
+		SET status = 'failed', attempts = $2, last_error = $3, updated_at = now()
+		WHERE id = $1
+	`
+
+	if _, err := r.pool.Exec(ctx, query, id, attempts, lastErr); err != nil {
+		return errorspkg.NewErrRepoFailed("Exec", method, err)
+	}
+	return nil
+}
+
+func (r *OutboxRepo) GetFailed(ctx context.Context) ([]entities.OutboxMessage, error) {
+	const method = "outboxRepo.GetFailed"
+
+	query := `
+		SELECT id, kind, payload, status, attempts, COALESCE(last_error, ''), next_attempt_at, created_at
+		FROM notification_outbox
+		WHERE status = 'failed'
+		ORDER BY id DESC
+	`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, errorspkg.NewErrRepoFailed("Query", method, err)
+	}
+	defer rows.Close()
+
+	return scanOutbox(rows, method)
+}
+
+func (r *OutboxRepo) Requeue(ctx context.Context, id int64) error {
+	const method = "outboxRepo.Requeue"
+
+	query := `
+		UPDATE notification_outbox
+		SET status = 'pending', attempts = 0, next_attempt_at = now(), updated_at = now()
+		WHERE id = $1 AND status = 'failed'
+	`
+
+	tag, err := r.pool.Exec(ctx, query, id)
+	if err != nil {
+		return errorspkg.NewErrRepoFailed("Exec", method, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errorspkg.NewErrRepoNotFound("failed outbox message", strconv.FormatInt(id, 10), method)
+	}
+	return nil
+}
+
+// insertOutbox is used by other repositories to enqueue notifications in their own transaction.
+func insertOutbox(ctx context.Context, tx pgx.Tx, messages []entities.OutboxMessage) error {
+	query := `INSERT INTO notification_outbox (kind, payload) VALUES ($1, $2)`
+
+	for _, m := range messages {
+		if _, err := tx.Exec(ctx, query, m.Kind, m.Payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func scanOutbox(rows pgx.Rows, method string) ([]entities.OutboxMessage, error) {
+	var res []entities.OutboxMessage
+	for rows.Next() {
+		var m entities.OutboxMessage
+		if err := rows.Scan(
+			&m.ID,
+			&m.Kind,
+			&m.Payload,
+			&m.Status,
+			&m.Attempts,
+			&m.LastError,
+			&m.NextAttemptAt,
+			&m.CreatedAt,
+		); err != nil {
+			return nil, errorspkg.NewErrRepoFailed("Scan", method, err)
+		}
+		res = append(res, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errorspkg.NewErrRepoFailed("Rows", method, err)
+	}
+	return res, nil
+}
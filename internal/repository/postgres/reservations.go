@@ -3,11 +3,14 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
 	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/calyrexx/QuietGrooveBackend/internal/repository"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"strings"
 	"time"
 )
 
@@ -19,13 +22,15 @@ func NewReservationsRepo(pool *pgxpool.Pool) *ReservationsRepo {
 	return &ReservationsRepo{pool: pool}
 }
 
-func (r *ReservationsRepo) GetAvailableHouses(ctx context.Context, req entities.GetAvailableHouses) ([]int, error) {
-	const method = "reservationsRepo.CheckAvailability"
+// GetAvailableHouses loads the free houses and then all their bathhouses, two queries whatever
+// the number of houses.
+func (r *ReservationsRepo) GetAvailableHouses(ctx context.Context, req entities.GetAvailableHouses) ([]entities.AvailableHouse, error) {
+	const method = "reservationsRepo.GetAvailableHouses"
 
 	query := `
-        SELECT h.id
+        SELECT ` + houseColumns + `
         FROM houses h
-        WHERE h.capacity >= $3
+        WHERE ` + houseFiltersSQL + `
         AND NOT EXISTS (
             SELECT 1 FROM reservations r
             WHERE r.house_id = h.id
@@ -37,34 +42,107 @@ func (r *ReservationsRepo) GetAvailableHouses(ctx context.Context, req entities.
             WHERE b.house_id = h.id
             AND b.period && daterange($1::date, $2::date)
         )
+        ORDER BY h.id
     `
 
-	rows, err := r.pool.Query(
-		ctx,
-		query,
-		req.CheckIn,
-		req.CheckOut,
-		req.GuestsCount,
-	)
+	rows, err := r.pool.Query(ctx, query, houseFiltersArgs(req)...)
+	if err != nil {
+		return nil, errorspkg.NewErrRepoFailed("Query", method, err)
+	}
+	houses, err := collectHouses(rows)
+	if err != nil {
+		return nil, errorspkg.NewErrRepoFailed("collectHouses", method, err)
+	}
+	if len(houses) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]int, 0, len(houses))
+	for _, h := range houses {
+		ids = append(ids, h.ID)
+	}
+	bathhouses, err := bathhousesByHouses(ctx, r.pool, ids)
+	if err != nil {
+		return nil, errorspkg.NewErrRepoFailed("bathhousesByHouses", method, err)
+	}
+
+	byHouse := make(map[int][]entities.Bathhouse, len(houses))
+	for _, bh := range bathhouses {
+		byHouse[bh.HouseID] = append(byHouse[bh.HouseID], bh)
+	}
+
+	result := make([]entities.AvailableHouse, 0, len(houses))
+	for _, h := range houses {
+		result = append(result, entities.AvailableHouse{
+			House:      h,
+			Bathhouses: byHouse[h.ID],
+		})
+	}
+
+	return result, nil
+}
+
+// GetOccupancy returns the houses matching the filters of req with their reservations and
+// blackouts overlapping req.CheckIn to req.CheckOut, houses without any have no Busy ranges.
+func (r *ReservationsRepo) GetOccupancy(ctx context.Context, req entities.GetAvailableHouses) ([]entities.HouseOccupancy, error) {
+	const method = "reservationsRepo.GetOccupancy"
+
+	query := `
+        SELECT h.id, busy.period IS NOT NULL, lower(busy.period), upper(busy.period)
+        FROM houses h
+        LEFT JOIN LATERAL (
+            SELECT r.stay AS period FROM reservations r
+            WHERE r.house_id = h.id
+            AND r.stay && daterange($1::date, $2::date)
+            AND r.status NOT IN ('cancelled', 'checked_out')
+            UNION ALL
+            SELECT b.period FROM blackouts b
+            WHERE b.house_id = h.id
+            AND b.period && daterange($1::date, $2::date)
+        ) busy ON true
+        WHERE ` + houseFiltersSQL + `
+        ORDER BY h.id, lower(busy.period)
+    `
+
+	rows, err := r.pool.Query(ctx, query, houseFiltersArgs(req)...)
 	if err != nil {
 		return nil, errorspkg.NewErrRepoFailed("Query", method, err)
 	}
 	defer rows.Close()
 
-	var availableHouseIDs []int
+	result := make([]entities.HouseOccupancy, 0)
 	for rows.Next() {
-		var houseID int
-		if err = rows.Scan(&houseID); err != nil {
+		var (
+			houseID  int
+			hasBusy  bool
+			from, to *time.Time
+		)
+		if err = rows.Scan(&houseID, &hasBusy, &from, &to); err != nil {
 			return nil, errorspkg.NewErrRepoFailed("Scan", method, err)
 		}
-		availableHouseIDs = append(availableHouseIDs, houseID)
+		if len(result) == 0 || result[len(result)-1].HouseID != houseID {
+			result = append(result, entities.HouseOccupancy{HouseID: houseID})
+		}
+		if !hasBusy {
+			continue
+		}
+		// An unbounded range has no lower or upper date, the window edge stands in for it.
+		busy := entities.DateRange{From: req.CheckIn, To: req.CheckOut}
+		if from != nil {
+			busy.From = *from
+		}
+		if to != nil {
+			busy.To = *to
+		}
+		last := &result[len(result)-1]
+		last.Busy = append(last.Busy, busy)
 	}
 
 	if err = rows.Err(); err != nil {
 		return nil, errorspkg.NewErrRepoFailed("rows.Err", method, err)
 	}
 
-	return availableHouseIDs, nil
+	return result, nil
 }
 
 func (r *ReservationsRepo) CheckAvailability(ctx context.Context, req entities.CheckAvailability) (bool, error) {
@@ -98,27 +176,113 @@ func (r *ReservationsRepo) CheckAvailability(ctx context.Context, req entities.C
 	return available, nil
 }
 
-func (r *ReservationsRepo) Cancel(ctx context.Context, userTgId int64, reservationUUID string) error {
-	const method = "reservationsRepo.Cancel"
+func (r *ReservationsRepo) Cancel(
+	ctx context.Context,
+	userTgId int64,
+	reservationUUID string,
+	notify repository.CancelNotifications,
+) error {
+	return r.cancel(ctx, "reservationsRepo.Cancel", "AND g.tg_user_id = $2", notify, reservationUUID, userTgId)
+}
 
+// CancelByAdmin cancels any reservation that hasn't ended yet, whoever booked it.
+func (r *ReservationsRepo) CancelByAdmin(ctx context.Context, reservationUUID string, notify repository.CancelNotifications) error {
+	return r.cancel(ctx, "reservationsRepo.CancelByAdmin",
+		"AND r.status IN ('pending', 'confirmed', 'checked_in')", notify, reservationUUID)
+}
+
+// cancel updates the reservation and queues the notifications built from it in one
+// transaction, so a cancellation is never left without them.
+func (r *ReservationsRepo) cancel(
+	ctx context.Context,
+	method, condition string,
+	notify repository.CancelNotifications,
+	reservationUUID string,
+	args ...any,
+) error {
 	query := `
-		UPDATE reservations
+		UPDATE reservations r
 		SET 
 			status = 'cancelled',
 			updated_at = NOW()
-		WHERE uuid = $1
-		AND guest_uuid IN (
-			SELECT uuid 
-			FROM guests 
-			WHERE tg_user_id = $2
-		)
+		FROM guests g, houses h
+		WHERE r.uuid = $1
+			AND g.uuid = r.guest_uuid
+			AND h.id = r.house_id
+			` + condition + `
+		RETURNING
+			r.uuid,
+			h.name,
+			g.uuid,
+			g.name,
+			COALESCE(g.phone, ''),
+			g.email,
+			COALESCE(g.tg_user_id, 0),
+			g.locale,
+			LOWER(r.stay),
+			UPPER(r.stay),
+			r.total_price
 	`
 
-	_, err := r.pool.Exec(ctx, query, reservationUUID, userTgId)
+	tx, err := r.pool.Begin(ctx)
 	if err != nil {
+		return errorspkg.NewErrRepoFailed("BeginTx", method, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var res entities.ReservationCancelledMessage
+	err = tx.QueryRow(ctx, query, append([]any{reservationUUID}, args...)...).Scan(
+		&res.UUID,
+		&res.HouseName,
+		&res.GuestUUID,
+		&res.GuestName,
+		&res.GuestPhone,
+		&res.GuestEmail,
+		&res.GuestTgID,
+		&res.GuestLocale,
+		&res.CheckIn,
+		&res.CheckOut,
+		&res.TotalPrice,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errorspkg.NewErrRepoNotFound("reservation", reservationUUID, method)
+		}
 		return errorspkg.NewErrRepoFailed("QueryRow", method, err)
 	}
 
+	notifications, err := notify(res)
+	if err != nil {
+		return err
+	}
+	if err = insertOutbox(ctx, tx, notifications); err != nil {
+		return errorspkg.NewErrRepoFailed("Exec Insert Outbox", method, err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return errorspkg.NewErrRepoFailed("Commit", method, err)
+	}
+
+	return nil
+}
+
+// SetStatus moves the reservation to status if it is currently in one of from.
+func (r *ReservationsRepo) SetStatus(ctx context.Context, reservationUUID uuid.UUID, from []string, status string) error {
+	const method = "reservationsRepo.SetStatus"
+
+	query := `
+		UPDATE reservations
+		SET status = $3, updated_at = NOW()
+		WHERE uuid = $1 AND status::text = ANY($2)
+	`
+
+	tag, err := r.pool.Exec(ctx, query, reservationUUID, from, status)
+	if err != nil {
+		return errorspkg.NewErrRepoFailed("Exec", method, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errorspkg.NewErrRepoNotFound("reservation in status "+strings.Join(from, "/"), reservationUUID.String(), method)
+	}
 	return nil
 }
 
@@ -195,24 +359,31 @@ func (r *ReservationsRepo) GetPrice(ctx context.Context, houseID int, extras []e
 	return response, nil
 }
 
-func (r *ReservationsRepo) Create(ctx context.Context, reservation entities.Reservation) error {
+func (r *ReservationsRepo) Create(
+	ctx context.Context,
+	reservation entities.Reservation,
+	notifications []entities.OutboxMessage,
+) (uuid.UUID, error) {
 	const method = "reservationsRepo.Create"
 
 	tx, err := r.pool.Begin(ctx)
 	if err != nil {
-		return errorspkg.NewErrRepoFailed("BeginTx", method, err)
+		return uuid.Nil, errorspkg.NewErrRepoFailed("BeginTx", method, err)
 	}
 
 	var resUUID uuid.UUID
 	queryReservation := `
 		INSERT INTO reservations (
-			uuid, house_id, guest_uuid, stay, guests_count, status, total_price
+			uuid, house_id, guest_uuid, stay, guests_count, status, total_price, loyalty_discount
 		) VALUES (
-			$1, $2, $3, daterange($4::date, $5::date), $6, $7, $8
+			$1, $2, $3, daterange($4::date, $5::date), $6, $7, $8, $9
 		)
 		RETURNING uuid
 	`
-	resUUID = uuid.New()
+	resUUID = reservation.UUID
+	if resUUID == uuid.Nil {
+		resUUID = uuid.New()
+	}
 	_, err = tx.Exec(ctx, queryReservation,
 		resUUID,
 		reservation.HouseID,
@@ -222,10 +393,11 @@ func (r *ReservationsRepo) Create(ctx context.Context, reservation entities.Rese
 		reservation.GuestsCount,
 		reservation.Status,
 		reservation.TotalPrice,
+		reservation.Discount,
 	)
 	if err != nil {
 		_ = tx.Rollback(ctx)
-		return errorspkg.NewErrRepoFailed("Exec Insert Reservation", method, err)
+		return uuid.Nil, errorspkg.NewErrRepoFailed("Exec Insert Reservation", method, err)
 	}
 
 	if len(reservation.Bathhouse) > 0 {
@@ -245,16 +417,21 @@ func (r *ReservationsRepo) Create(ctx context.Context, reservation entities.Rese
 			)
 			if err != nil {
 				_ = tx.Rollback(ctx)
-				return errorspkg.NewErrRepoFailed("Exec Insert Bathhouse", method, err)
+				return uuid.Nil, errorspkg.NewErrRepoFailed("Exec Insert Bathhouse", method, err)
 			}
 		}
 	}
 
+	if err = insertOutbox(ctx, tx, notifications); err != nil {
+		_ = tx.Rollback(ctx)
+		return uuid.Nil, errorspkg.NewErrRepoFailed("Exec Insert Outbox", method, err)
+	}
+
 	if err = tx.Commit(ctx); err != nil {
-		return errorspkg.NewErrRepoFailed("Commit", method, err)
+		return uuid.Nil, errorspkg.NewErrRepoFailed("Commit", method, err)
 	}
 
-	return nil
+	return resUUID, nil
 }
 
 func (r *ReservationsRepo) GetByTelegramID(ctx context.Context, telegramID int64) ([]entities.ReservationMessage, error) {
@@ -310,6 +487,7 @@ func (r *ReservationsRepo) GetDetailsByUUID(ctx context.Context, telegramID int6
 		SELECT
 			r.uuid,
 			h.name AS house_name,
+			COALESCE(NULLIF(h.cover_image, ''), h.images[1], '') AS image_url,
 			LOWER(r.stay) AS check_in,
 			UPPER(r.stay) AS check_out,
 			r.guests_count,
@@ -327,6 +505,7 @@ func (r *ReservationsRepo) GetDetailsByUUID(ctx context.Context, telegramID int6
 	err := r.pool.QueryRow(ctx, query, uuid, telegramID).Scan(
 		&resUUID,
 		&res.HouseName,
+		&res.ImageURL,
 		&res.CheckIn,
 		&res.CheckOut,
 		&res.GuestsCount,
@@ -379,6 +558,8 @@ func (r *ReservationsRepo) GetDetailsByUUID(ctx context.Context, telegramID int6
 	return res, nil
 }
 
+// GetAllConfirmed returns reservations whose status still moves with time: confirmed and
+// checked in (the latter may have been marked by an admin before the check-in date).
 func (r *ReservationsRepo) GetAllConfirmed(ctx context.Context) ([]entities.ReservationUpdateStatus, error) {
 	const method = "reservationsRepo.GetAllConfirmed"
 
@@ -389,7 +570,7 @@ func (r *ReservationsRepo) GetAllConfirmed(ctx context.Context) ([]entities.Rese
 			UPPER(stay) AS check_out,
 			status
 		FROM reservations
-		WHERE status = 'confirmed'
+		WHERE status IN ('confirmed', 'checked_in')
 	`
 
 	rows, err := r.pool.Query(ctx, query)
@@ -446,6 +627,8 @@ func (r *ReservationsRepo) UpdateStatuses(ctx context.Context, reservations []en
 	return nil
 }
 
+// GetAllForReminder returns reservations that may still need a reminder (until the check-out
+// day is over) together with the stages already sent for them.
 func (r *ReservationsRepo) GetAllForReminder(ctx context.Context) ([]entities.ReservationReminderNotification, error) {
 	const method = "reservationsRepo.GetAllForReminder"
 
@@ -455,11 +638,25 @@ func (r *ReservationsRepo) GetAllForReminder(ctx context.Context) ([]entities.Re
 			h.name AS house_name,
 			LOWER(r.stay) AS check_in,
 			UPPER(r.stay) AS check_out,
-			g.tg_user_id
+			h.check_in_from,
+			h.check_out_until,
+			h.directions,
+			COALESCE(g.tg_user_id, 0),
+			g.uuid,
+			g.name,
+			g.email,
+			COALESCE(g.phone, ''),
+			g.locale,
+			r.created_at,
+			COALESCE(
+				(SELECT array_agg(rr.stage) FROM reservation_reminders rr WHERE rr.reservation_uuid = r.uuid),
+				'{}'::text[]
+			)
 		FROM reservations r
 		JOIN guests g ON r.guest_uuid = g.uuid
 		JOIN houses h ON r.house_id = h.id
-		WHERE r.status = 'confirmed'
+		WHERE r.status IN ('confirmed', 'checked_in')
+			AND UPPER(r.stay) >= current_date
 	`
 
 	rows, err := r.pool.Query(ctx, query)
@@ -476,7 +673,17 @@ func (r *ReservationsRepo) GetAllForReminder(ctx context.Context) ([]entities.Re
 			&res.HouseName,
 			&res.CheckIn,
 			&res.CheckOut,
+			&res.CheckInFrom,
+			&res.CheckOutUntil,
+			&res.Directions,
 			&res.UserTgID,
+			&res.GuestUUID,
+			&res.GuestName,
+			&res.GuestEmail,
+			&res.GuestPhone,
+			&res.GuestLocale,
+			&res.CreatedAt,
+			&res.SentStages,
 		); err != nil {
 			return nil, errorspkg.NewErrRepoFailed("Scan", method, err)
 		}
@@ -484,3 +691,66 @@ func (r *ReservationsRepo) GetAllForReminder(ctx context.Context) ([]entities.Re
 	}
 	return result, nil
 }
+
+// ClaimReminder records the stage for the reservation and reports whether this call did it,
+// so overlapping cron runs never send the same stage twice.
+func (r *ReservationsRepo) ClaimReminder(ctx context.Context, reservationUUID uuid.UUID, stage, status string) (bool, error) {
+	const method = "reservationsRepo.ClaimReminder"
+
+	query := `
+		INSERT INTO reservation_reminders (reservation_uuid, stage, status)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (reservation_uuid, stage) DO NOTHING
+	`
+
+	tag, err := r.pool.Exec(ctx, query, reservationUUID, stage, status)
+	if err != nil {
+		return false, errorspkg.NewErrRepoFailed("Exec", method, err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// ReleaseReminder forgets a claimed stage after a failed send so the next run retries it.
+func (r *ReservationsRepo) ReleaseReminder(ctx context.Context, reservationUUID uuid.UUID, stage string) error {
+	const method = "reservationsRepo.ReleaseReminder"
+
+	query := `DELETE FROM reservation_reminders WHERE reservation_uuid = $1 AND stage = $2`
+
+	if _, err := r.pool.Exec(ctx, query, reservationUUID, stage); err != nil {
+		return errorspkg.NewErrRepoFailed("Exec", method, err)
+	}
+	return nil
+}
+
+// houseFiltersSQL matches houses h against the filters bound by houseFiltersArgs, starting at $3.
+const houseFiltersSQL = `h.capacity >= $3
+        AND (NOT $4::bool OR h.sauna)
+        AND (NOT $5::bool OR h.pets_allowed)
+        AND (NOT $6::bool OR h.kitchen)
+        AND (NOT $7::bool OR h.fireplace)
+        AND (NOT $8::bool OR EXISTS (
+            SELECT 1 FROM bathhouses bh
+            WHERE bh.house_id = h.id
+        ))
+        AND h.area >= $9
+        AND h.tags @> $10::text[]`
+
+// houseFiltersArgs binds the dates of req to $1 and $2 and its filters to the houseFiltersSQL parameters.
+func houseFiltersArgs(req entities.GetAvailableHouses) []any {
+	tags := req.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return []any{
+		req.CheckIn,
+		req.CheckOut,
+		req.GuestsCount,
+		req.Sauna,
+		req.PetsAllowed,
+		req.Kitchen,
+		req.Fireplace,
+		req.WithBathhouse,
+		req.MinArea,
+		tags,
+	}
+}
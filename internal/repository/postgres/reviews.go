@@ -0,0 +1,254 @@
+package postgres
+
+import (
+	"context"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"strconv"
+	"time"
+)
+
+type ReviewsRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewReviewsRepo(pool *pgxpool.Pool) *ReviewsRepo {
+	return &ReviewsRepo{pool: pool}
+}
+
+// GetForFeedback returns checked out stays of Telegram guests who haven't been asked for a
+// review yet. updated_at is the moment UpdateStatuses moved the reservation to checked_out.
+func (r *ReviewsRepo) GetForFeedback(
+	ctx context.Context,
+	checkedOutBefore, checkedOutAfter time.Time,
+) ([]entities.FeedbackRequest, error) {
+	const method = "reviewsRepo.GetForFeedback"
+
+	query := `
+		SELECT
+			r.uuid,
+			h.name,
+			g.name,
+			g.tg_user_id,
+			g.locale,
+			LOWER(r.stay) AS check_in,
+			UPPER(r.stay) AS check_out
+		FROM reservations r
+		JOIN guests g ON r.guest_uuid = g.uuid
+		JOIN houses h ON r.house_id = h.id
+		WHERE r.status = 'checked_out'
+			AND r.feedback_requested_at IS NULL
+			AND g.tg_user_id IS NOT NULL
+			AND r.updated_at <= $1
+			AND r.updated_at > $2
+	`
+
+	rows, err := r.pool.Query(ctx, query, checkedOutBefore, checkedOutAfter)
+	if err != nil {
+		return nil, errorspkg.NewErrRepoFailed("Query", method, err)
+	}
+	defer rows.Close()
+
+	var result []entities.FeedbackRequest
+	for rows.Next() {
+		var req entities.FeedbackRequest
+		if err = rows.Scan(
+			&req.ReservationUUID,
+			&req.HouseName,
+			&req.GuestName,
+			&req.GuestTgID,
+			&req.GuestLocale,
+			&req.CheckIn,
+			&req.CheckOut,
+		); err != nil {
+			return nil, errorspkg.NewErrRepoFailed("Scan", method, err)
+		}
+		result = append(result, req)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errorspkg.NewErrRepoFailed("rows.Err", method, err)
+	}
+
+	return result, nil
+}
+
+func (r *ReviewsRepo) MarkFeedbackRequested(ctx context.Context, reservationUUID uuid.UUID) error {
+	const method = "reviewsRepo.MarkFeedbackRequested"
+
+	query := `UPDATE reservations SET feedback_requested_at = now() WHERE uuid = $1`
+
+	if _, err := r.pool.Exec(ctx, query, reservationUUID); err != nil {
+		return errorspkg.NewErrRepoFailed("Exec", method, err)
+	}
+	return nil
+}
+
+// Rate creates the review or changes its rating; a changed review goes back to moderation.
+func (r *ReviewsRepo) Rate(ctx context.Context, tgID int64, reservationUUID uuid.UUID, rating int) error {
+	const method = "reviewsRepo.Rate"
+
+	query := `
+		INSERT INTO reviews (reservation_uuid, house_id, guest_uuid, rating)
+		SELECT r.uuid, r.house_id, g.uuid, $3
+		FROM reservations r
+		JOIN guests g ON r.guest_uuid = g.uuid
+		WHERE r.uuid = $1 AND g.tg_user_id = $2 AND r.status = 'checked_out'
+		ON CONFLICT (reservation_uuid) DO UPDATE
+		SET rating = EXCLUDED.rating,
+			awaiting_comment = true,
+			status = 'pending',
+			moderated_at = NULL,
+			updated_at = now()
+	`
+
+	tag, err := r.pool.Exec(ctx, query, reservationUUID, tgID, rating)
+	if err != nil {
+		return errorspkg.NewErrRepoFailed("Exec", method, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errorspkg.NewErrRepoNotFound("reservation", reservationUUID.String(), method)
+	}
+	return nil
+}
+
+// SetComment attaches the text to the guest's latest review that is waiting for one.
+func (r *ReviewsRepo) SetComment(ctx context.Context, tgID int64, comment string) error {
+	const method = "reviewsRepo.SetComment"
+
+	query := `
+		UPDATE reviews
+		SET comment = $2,
+			awaiting_comment = false,
+			status = 'pending',
+			moderated_at = NULL,
+			updated_at = now()
+		WHERE id = (
+			SELECT rv.id
+			FROM reviews rv
+			JOIN guests g ON rv.guest_uuid = g.uuid
+			WHERE g.tg_user_id = $1 AND rv.awaiting_comment
+			ORDER BY rv.updated_at DESC
+			LIMIT 1
+		)
+	`
+
+	tag, err := r.pool.Exec(ctx, query, tgID, comment)
+	if err != nil {
+		return errorspkg.NewErrRepoFailed("Exec", method, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errorspkg.NewErrRepoNotFound("review awaiting comment", strconv.FormatInt(tgID, 10), method)
+	}
+	return nil
+}
+
+func (r *ReviewsRepo) SkipComment(ctx context.Context, tgID int64, reservationUUID uuid.UUID) error {
+	const method = "reviewsRepo.SkipComment"
+
+	query := `
+		UPDATE reviews rv
+		SET awaiting_comment = false, updated_at = now()
+		FROM guests g
+		WHERE rv.guest_uuid = g.uuid AND g.tg_user_id = $1 AND rv.reservation_uuid = $2
+	`
+
+	tag, err := r.pool.Exec(ctx, query, tgID, reservationUUID)
+	if err != nil {
+		return errorspkg.NewErrRepoFailed("Exec", method, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errorspkg.NewErrRepoNotFound("review", reservationUUID.String(), method)
+	}
+	return nil
+}
+
+// GetAll returns reviews for moderation, an empty status means all of them.
+func (r *ReviewsRepo) GetAll(ctx context.Context, status entities.ReviewStatus) ([]entities.Review, error) {
+	const method = "reviewsRepo.GetAll"
+
+	query := `
+		SELECT rv.id, rv.reservation_uuid, rv.house_id, COALESCE(g.name, ''), rv.rating, rv.comment,
+			rv.status, rv.created_at, rv.moderated_at
+		FROM reviews rv
+		LEFT JOIN guests g ON rv.guest_uuid = g.uuid
+		WHERE $1 = '' OR rv.status = $1
+		ORDER BY rv.created_at DESC
+	`
+
+	rows, err := r.pool.Query(ctx, query, status)
+	if err != nil {
+		return nil, errorspkg.NewErrRepoFailed("Query", method, err)
+	}
+	defer rows.Close()
+
+	return scanReviews(rows, method)
+}
+
+func (r *ReviewsRepo) GetApproved(ctx context.Context) ([]entities.Review, error) {
+	const method = "reviewsRepo.GetApproved"
+
+	query := `
+		SELECT rv.id, rv.reservation_uuid, rv.house_id, COALESCE(g.name, ''), rv.rating, rv.comment,
+			rv.status, rv.created_at, rv.moderated_at
+		FROM reviews rv
+		LEFT JOIN guests g ON rv.guest_uuid = g.uuid
+		WHERE rv.status = 'approved'
+		ORDER BY rv.created_at DESC
+	`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, errorspkg.NewErrRepoFailed("Query", method, err)
+	}
+	defer rows.Close()
+
+	return scanReviews(rows, method)
+}
+
+func (r *ReviewsRepo) SetStatus(ctx context.Context, id int, status entities.ReviewStatus) error {
+	const method = "reviewsRepo.SetStatus"
+
+	query := `
+		UPDATE reviews
+		SET status = $2, moderated_at = now(), updated_at = now()
+		WHERE id = $1
+	`
+
+	tag, err := r.pool.Exec(ctx, query, id, status)
+	if err != nil {
+		return errorspkg.NewErrRepoFailed("Exec", method, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errorspkg.NewErrRepoNotFound("review", strconv.Itoa(id), method)
+	}
+	return nil
+}
+
+func scanReviews(rows pgx.Rows, method string) ([]entities.Review, error) {
+	var result []entities.Review
+	for rows.Next() {
+		var rv entities.Review
+		if err := rows.Scan(
+			&rv.ID,
+			&rv.ReservationUUID,
+			&rv.HouseID,
+			&rv.GuestName,
+			&rv.Rating,
+			&rv.Comment,
+			&rv.Status,
+			&rv.CreatedAt,
+			&rv.ModeratedAt,
+		); err != nil {
+			return nil, errorspkg.NewErrRepoFailed("Scan", method, err)
+		}
+		result = append(result, rv)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errorspkg.NewErrRepoFailed("rows.Err", method, err)
+	}
+
+	return result, nil
+}
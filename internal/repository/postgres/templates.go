@@ -0,0 +1,66 @@
+package postgres
+
+import (
+	"context"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type TemplatesRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewTemplatesRepo(pool *pgxpool.Pool) *TemplatesRepo {
+	return &TemplatesRepo{pool: pool}
+}
+
+func (r *TemplatesRepo) GetAll(ctx context.Context) ([]entities.MessageTemplate, error) {
+	const method = "templatesRepo.GetAll"
+
+	rows, err := r.pool.Query(ctx, `SELECT key, locale, body, updated_at FROM message_templates`)
+	if err != nil {
+		return nil, errorspkg.NewErrRepoFailed("Query", method, err)
+	}
+	defer rows.Close()
+
+	var res []entities.MessageTemplate
+	for rows.Next() {
+		var t entities.MessageTemplate
+		if err = rows.Scan(&t.Key, &t.Locale, &t.Body, &t.UpdatedAt); err != nil {
+			return nil, errorspkg.NewErrRepoFailed("Scan", method, err)
+		}
+		res = append(res, t)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errorspkg.NewErrRepoFailed("Rows", method, err)
+	}
+
+	return res, nil
+}
+
+func (r *TemplatesRepo) Upsert(ctx context.Context, tmpl entities.MessageTemplate) error {
+	const method = "templatesRepo.Upsert"
+
+	query := `
+		INSERT INTO message_templates (key, locale, body)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (key, locale) DO UPDATE
+		SET body = EXCLUDED.body, updated_at = now()
+	`
+
+	if _, err := r.pool.Exec(ctx, query, tmpl.Key, tmpl.Locale, tmpl.Body); err != nil {
+		return errorspkg.NewErrRepoFailed("Exec", method, err)
+	}
+	return nil
+}
+
+func (r *TemplatesRepo) Delete(ctx context.Context, key, locale string) error {
+	const method = "templatesRepo.Delete"
+
+	_, err := r.pool.Exec(ctx, `DELETE FROM message_templates WHERE key = $1 AND locale = $2`, key, locale)
+	if err != nil {
+		return errorspkg.NewErrRepoFailed("Exec", method, err)
+	}
+	return nil
+}
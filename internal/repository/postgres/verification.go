@@ -2,11 +2,30 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"strconv"
+	"time"
 )
 
+const verificationColumns = `
+		uuid,
+		token_hash,
+		email,
+		name,
+		phone,
+		tg_user_id,
+		status,
+		created_at,
+		verified_at,
+		expires_at,
+		notify_channels,
+		attempts`
+
 type VerificationRepo struct {
 	pool *pgxpool.Pool
 }
@@ -17,16 +36,19 @@ func NewVerificationRepo(pool *pgxpool.Pool) *VerificationRepo {
 	}
 }
 
-func (r *VerificationRepo) Create(ctx context.Context, v entities.Verification) error {
+func (r *VerificationRepo) Create(ctx context.Context, v entities.Verification) (string, error) {
+	const method = "verificationRepo.Create"
+
 	query := `
 		INSERT INTO verifications (
 		    uuid,
 		    name,
-			code,
+			token_hash,
 		    email,
 		    phone,
 		    status,
-		    expires_at
+		    expires_at,
+		    notify_channels
 		)
         VALUES (
         	$1,
@@ -35,33 +57,292 @@ func (r *VerificationRepo) Create(ctx context.Context, v entities.Verification)
             $4,
             $5,
             $6,
-            $7
+            $7,
+            $8
         )`
 
-	_, err := r.pool.Exec(ctx, query, uuid.New(), v.Name, v.Code, v.Email, v.Phone, v.Status, v.ExpiresAt)
+	id := uuid.New()
+	_, err := r.pool.Exec(ctx, query, id, v.Name, v.TokenHash, v.Email, v.Phone, v.Status, v.ExpiresAt,
+		channelsToStrings(v.NotifyChannels))
+	if err != nil {
+		return "", errorspkg.NewErrRepoFailed("Exec", method, err)
+	}
+
+	return id.String(), nil
+}
+
+func (r *VerificationRepo) GetByID(ctx context.Context, id string) (entities.Verification, error) {
+	const method = "verificationRepo.GetByID"
+
+	query := `SELECT ` + verificationColumns + ` FROM verifications WHERE uuid=$1`
+
+	v, err := scanVerification(r.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return v, errorspkg.NewErrRepoNotFound("verification", id, method)
+		}
+		return v, errorspkg.NewErrRepoFailed("QueryRow", method, err)
+	}
+
+	return v, nil
+}
+
+func (r *VerificationRepo) GetPendingByToken(ctx context.Context, tokenHash string) (entities.Verification, error) {
+	const method = "verificationRepo.GetPendingByToken"
+
+	query := `SELECT ` + verificationColumns + ` FROM verifications WHERE token_hash=$1 AND status=$2`
+
+	v, err := scanVerification(r.pool.QueryRow(ctx, query, tokenHash, entities.VerifPending))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return v, errorspkg.NewErrRepoNotFound("verification", "token", method)
+		}
+		return v, errorspkg.NewErrRepoFailed("QueryRow", method, err)
+	}
 
-	return err
+	return v, nil
 }
 
-func (r *VerificationRepo) GetByCode(ctx context.Context, code string) (entities.Verification, error) {
+func (r *VerificationRepo) Approve(
+	ctx context.Context,
+	tokenHash string,
+	tgUserID int64,
+	locale string,
+) (entities.Verification, error) {
+	const method = "verificationRepo.Approve"
+
 	query := `
-		SELECT 
-			uuid,
-		    code,
-		    email,
-		    name,
-		    phone,
-		    tg_user_id,
-		    status,
-		    created_at,
-		    verified_at,
-		    expires_at
-        FROM verifications WHERE code=$1`
+		UPDATE verifications
+        SET status=$1, tg_user_id=$2, verified_at=now()
+        WHERE token_hash=$3 AND status=$4 AND expires_at > now()
+        RETURNING ` + verificationColumns
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return entities.Verification{}, errorspkg.NewErrRepoFailed("BeginTx", method, err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	v, err := scanVerification(tx.QueryRow(ctx, query,
+		entities.VerifApproved,
+		tgUserID,
+		tokenHash,
+		entities.VerifPending,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return v, errorspkg.NewErrRepoNotFound("verification", "token", method)
+		}
+		return v, errorspkg.NewErrRepoFailed("QueryRow", method, err)
+	}
 
-	var v entities.Verification
-	err := r.pool.QueryRow(ctx, query, code).Scan(
+	err = saveGuest(ctx, tx, entities.Guest{
+		Name:  v.Name,
+		Email: v.Email,
+		Phone: v.Phone,
+		TgID:  tgUserID,
+
+		Locale:         locale,
+		NotifyChannels: v.NotifyChannels,
+	})
+	if err != nil {
+		if errors.Is(err, errorspkg.ErrGuestConflict) {
+			return v, err
+		}
+		return v, errorspkg.NewErrRepoFailed("Save Guest", method, err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return v, errorspkg.NewErrRepoFailed("Commit", method, err)
+	}
+
+	return v, nil
+}
+
+func (r *VerificationRepo) Expire(ctx context.Context, id string) error {
+	const method = "verificationRepo.Expire"
+
+	query := `UPDATE verifications SET status=$1 WHERE uuid=$2 AND status=$3`
+
+	_, err := r.pool.Exec(ctx, query, entities.VerifExpired, id, entities.VerifPending)
+	if err != nil {
+		return errorspkg.NewErrRepoFailed("Exec", method, err)
+	}
+
+	return nil
+}
+
+func (r *VerificationRepo) AddAttempt(ctx context.Context, id string) (int, error) {
+	const method = "verificationRepo.AddAttempt"
+
+	query := `UPDATE verifications SET attempts = attempts + 1 WHERE uuid=$1 RETURNING attempts`
+
+	var attempts int
+	err := r.pool.QueryRow(ctx, query, id).Scan(&attempts)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, errorspkg.NewErrRepoNotFound("verification", id, method)
+		}
+		return 0, errorspkg.NewErrRepoFailed("QueryRow", method, err)
+	}
+
+	return attempts, nil
+}
+func (r *VerificationRepo) ExpireOverdue(ctx context.Context) (int64, error) {
+	const method = "verificationRepo.ExpireOverdue"
+
+	query := `UPDATE verifications SET status=$1 WHERE status=$2 AND expires_at <= now()`
+
+	tag, err := r.pool.Exec(ctx, query, entities.VerifExpired, entities.VerifPending)
+	if err != nil {
+		return 0, errorspkg.NewErrRepoFailed("Exec", method, err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+func (r *VerificationRepo) DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	const method = "verificationRepo.DeleteFinishedBefore"
+
+	query := `DELETE FROM verifications WHERE status<>$1 AND created_at < $2`
+
+	tag, err := r.pool.Exec(ctx, query, entities.VerifPending, before)
+	if err != nil {
+		return 0, errorspkg.NewErrRepoFailed("Exec", method, err)
+	}
+
+	lockouts := `
+		DELETE FROM verification_lockouts
+		WHERE updated_at < $1 AND (locked_until IS NULL OR locked_until < now())`
+
+	_, err = r.pool.Exec(ctx, lockouts, before)
+	if err != nil {
+		return 0, errorspkg.NewErrRepoFailed("Exec", method, err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+func (r *VerificationRepo) Funnel(ctx context.Context, since time.Time) (entities.VerificationFunnel, error) {
+	const method = "verificationRepo.Funnel"
+
+	query := `
+		SELECT
+			count(*),
+			count(*) FILTER (WHERE status=$2),
+			count(*) FILTER (WHERE status=$3),
+			count(*) FILTER (WHERE status=$4)
+		FROM verifications
+		WHERE created_at >= $1`
+
+	var funnel entities.VerificationFunnel
+	err := r.pool.QueryRow(ctx, query, since,
+		entities.VerifPending,
+		entities.VerifApproved,
+		entities.VerifExpired,
+	).Scan(&funnel.Created, &funnel.Pending, &funnel.Approved, &funnel.Expired)
+	if err != nil {
+		return funnel, errorspkg.NewErrRepoFailed("QueryRow", method, err)
+	}
+
+	return funnel, nil
+}
+
+func (r *VerificationRepo) GetLockout(ctx context.Context, tgID int64) (entities.VerificationLockout, error) {
+	const method = "verificationRepo.GetLockout"
+
+	query := `
+		SELECT failures, lockouts, locked_until
+		FROM verification_lockouts
+		WHERE tg_user_id=$1`
+
+	lockout := entities.VerificationLockout{TgID: tgID}
+	err := r.pool.QueryRow(ctx, query, tgID).Scan(&lockout.Failures, &lockout.Lockouts, &lockout.LockedUntil)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return lockout, nil
+		}
+		return lockout, errorspkg.NewErrRepoFailed("QueryRow", method, err)
+	}
+
+	return lockout, nil
+}
+
+func (r *VerificationRepo) AddFailure(
+	ctx context.Context,
+	tgID int64,
+	staleBefore time.Time,
+) (entities.VerificationLockout, error) {
+	const method = "verificationRepo.AddFailure"
+
+	query := `
+		INSERT INTO verification_lockouts (tg_user_id, failures)
+		VALUES ($1, 1)
+		ON CONFLICT (tg_user_id) DO UPDATE SET
+			failures = CASE
+				WHEN verification_lockouts.updated_at < $2 THEN 1
+				ELSE verification_lockouts.failures + 1
+			END,
+			lockouts = CASE
+				WHEN verification_lockouts.updated_at < $2 THEN 0
+				ELSE verification_lockouts.lockouts
+			END,
+			updated_at = now()
+		RETURNING failures, lockouts, locked_until`
+
+	lockout := entities.VerificationLockout{TgID: tgID}
+	err := r.pool.QueryRow(ctx, query, tgID, staleBefore).Scan(
+		&lockout.Failures,
+		&lockout.Lockouts,
+		&lockout.LockedUntil,
+	)
+	if err != nil {
+		return lockout, errorspkg.NewErrRepoFailed("QueryRow", method, err)
+	}
+
+	return lockout, nil
+}
+
+// Lock starts the next lockout, the failures counter begins again after it.
+func (r *VerificationRepo) Lock(ctx context.Context, tgID int64, until time.Time) error {
+	const method = "verificationRepo.Lock"
+
+	query := `
+		UPDATE verification_lockouts
+		SET failures = 0, lockouts = lockouts + 1, locked_until = $2, updated_at = now()
+		WHERE tg_user_id=$1`
+
+	tag, err := r.pool.Exec(ctx, query, tgID, until)
+	if err != nil {
+		return errorspkg.NewErrRepoFailed("Exec", method, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errorspkg.NewErrRepoNotFound("verification lockout", strconv.FormatInt(tgID, 10), method)
+	}
+
+	return nil
+}
+
+func (r *VerificationRepo) ResetLockout(ctx context.Context, tgID int64) error {
+	const method = "verificationRepo.ResetLockout"
+
+	_, err := r.pool.Exec(ctx, `DELETE FROM verification_lockouts WHERE tg_user_id=$1`, tgID)
+	if err != nil {
+		return errorspkg.NewErrRepoFailed("Exec", method, err)
+	}
+
+	return nil
+}
+
+func scanVerification(row pgx.Row) (entities.Verification, error) {
+	var (
+		v         entities.Verification
+		tokenHash *string
+		channels  []string
+	)
+	err := row.Scan(
 		&v.ID,
-		&v.Code,
+		&tokenHash,
 		&v.Email,
 		&v.Name,
 		&v.Phone,
@@ -70,22 +351,13 @@ func (r *VerificationRepo) GetByCode(ctx context.Context, code string) (entities
 		&v.CreatedAt,
 		&v.VerifiedAt,
 		&v.ExpiresAt,
+		&channels,
+		&v.Attempts,
 	)
+	if tokenHash != nil {
+		v.TokenHash = *tokenHash
+	}
+	v.NotifyChannels = stringsToChannels(channels)
 
 	return v, err
 }
-
-func (r *VerificationRepo) Approve(ctx context.Context, id string, tgUserID int64) error {
-	query := `
-		UPDATE verifications
-        SET status=$1, tg_user_id=$2, verified_at=now()
-        WHERE uuid=$3`
-
-	_, err := r.pool.Exec(ctx, query,
-		entities.VerifApproved,
-		tgUserID,
-		id,
-	)
-
-	return err
-}
@@ -3,17 +3,27 @@ package repository
 import (
 	"context"
 	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/google/uuid"
 )
 
+// CancelNotifications builds the outbox messages for a cancelled reservation; they are stored
+// in the same transaction as the cancellation.
+type CancelNotifications func(msg entities.ReservationCancelledMessage) ([]entities.OutboxMessage, error)
+
 type IReservations interface {
-	GetAvailableHouses(ctx context.Context, req entities.GetAvailableHouses) ([]int, error)
+	GetAvailableHouses(ctx context.Context, req entities.GetAvailableHouses) ([]entities.AvailableHouse, error)
+	GetOccupancy(ctx context.Context, req entities.GetAvailableHouses) ([]entities.HouseOccupancy, error)
 	CheckAvailability(ctx context.Context, req entities.CheckAvailability) (bool, error)
 	GetPrice(ctx context.Context, houseID int, extras []entities.ReservationExtra, bathhouse []entities.BathhouseReservation) (entities.GetPrice, error)
-	Create(ctx context.Context, reservation entities.Reservation) error
+	Create(ctx context.Context, reservation entities.Reservation, notifications []entities.OutboxMessage) (uuid.UUID, error)
 	GetDetailsByUUID(ctx context.Context, telegramID int64, uuid string) (entities.ReservationMessage, error)
 	GetByTelegramID(ctx context.Context, telegramID int64) ([]entities.ReservationMessage, error)
-	Cancel(ctx context.Context, userTgId int64, reservationUUID string) error
+	Cancel(ctx context.Context, userTgId int64, reservationUUID string, notify CancelNotifications) error
+	CancelByAdmin(ctx context.Context, reservationUUID string, notify CancelNotifications) error
+	SetStatus(ctx context.Context, reservationUUID uuid.UUID, from []string, status string) error
 	GetAllConfirmed(ctx context.Context) ([]entities.ReservationUpdateStatus, error)
 	UpdateStatuses(ctx context.Context, reservations []entities.ReservationUpdateStatus) error
 	GetAllForReminder(ctx context.Context) ([]entities.ReservationReminderNotification, error)
+	ClaimReminder(ctx context.Context, reservationUUID uuid.UUID, stage, status string) (bool, error)
+	ReleaseReminder(ctx context.Context, reservationUUID uuid.UUID, stage string) error
 }
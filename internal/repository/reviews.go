@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/google/uuid"
+	"time"
+)
+
+type IReviews interface {
+	GetForFeedback(ctx context.Context, checkedOutBefore, checkedOutAfter time.Time) ([]entities.FeedbackRequest, error)
+	MarkFeedbackRequested(ctx context.Context, reservationUUID uuid.UUID) error
+	Rate(ctx context.Context, tgID int64, reservationUUID uuid.UUID, rating int) error
+	SetComment(ctx context.Context, tgID int64, comment string) error
+	SkipComment(ctx context.Context, tgID int64, reservationUUID uuid.UUID) error
+	GetAll(ctx context.Context, status entities.ReviewStatus) ([]entities.Review, error)
+	GetApproved(ctx context.Context) ([]entities.Review, error)
+	SetStatus(ctx context.Context, id int, status entities.ReviewStatus) error
+}
@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+)
+
+type ITemplates interface {
+	GetAll(ctx context.Context) ([]entities.MessageTemplate, error)
+	Upsert(ctx context.Context, tmpl entities.MessageTemplate) error
+	Delete(ctx context.Context, key, locale string) error
+}
@@ -3,10 +3,27 @@ package repository
 import (
 	"context"
 	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"time"
 )
 
 type IVerification interface {
-	Create(ctx context.Context, v entities.Verification) error
-	GetByCode(ctx context.Context, code string) (entities.Verification, error)
-	Approve(ctx context.Context, id string, tgUserID int64) error
+	Create(ctx context.Context, v entities.Verification) (string, error)
+	GetByID(ctx context.Context, id string) (entities.Verification, error)
+	GetPendingByToken(ctx context.Context, tokenHash string) (entities.Verification, error)
+	// Approve binds tgUserID to the pending, unexpired verification and saves the guest
+	// from it in one transaction. On errorspkg.ErrGuestConflict nothing is stored and the
+	// verification is returned for the report.
+	Approve(ctx context.Context, tokenHash string, tgUserID int64, locale string) (entities.Verification, error)
+	Expire(ctx context.Context, id string) error
+	AddAttempt(ctx context.Context, id string) (int, error)
+	ExpireOverdue(ctx context.Context) (int64, error)
+	// DeleteFinishedBefore removes approved and expired verifications and idle lockouts.
+	DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error)
+	Funnel(ctx context.Context, since time.Time) (entities.VerificationFunnel, error)
+
+	GetLockout(ctx context.Context, tgID int64) (entities.VerificationLockout, error)
+	// AddFailure counts a wrong code, counters untouched since staleBefore start over.
+	AddFailure(ctx context.Context, tgID int64, staleBefore time.Time) (entities.VerificationLockout, error)
+	Lock(ctx context.Context, tgID int64, until time.Time) error
+	ResetLockout(ctx context.Context, tgID int64) error
 }
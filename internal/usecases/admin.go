@@ -0,0 +1,215 @@
+package usecases
+
+import (
+	"context"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/calyrexx/QuietGrooveBackend/internal/repository"
+	"github.com/calyrexx/zeroslog"
+	"github.com/google/uuid"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	reservationNoShow  = "no_show"
+	minPhoneDigits     = 5
+	adminPhoneSuffixes = 10
+)
+
+type (
+	AdminCanceller interface {
+		CancelByAdmin(ctx context.Context, uuid string) error
+	}
+
+	AdminDependencies struct {
+		Repo            repository.IAdmin
+		ReservationRepo repository.IReservations
+		CalendarRepo    repository.ICalendar
+		HouseRepo       repository.IHouses
+		Canceller       AdminCanceller
+		Logger          *slog.Logger
+	}
+
+	// Admin backs the Telegram admin console and the management of the admin list.
+	Admin struct {
+		repo            repository.IAdmin
+		reservationRepo repository.IReservations
+		calendarRepo    repository.ICalendar
+		houseRepo       repository.IHouses
+		canceller       AdminCanceller
+		logger          *slog.Logger
+	}
+)
+
+func NewAdmin(d *AdminDependencies) (*Admin, error) {
+	const method = "usecases.NewAdmin"
+	if d == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "whole", "nil")
+	}
+	if d.Repo == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "Repo", "nil")
+	}
+	if d.ReservationRepo == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "ReservationRepo", "nil")
+	}
+	if d.CalendarRepo == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "CalendarRepo", "nil")
+	}
+	if d.HouseRepo == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "HouseRepo", "nil")
+	}
+	if d.Canceller == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "Canceller", "nil")
+	}
+
+	logger := d.Logger.With(zeroslog.UsecaseKey, "Admin")
+
+	return &Admin{
+		repo:            d.Repo,
+		reservationRepo: d.ReservationRepo,
+		calendarRepo:    d.CalendarRepo,
+		houseRepo:       d.HouseRepo,
+		canceller:       d.Canceller,
+		logger:          logger,
+	}, nil
+}
+
+// IsAdmin fails closed: a lookup error means no access.
+func (u *Admin) IsAdmin(ctx context.Context, tgID int64) bool {
+	ok, err := u.repo.IsAdmin(ctx, tgID)
+	if err != nil {
+		u.logger.Error("check admin", zeroslog.ErrorKey, err, "tgID", tgID)
+		return false
+	}
+	return ok
+}
+
+func (u *Admin) GetAdmins(ctx context.Context) ([]entities.BotAdmin, error) {
+	return u.repo.GetAdmins(ctx)
+}
+
+func (u *Admin) AddAdmin(ctx context.Context, admin entities.BotAdmin) error {
+	if admin.TgID <= 0 {
+		return errorspkg.ErrInvalidTgID
+	}
+	return u.repo.AddAdmin(ctx, admin)
+}
+
+func (u *Admin) DeleteAdmin(ctx context.Context, tgID int64) error {
+	return u.repo.DeleteAdmin(ctx, tgID)
+}
+
+func (u *Admin) Agenda(ctx context.Context, day time.Time) (entities.DayAgenda, error) {
+	return u.repo.GetAgenda(ctx, startOfDay(day))
+}
+
+func (u *Admin) GetReservation(ctx context.Context, reservationUUID string) (entities.AdminReservation, error) {
+	id, err := uuid.Parse(reservationUUID)
+	if err != nil {
+		return entities.AdminReservation{}, err
+	}
+	return u.repo.GetReservation(ctx, id)
+}
+
+// Find looks a reservation up by its UUID or by the guest's phone number.
+func (u *Admin) Find(ctx context.Context, query string) ([]entities.AdminReservation, error) {
+	if id, err := uuid.Parse(strings.TrimSpace(query)); err == nil {
+		res, getErr := u.repo.GetReservation(ctx, id)
+		if getErr != nil {
+			return nil, getErr
+		}
+		return []entities.AdminReservation{res}, nil
+	}
+
+	digits := strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, query)
+	if len(digits) < minPhoneDigits {
+		return nil, errorspkg.ErrPhoneQueryTooShort
+	}
+	if len(digits) > adminPhoneSuffixes {
+		digits = digits[len(digits)-adminPhoneSuffixes:]
+	}
+
+	return u.repo.FindReservationsByPhone(ctx, digits)
+}
+
+func (u *Admin) Cancel(ctx context.Context, reservationUUID string) error {
+	return u.canceller.CancelByAdmin(ctx, reservationUUID)
+}
+
+func (u *Admin) CheckIn(ctx context.Context, reservationUUID string) error {
+	return u.setStatus(ctx, reservationUUID, []string{reservationConfirmed}, reservationCheckedIn)
+}
+
+func (u *Admin) NoShow(ctx context.Context, reservationUUID string) error {
+	return u.setStatus(ctx, reservationUUID, []string{reservationConfirmed, reservationCheckedIn}, reservationNoShow)
+}
+
+// Block closes the house for [from, to) unless it is booked or already blocked then.
+// house is the house ID or its name.
+func (u *Admin) Block(ctx context.Context, house string, from, to time.Time, reason string) (entities.Blackout, error) {
+	if !to.After(from) {
+		return entities.Blackout{}, errorspkg.ErrInvalidPeriod
+	}
+
+	h, err := u.findHouse(ctx, house)
+	if err != nil {
+		return entities.Blackout{}, err
+	}
+
+	available, err := u.reservationRepo.CheckAvailability(ctx, entities.CheckAvailability{
+		HouseId:  h.ID,
+		CheckIn:  from,
+		CheckOut: to,
+	})
+	if err != nil {
+		return entities.Blackout{}, err
+	}
+	if !available {
+		return entities.Blackout{}, errorspkg.NewErrHouseUnavailable(h.ID, from, to)
+	}
+
+	blackout := entities.Blackout{
+		HouseID:   h.ID,
+		HouseName: h.Name,
+		CheckIn:   from,
+		CheckOut:  to,
+		Reason:    reason,
+	}
+	if blackout.ID, err = u.calendarRepo.AddBlackout(ctx, blackout); err != nil {
+		return entities.Blackout{}, err
+	}
+
+	return blackout, nil
+}
+
+func (u *Admin) setStatus(ctx context.Context, reservationUUID string, from []string, status string) error {
+	id, err := uuid.Parse(reservationUUID)
+	if err != nil {
+		return err
+	}
+	return u.reservationRepo.SetStatus(ctx, id, from, status)
+}
+
+func (u *Admin) findHouse(ctx context.Context, house string) (entities.House, error) {
+	houses, err := u.houseRepo.GetAll(ctx)
+	if err != nil {
+		return entities.House{}, err
+	}
+
+	id, idErr := strconv.Atoi(house)
+	for _, h := range houses {
+		if (idErr == nil && h.ID == id) || strings.EqualFold(h.Name, house) {
+			return h, nil
+		}
+	}
+	return entities.House{}, errorspkg.ErrUnknownHouse
+}
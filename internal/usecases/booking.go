@@ -0,0 +1,423 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"github.com/calyrexx/QuietGrooveBackend/internal/configuration"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/calyrexx/QuietGrooveBackend/internal/repository"
+	"github.com/calyrexx/zeroslog"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+type (
+	BookingReservations interface {
+		GetAvailableHouses(ctx context.Context, req entities.GetAvailableHouses) ([]GetAvailableHousesResponse, error)
+		CreateReservation(ctx context.Context, req CreateReservationRequest) (entities.Reservation, error)
+	}
+
+	BookingDependencies struct {
+		Repo         repository.IBookingSessions
+		GuestRepo    repository.IGuests
+		ExtrasRepo   repository.IExtras
+		Reservations BookingReservations
+		Loyalty      ReservationLoyalty
+		Config       *configuration.Booking
+		Logger       *slog.Logger
+	}
+
+	// Booking drives the booking flow of the Telegram bot. Every step is saved, so a
+	// restart of the service doesn't interrupt the guest.
+	Booking struct {
+		repo         repository.IBookingSessions
+		guestRepo    repository.IGuests
+		extrasRepo   repository.IExtras
+		reservations BookingReservations
+		loyalty      ReservationLoyalty
+		config       *configuration.Booking
+		logger       *slog.Logger
+	}
+)
+
+func NewBooking(d *BookingDependencies) (*Booking, error) {
+	const method = "usecases.NewBooking"
+	if d == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "whole", "nil")
+	}
+	if d.Repo == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "Repo", "nil")
+	}
+	if d.GuestRepo == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "GuestRepo", "nil")
+	}
+	if d.ExtrasRepo == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "ExtrasRepo", "nil")
+	}
+	if d.Reservations == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "Reservations", "nil")
+	}
+	if d.Loyalty == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "Loyalty", "nil")
+	}
+	if d.Config == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "Config", "nil")
+	}
+	if d.Config.MaxGuests <= 0 {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "Config.MaxGuests", "must be positive")
+	}
+
+	logger := d.Logger.With(zeroslog.UsecaseKey, "Booking")
+
+	return &Booking{
+		repo:         d.Repo,
+		guestRepo:    d.GuestRepo,
+		extrasRepo:   d.ExtrasRepo,
+		reservations: d.Reservations,
+		loyalty:      d.Loyalty,
+		config:       d.Config,
+		logger:       logger,
+	}, nil
+}
+
+func (u *Booking) MaxGuests() int {
+	return u.config.MaxGuests
+}
+
+// Start begins a new booking for the chat, dropping an unfinished one. Only guests who
+// verified their Telegram account can book in the bot.
+func (u *Booking) Start(ctx context.Context, chatID, tgID int64) (entities.BookingSession, error) {
+	if _, err := u.guestRepo.GetByTgID(ctx, tgID); err != nil {
+		return entities.BookingSession{}, err
+	}
+
+	today := startOfDay(time.Now())
+	session := entities.BookingSession{
+		ChatID: chatID,
+		Step:   entities.BookingCheckIn,
+		Month:  today.AddDate(0, 0, 1-today.Day()),
+	}
+	return session, u.repo.Save(ctx, session)
+}
+
+func (u *Booking) Session(ctx context.Context, chatID int64) (entities.BookingSession, error) {
+	return u.repo.Get(ctx, chatID, time.Now().Add(-u.config.SessionTTL))
+}
+
+func (u *Booking) Cancel(ctx context.Context, chatID int64) error {
+	return u.repo.Delete(ctx, chatID)
+}
+
+// ShowMonth moves the date picker, months before the current one are not shown.
+func (u *Booking) ShowMonth(ctx context.Context, s entities.BookingSession, month time.Time) (entities.BookingSession, error) {
+	today := startOfDay(time.Now())
+	if month.Before(today.AddDate(0, 0, 1-today.Day())) {
+		return s, errorspkg.ErrInvalidPeriod
+	}
+	s.Month = month
+	return s, u.repo.Save(ctx, s)
+}
+
+// PickDate sets the check-in date and then the check-out date.
+func (u *Booking) PickDate(ctx context.Context, s entities.BookingSession, day time.Time) (entities.BookingSession, error) {
+	if day.Before(startOfDay(time.Now())) {
+		return s, errorspkg.ErrInvalidPeriod
+	}
+
+	switch s.Step {
+	case entities.BookingCheckIn:
+		s.CheckIn = day
+		s.Step = entities.BookingCheckOut
+	case entities.BookingCheckOut:
+		if !day.After(s.CheckIn) {
+			return s, errorspkg.ErrInvalidPeriod
+		}
+		s.CheckOut = day
+		s.Step = entities.BookingGuests
+	default:
+		return s, errorspkg.ErrBookingStep
+	}
+	return s, u.repo.Save(ctx, s)
+}
+
+func (u *Booking) PickGuests(ctx context.Context, s entities.BookingSession, count int) (entities.BookingSession, error) {
+	if s.Step != entities.BookingGuests {
+		return s, errorspkg.ErrBookingStep
+	}
+	if count < 1 || count > u.config.MaxGuests {
+		return s, errorspkg.ErrBookingStep
+	}
+	s.GuestsCount = count
+	s.Step = entities.BookingHouse
+	return s, u.repo.Save(ctx, s)
+}
+
+func (u *Booking) Houses(ctx context.Context, s entities.BookingSession) ([]GetAvailableHousesResponse, error) {
+	return u.reservations.GetAvailableHouses(ctx, entities.GetAvailableHouses{
+		CheckIn:     s.CheckIn,
+		CheckOut:    s.CheckOut,
+		GuestsCount: s.GuestsCount,
+	})
+}
+
+// House returns the house if it is still free for the session dates.
+func (u *Booking) House(ctx context.Context, s entities.BookingSession, houseID int) (GetAvailableHousesResponse, error) {
+	houses, err := u.Houses(ctx, s)
+	if err != nil {
+		return GetAvailableHousesResponse{}, err
+	}
+	for _, h := range houses {
+		if h.ID == houseID {
+			return h, nil
+		}
+	}
+	return GetAvailableHousesResponse{}, errorspkg.NewErrHouseUnavailable(houseID, s.CheckIn, s.CheckOut)
+}
+
+func (u *Booking) PickHouse(
+	ctx context.Context,
+	s entities.BookingSession,
+	houseID int,
+) (entities.BookingSession, GetAvailableHousesResponse, error) {
+	if s.Step != entities.BookingHouse {
+		return s, GetAvailableHousesResponse{}, errorspkg.ErrBookingStep
+	}
+
+	house, err := u.House(ctx, s, houseID)
+	if err != nil {
+		return s, house, err
+	}
+
+	s.HouseID = house.ID
+	s.HouseName = house.Name
+	s.StayPrice = house.TotalPrice
+	s.Extras = nil
+	s.Bathhouse = nil
+	s.Step = entities.BookingExtras
+	return s, house, u.repo.Save(ctx, s)
+}
+
+func (u *Booking) Extras(ctx context.Context) ([]entities.Extra, error) {
+	return u.extrasRepo.GetAll(ctx)
+}
+
+func (u *Booking) ToggleExtra(ctx context.Context, s entities.BookingSession, extraID int) (entities.BookingSession, error) {
+	if s.Step != entities.BookingExtras {
+		return s, errorspkg.ErrBookingStep
+	}
+
+	if i := slices.Index(s.Extras, extraID); i >= 0 {
+		s.Extras = slices.Delete(s.Extras, i, i+1)
+	} else {
+		extras, err := u.extrasRepo.GetAll(ctx)
+		if err != nil {
+			return s, err
+		}
+		if !slices.ContainsFunc(extras, func(e entities.Extra) bool { return e.ID == extraID }) {
+			return s, errorspkg.ErrBookingStep
+		}
+		s.Extras = append(s.Extras, extraID)
+	}
+	return s, u.repo.Save(ctx, s)
+}
+
+// ToggleBathhouse adds or removes the bathhouse on the date; added reports whether it was added.
+func (u *Booking) ToggleBathhouse(
+	ctx context.Context,
+	s entities.BookingSession,
+	typeID int,
+	date string,
+) (session entities.BookingSession, added bool, err error) {
+	if s.Step != entities.BookingBathhouse {
+		return s, false, errorspkg.ErrBookingStep
+	}
+
+	if i := bookedBathhouse(s, typeID, date); i >= 0 {
+		s.Bathhouse = slices.Delete(s.Bathhouse, i, i+1)
+		return s, false, u.repo.Save(ctx, s)
+	}
+
+	house, err := u.House(ctx, s, s.HouseID)
+	if err != nil {
+		return s, false, err
+	}
+	slot, ok := bathhouseSlot(house, typeID, date)
+	if !ok {
+		return s, false, errorspkg.ErrBookingStep
+	}
+
+	s.Bathhouse = append(s.Bathhouse, entities.BathhouseReservation{
+		TypeID:   typeID,
+		Date:     date,
+		TimeFrom: slot.TimeFrom,
+		TimeTo:   slot.TimeTo,
+	})
+	if bh := findBathhouse(house, typeID); len(bh.FillOption) > 0 {
+		s.Step = entities.BookingFillOption
+	}
+	return s, true, u.repo.Save(ctx, s)
+}
+
+func (u *Booking) PickFillOption(
+	ctx context.Context,
+	s entities.BookingSession,
+	typeID int,
+	date string,
+	optionID int,
+) (entities.BookingSession, error) {
+	i := bookedBathhouse(s, typeID, date)
+	if s.Step != entities.BookingFillOption || i < 0 {
+		return s, errorspkg.ErrBookingStep
+	}
+
+	house, err := u.House(ctx, s, s.HouseID)
+	if err != nil {
+		return s, err
+	}
+	bh := findBathhouse(house, typeID)
+	if !slices.ContainsFunc(bh.FillOption, func(o BathhouseFillOption) bool { return o.ID == optionID }) {
+		return s, errorspkg.ErrBookingStep
+	}
+
+	s.Bathhouse[i].FillOptionID = optionID
+	s.Step = entities.BookingBathhouse
+	return s, u.repo.Save(ctx, s)
+}
+
+// Next finishes the extras or bathhouse step.
+func (u *Booking) Next(ctx context.Context, s entities.BookingSession) (entities.BookingSession, error) {
+	switch s.Step {
+	case entities.BookingExtras:
+		s.Step = entities.BookingBathhouse
+	case entities.BookingBathhouse:
+		s.Step = entities.BookingConfirm
+	default:
+		return s, errorspkg.ErrBookingStep
+	}
+	return s, u.repo.Save(ctx, s)
+}
+
+func (u *Booking) Summary(ctx context.Context, s entities.BookingSession) (entities.BookingSummary, error) {
+	summary := entities.BookingSummary{
+		HouseName:   s.HouseName,
+		CheckIn:     s.CheckIn,
+		CheckOut:    s.CheckOut,
+		GuestsCount: s.GuestsCount,
+		StayPrice:   s.StayPrice,
+	}
+
+	// The bot runs in private chats, so the chat is the guest's Telegram account.
+	guest, err := u.guestRepo.GetByTgID(ctx, s.ChatID)
+	if err == nil {
+		summary.Discount, err = u.loyalty.Discount(ctx, guest.UUID)
+	}
+	var notFound *errorspkg.ErrRepoNotFound
+	if err != nil && !errors.As(err, &notFound) {
+		return summary, err
+	}
+
+	if len(s.Extras) > 0 {
+		extras, err := u.extrasRepo.GetAll(ctx)
+		if err != nil {
+			return summary, err
+		}
+		for _, e := range extras {
+			if slices.Contains(s.Extras, e.ID) {
+				summary.Extras = append(summary.Extras, e)
+			}
+		}
+	}
+
+	if len(s.Bathhouse) > 0 {
+		house, err := u.House(ctx, s, s.HouseID)
+		if err != nil {
+			return summary, err
+		}
+		for _, b := range s.Bathhouse {
+			bh := findBathhouse(house, b.TypeID)
+			msg := entities.BathhouseMessage{
+				Name:     bh.Name,
+				Date:     b.Date,
+				TimeFrom: b.TimeFrom,
+				TimeTo:   b.TimeTo,
+			}
+			for _, o := range bh.FillOption {
+				if o.ID == b.FillOptionID {
+					msg.FillOption = &o.Name
+				}
+			}
+			summary.Bathhouse = append(summary.Bathhouse, msg)
+		}
+	}
+
+	return summary, nil
+}
+
+// Book creates the reservation for the guest linked to tgID and closes the session.
+func (u *Booking) Book(ctx context.Context, s entities.BookingSession, tgID int64) (entities.Reservation, error) {
+	if s.Step != entities.BookingConfirm {
+		return entities.Reservation{}, errorspkg.ErrBookingStep
+	}
+
+	guest, err := u.guestRepo.GetByTgID(ctx, tgID)
+	if err != nil {
+		return entities.Reservation{}, err
+	}
+
+	extras := make([]entities.ReservationExtra, 0, len(s.Extras))
+	for _, id := range s.Extras {
+		extras = append(extras, entities.ReservationExtra{ExtraID: id, Quantity: 1})
+	}
+
+	reservation, err := u.reservations.CreateReservation(ctx, CreateReservationRequest{
+		HouseID: s.HouseID,
+		Guest: entities.Guest{
+			Name:  guest.Name,
+			Email: guest.Email,
+			Phone: guest.Phone,
+		},
+		CheckIn:     s.CheckIn,
+		CheckOut:    s.CheckOut,
+		GuestsCount: s.GuestsCount,
+		Extras:      extras,
+		Bathhouse:   s.Bathhouse,
+	})
+	if err != nil {
+		return reservation, err
+	}
+
+	if err = u.repo.Delete(ctx, s.ChatID); err != nil {
+		u.logger.Error("delete booking session", zeroslog.ErrorKey, err, "chatID", s.ChatID)
+	}
+	return reservation, nil
+}
+
+func bookedBathhouse(s entities.BookingSession, typeID int, date string) int {
+	return slices.IndexFunc(s.Bathhouse, func(b entities.BathhouseReservation) bool {
+		return b.TypeID == typeID && b.Date == date
+	})
+}
+
+func findBathhouse(house GetAvailableHousesResponse, typeID int) BathhouseSlots {
+	for _, bh := range house.Bathhouses {
+		if bh.TypeID == typeID {
+			return bh
+		}
+	}
+	return BathhouseSlots{}
+}
+
+func bathhouseSlot(house GetAvailableHousesResponse, typeID int, date string) (BathhouseTimeSlots, bool) {
+	for _, day := range findBathhouse(house, typeID).Slots {
+		if day.Date == date && len(day.Time) > 0 {
+			return day.Time[0], true
+		}
+	}
+	return BathhouseTimeSlots{}, false
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
@@ -0,0 +1,230 @@
+package usecases
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/ical"
+	"github.com/calyrexx/QuietGrooveBackend/internal/repository"
+	"github.com/calyrexx/zeroslog"
+	"log/slog"
+	"strconv"
+	"time"
+)
+
+const (
+	calendarProdID   = "-//QuietGrove//Houses Calendar//RU"
+	calendarUIDHost  = "quietgrove"
+	busySummary      = "Занято"
+	blackoutSummary  = "Недоступно"
+	calendarNameTmpl = "QuietGrove — %s"
+)
+
+type (
+	CalendarFetcher interface {
+		Fetch(ctx context.Context, url string) ([]entities.ImportedEvent, error)
+	}
+
+	CalendarNotifier interface {
+		ImportedEventOverlaps(msg entities.CalendarConflictMessage) error
+	}
+
+	CalendarDependencies struct {
+		Repo      repository.ICalendar
+		HouseRepo repository.IHouses
+		Fetcher   CalendarFetcher
+		Notifier  CalendarNotifier
+		Logger    *slog.Logger
+	}
+
+	Calendar struct {
+		repo      repository.ICalendar
+		houseRepo repository.IHouses
+		fetcher   CalendarFetcher
+		notifier  CalendarNotifier
+		logger    *slog.Logger
+	}
+)
+
+func NewCalendar(d *CalendarDependencies) (*Calendar, error) {
+	const method = "usecases.NewCalendar"
+	if d == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "whole", "nil")
+	}
+	if d.Repo == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "Repo", "nil")
+	}
+	if d.HouseRepo == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "HouseRepo", "nil")
+	}
+	if d.Fetcher == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "Fetcher", "nil")
+	}
+	if d.Notifier == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "Notifier", "nil")
+	}
+
+	logger := d.Logger.With(zeroslog.UsecaseKey, "Calendar")
+
+	return &Calendar{
+		repo:      d.Repo,
+		houseRepo: d.HouseRepo,
+		fetcher:   d.Fetcher,
+		notifier:  d.Notifier,
+		logger:    logger,
+	}, nil
+}
+
+// Export builds an iCal feed of busy dates for the house. Only dates are exported, never guest data.
+func (u *Calendar) Export(ctx context.Context, houseID int, token string) ([]byte, error) {
+	expected, err := u.repo.GetToken(ctx, houseID)
+	if err != nil {
+		return nil, err
+	}
+	if token == "" || subtle.ConstantTimeCompare([]byte(expected), []byte(token)) != 1 {
+		return nil, errorspkg.ErrInvalidCalendarToken
+	}
+
+	house, err := u.houseRepo.GetOne(ctx, houseID)
+	if err != nil {
+		return nil, err
+	}
+
+	periods, err := u.repo.GetBusyPeriods(ctx, houseID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	events := make([]ical.Event, 0, len(periods))
+	for _, p := range periods {
+		summary := busySummary
+		if p.Kind == entities.BusyBlackout {
+			summary = blackoutSummary
+		}
+		events = append(events, ical.Event{
+			UID:     fmt.Sprintf("%s@%s", p.ID, calendarUIDHost),
+			Summary: summary,
+			Start:   p.CheckIn,
+			End:     p.CheckOut,
+			Stamp:   now,
+		})
+	}
+
+	cal := ical.Calendar{
+		ProdID: calendarProdID,
+		Name:   fmt.Sprintf(calendarNameTmpl, house.Name),
+		Events: events,
+	}
+
+	return cal.Encode(), nil
+}
+
+func (u *Calendar) RegenerateToken(ctx context.Context, houseID int) (string, error) {
+	return u.repo.RegenerateToken(ctx, houseID)
+}
+
+func (u *Calendar) GetSources(ctx context.Context, houseID int) ([]entities.CalendarSource, error) {
+	return u.repo.GetSourcesByHouse(ctx, houseID)
+}
+
+func (u *Calendar) AddSource(ctx context.Context, source entities.CalendarSource) (entities.CalendarSource, error) {
+	if _, err := u.houseRepo.GetOne(ctx, source.HouseID); err != nil {
+		return entities.CalendarSource{}, err
+	}
+
+	id, err := u.repo.AddSource(ctx, source)
+	if err != nil {
+		return entities.CalendarSource{}, err
+	}
+	source.ID = id
+
+	return source, nil
+}
+
+func (u *Calendar) DeleteSource(ctx context.Context, houseID, sourceID int) error {
+	return u.repo.DeleteSource(ctx, houseID, sourceID)
+}
+
+// SyncImported pulls every external calendar into blackouts. A broken feed doesn't stop the others.
+func (u *Calendar) SyncImported(ctx context.Context) error {
+	const method = "SyncImported"
+	u.logger.Info("starting sync external calendars", "method", method)
+	timeNow := time.Now()
+
+	sources, err := u.repo.GetSources(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, src := range sources {
+		if err = u.syncSource(ctx, src); err != nil {
+			u.logger.Error("sync calendar source", zeroslog.ErrorKey, err, "source", src.ID, "house", src.HouseID)
+			errs = append(errs, fmt.Errorf("source [%d]: %w", src.ID, err))
+		}
+	}
+
+	if len(sources) > 0 {
+		u.logger.Info(fmt.Sprintf("finished sync external calendars in [%s]", time.Since(timeNow)),
+			"method", method, "sources", len(sources), "failed", len(errs))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (u *Calendar) syncSource(ctx context.Context, src entities.CalendarSource) error {
+	events, err := u.fetcher.Fetch(ctx, src.URL)
+	if err != nil {
+		return err
+	}
+
+	today := time.Now().Truncate(24 * time.Hour)
+	byUID := make(map[string]entities.ImportedEvent, len(events))
+	for _, e := range events {
+		if e.CheckOut.Before(today) {
+			continue
+		}
+		byUID[e.UID] = e
+	}
+
+	actual := make([]entities.ImportedEvent, 0, len(byUID))
+	for _, e := range byUID {
+		actual = append(actual, e)
+	}
+
+	changed, err := u.repo.SyncImported(ctx, src, actual)
+	if err != nil {
+		return err
+	}
+
+	for _, b := range changed {
+		conflicts, repoErr := u.repo.GetOverlappingReservations(ctx, b.HouseID, b.CheckIn, b.CheckOut)
+		if repoErr != nil {
+			return repoErr
+		}
+		if len(conflicts) == 0 {
+			continue
+		}
+
+		houseName := strconv.Itoa(b.HouseID)
+		if house, houseErr := u.houseRepo.GetOne(ctx, b.HouseID); houseErr == nil {
+			houseName = house.Name
+		}
+
+		if errSend := u.notifier.ImportedEventOverlaps(entities.CalendarConflictMessage{
+			SourceName:   src.Name,
+			HouseName:    houseName,
+			CheckIn:      b.CheckIn,
+			CheckOut:     b.CheckOut,
+			Reservations: conflicts,
+		}); errSend != nil {
+			u.logger.Error("telegram notify", zeroslog.ErrorKey, errSend)
+		}
+	}
+
+	return nil
+}
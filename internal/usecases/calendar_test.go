@@ -0,0 +1,216 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/integrations/calendars"
+	"github.com/calyrexx/QuietGrooveBackend/internal/repository"
+	"github.com/google/uuid"
+)
+
+// fakeCalendarRepo keeps imported blackouts by source and UID the way CalendarRepo.SyncImported
+// does: new and moved events are returned as changed, missing ones are deleted.
+type fakeCalendarRepo struct {
+	repository.ICalendar
+	sources      []entities.CalendarSource
+	blackouts    map[int]map[string]entities.Blackout
+	reservations []entities.ReservationConflict
+}
+
+func (r *fakeCalendarRepo) GetSources(context.Context) ([]entities.CalendarSource, error) {
+	return r.sources, nil
+}
+
+func (r *fakeCalendarRepo) SyncImported(_ context.Context, src entities.CalendarSource, events []entities.ImportedEvent) ([]entities.Blackout, error) {
+	if r.blackouts == nil {
+		r.blackouts = make(map[int]map[string]entities.Blackout)
+	}
+	stored := r.blackouts[src.ID]
+	next := make(map[string]entities.Blackout, len(events))
+
+	var changed []entities.Blackout
+	for _, e := range events {
+		b := entities.Blackout{HouseID: src.HouseID, SourceID: &src.ID, CheckIn: e.CheckIn, CheckOut: e.CheckOut}
+		old, ok := stored[e.UID]
+		if !ok || !old.CheckIn.Equal(b.CheckIn) || !old.CheckOut.Equal(b.CheckOut) {
+			changed = append(changed, b)
+		}
+		next[e.UID] = b
+	}
+	r.blackouts[src.ID] = next
+
+	return changed, nil
+}
+
+func (r *fakeCalendarRepo) GetOverlappingReservations(_ context.Context, _ int, checkIn, checkOut time.Time) ([]entities.ReservationConflict, error) {
+	var res []entities.ReservationConflict
+	for _, c := range r.reservations {
+		if c.CheckIn.Before(checkOut) && checkIn.Before(c.CheckOut) {
+			res = append(res, c)
+		}
+	}
+	return res, nil
+}
+
+type fakeHousesRepo struct {
+	repository.IHouses
+}
+
+func (fakeHousesRepo) GetOne(_ context.Context, id int) (entities.House, error) {
+	return entities.House{ID: id, Name: fmt.Sprintf("Дом %d", id)}, nil
+}
+
+type fakeCalendarNotifier struct {
+	messages []entities.CalendarConflictMessage
+}
+
+func (n *fakeCalendarNotifier) ImportedEventOverlaps(msg entities.CalendarConflictMessage) error {
+	n.messages = append(n.messages, msg)
+	return nil
+}
+
+// feedServer serves a calendar per path, a path without one answers 500.
+type feedServer struct {
+	mu    sync.Mutex
+	feeds map[string]string
+}
+
+func (s *feedServer) set(path string, events ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if events == nil {
+		delete(s.feeds, path)
+		return
+	}
+	s.feeds[path] = "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" + strings.Join(events, "") + "END:VCALENDAR\r\n"
+}
+
+func (s *feedServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	feed, ok := s.feeds[r.URL.Path]
+	s.mu.Unlock()
+	if !ok {
+		http.Error(w, "boom", http.StatusInternalServerError)
+		return
+	}
+	_, _ = io.WriteString(w, feed)
+}
+
+func vevent(uid, start, end string) string {
+	return fmt.Sprintf("BEGIN:VEVENT\r\nUID:%s\r\nDTSTART;VALUE=DATE:%s\r\nDTEND;VALUE=DATE:%s\r\nEND:VEVENT\r\n", uid, start, end)
+}
+
+func testDate(s string) time.Time {
+	d, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestCalendarSyncImported(t *testing.T) {
+	feeds := &feedServer{feeds: map[string]string{}}
+	srv := httptest.NewServer(feeds)
+	defer srv.Close()
+
+	fetcher, err := calendars.NewClient(srv.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reservation := entities.ReservationConflict{
+		UUID:      uuid.New(),
+		CheckIn:   testDate("2099-07-06"),
+		CheckOut:  testDate("2099-07-08"),
+		GuestName: "Анна",
+	}
+	repo := &fakeCalendarRepo{
+		sources: []entities.CalendarSource{
+			{ID: 1, HouseID: 7, Name: "Avito", URL: srv.URL + "/avito.ics"},
+			{ID: 2, HouseID: 7, Name: "Суточно", URL: srv.URL + "/sutochno.ics"},
+		},
+		reservations: []entities.ReservationConflict{reservation},
+	}
+	notifier := &fakeCalendarNotifier{}
+
+	calendar, err := NewCalendar(&CalendarDependencies{
+		Repo:      repo,
+		HouseRepo: fakeHousesRepo{},
+		Fetcher:   fetcher,
+		Notifier:  notifier,
+		Logger:    discardLogger(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	// Both feeds parse, nothing overlaps the reservation yet.
+	feeds.set("/avito.ics", vevent("a", "20990701", "20990705"), vevent("b", "20990710", "20990712"))
+	feeds.set("/sutochno.ics", vevent("s", "20990801", "20990803"))
+	if err = calendar.SyncImported(ctx); err != nil {
+		t.Fatalf("first sync: %v", err)
+	}
+	if got := len(repo.blackouts[1]); got != 2 {
+		t.Fatalf("source 1 has %d blackouts, want 2", got)
+	}
+	if len(notifier.messages) != 0 {
+		t.Fatalf("got %d conflict messages, want none", len(notifier.messages))
+	}
+
+	// Event a moves onto the reservation: its blackout is updated and admins are told.
+	feeds.set("/avito.ics", vevent("a", "20990703", "20990707"), vevent("b", "20990710", "20990712"))
+	if err = calendar.SyncImported(ctx); err != nil {
+		t.Fatalf("sync after change: %v", err)
+	}
+	if a := repo.blackouts[1]["a"]; !a.CheckIn.Equal(testDate("2099-07-03")) || !a.CheckOut.Equal(testDate("2099-07-07")) {
+		t.Errorf("moved event stored as %s – %s", a.CheckIn.Format(time.DateOnly), a.CheckOut.Format(time.DateOnly))
+	}
+	if len(notifier.messages) != 1 {
+		t.Fatalf("got %d conflict messages, want 1", len(notifier.messages))
+	}
+	msg := notifier.messages[0]
+	if msg.SourceName != "Avito" || msg.HouseName != "Дом 7" || len(msg.Reservations) != 1 || msg.Reservations[0].UUID != reservation.UUID {
+		t.Errorf("conflict message = %+v", msg)
+	}
+
+	// Event b disappears from the feed: its blackout is deleted, the unchanged a is not reported again.
+	feeds.set("/avito.ics", vevent("a", "20990703", "20990707"))
+	if err = calendar.SyncImported(ctx); err != nil {
+		t.Fatalf("sync after delete: %v", err)
+	}
+	if _, ok := repo.blackouts[1]["b"]; ok || len(repo.blackouts[1]) != 1 {
+		t.Errorf("source 1 blackouts after delete = %v, want only a", repo.blackouts[1])
+	}
+	if len(notifier.messages) != 1 {
+		t.Errorf("got %d conflict messages, want still 1", len(notifier.messages))
+	}
+
+	// A failing feed is reported but keeps its blackouts and doesn't stop the other source.
+	feeds.set("/sutochno.ics")
+	feeds.set("/avito.ics", vevent("a", "20990703", "20990707"), vevent("c", "20990901", "20990902"))
+	err = calendar.SyncImported(ctx)
+	if err == nil || !strings.Contains(err.Error(), "source [2]") {
+		t.Fatalf("sync with a broken feed returned %v, want an error for source 2", err)
+	}
+	if len(repo.blackouts[2]) != 1 {
+		t.Errorf("source 2 blackouts = %v, want the last synced one kept", repo.blackouts[2])
+	}
+	if _, ok := repo.blackouts[1]["c"]; !ok {
+		t.Error("source 1 was not synced while source 2 failed")
+	}
+}
@@ -4,22 +4,41 @@ import (
 	"context"
 	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
 	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/phonepkg"
+	"github.com/calyrexx/QuietGrooveBackend/internal/repository"
 	"github.com/calyrexx/zeroslog"
+	"github.com/google/uuid"
 	"log/slog"
+	"slices"
+	"strings"
+	"time"
 )
 
+// ApplicationStatuses is the sales pipeline of an event application, in order.
+var ApplicationStatuses = []entities.ApplicationStatus{
+	entities.ApplicationNew,
+	entities.ApplicationContacted,
+	entities.ApplicationQuoted,
+	entities.ApplicationWon,
+	entities.ApplicationLost,
+}
+
 type (
 	EventsNotifier interface {
-		NewApplicationForEvent(res entities.NewApplication) error
+		NewApplicationForEvent(app entities.EventApplication) error
 	}
 
 	EventsDependencies struct {
 		Logger   *slog.Logger
+		Repo     repository.IEventApplications
+		Bookings repository.IEventBookings
 		Notifier EventsNotifier
 	}
 
 	Events struct {
 		logger   *slog.Logger
+		repo     repository.IEventApplications
+		bookings repository.IEventBookings
 		notifier EventsNotifier
 	}
 )
@@ -28,6 +47,12 @@ func NewEvents(d *EventsDependencies) (*Events, error) {
 	if d == nil {
 		return nil, errorspkg.NewErrConstructorDependencies("Usecases Events", "whole", "nil")
 	}
+	if d.Repo == nil {
+		return nil, errorspkg.NewErrConstructorDependencies("Usecases Events", "Repo", "nil")
+	}
+	if d.Bookings == nil {
+		return nil, errorspkg.NewErrConstructorDependencies("Usecases Events", "Bookings", "nil")
+	}
 	if d.Notifier == nil {
 		return nil, errorspkg.NewErrConstructorDependencies("Usecases Events", "Notifier", "nil")
 	}
@@ -36,14 +61,161 @@ func NewEvents(d *EventsDependencies) (*Events, error) {
 
 	return &Events{
 		logger:   logger,
+		repo:     d.Repo,
+		bookings: d.Bookings,
 		notifier: d.Notifier,
 	}, nil
 }
 
+// NewApplication stores the application before notifying the admins, a failed
+// notification doesn't fail the request: the lead is already saved.
 func (e *Events) NewApplication(ctx context.Context, req entities.NewApplication) error {
-	if err := e.notifier.NewApplicationForEvent(req); err != nil {
+	app, err := e.repo.Create(ctx, req)
+	if err != nil {
+		e.logger.Error("create application", zeroslog.ErrorKey, err)
 		return err
 	}
 
+	if err = e.notifier.NewApplicationForEvent(app); err != nil {
+		e.logger.Error("notify new application", zeroslog.ErrorKey, err, "id", app.ID)
+	}
+
+	return nil
+}
+
+// Applications lists applications, an empty status means all of them.
+func (e *Events) Applications(ctx context.Context, status string) ([]entities.EventApplication, error) {
+	s := entities.ApplicationStatus(status)
+	if s != "" && !slices.Contains(ApplicationStatuses, s) {
+		return nil, errorspkg.ErrInvalidApplicationStatus
+	}
+	return e.repo.GetAll(ctx, s)
+}
+
+func (e *Events) UpdateApplication(
+	ctx context.Context,
+	id int,
+	upd entities.EventApplicationUpdate,
+) (entities.EventApplication, error) {
+	if upd.Status != nil && !slices.Contains(ApplicationStatuses, *upd.Status) {
+		return entities.EventApplication{}, errorspkg.ErrInvalidApplicationStatus
+	}
+	if upd.Notes != nil {
+		notes := strings.TrimSpace(*upd.Notes)
+		upd.Notes = &notes
+	}
+	if upd.Manager != nil {
+		manager := strings.TrimSpace(*upd.Manager)
+		upd.Manager = &manager
+	}
+	return e.repo.Update(ctx, id, upd)
+}
+
+// SetApplicationStatus moves the application along the pipeline from the bot, the admin
+// who touches an unassigned application becomes its manager.
+func (e *Events) SetApplicationStatus(
+	ctx context.Context,
+	id int,
+	status entities.ApplicationStatus,
+	manager string,
+) (entities.EventApplication, error) {
+	app, err := e.repo.Get(ctx, id)
+	if err != nil {
+		return app, err
+	}
+
+	upd := entities.EventApplicationUpdate{Status: &status}
+	if app.Manager == "" && manager != "" {
+		upd.Manager = &manager
+	}
+	return e.UpdateApplication(ctx, id, upd)
+}
+
+// CreateBooking reserves the houses and bathhouses for the event. A booking made from an
+// application takes the missing contacts from it and marks the application as won.
+func (e *Events) CreateBooking(ctx context.Context, b entities.EventBooking) (entities.EventBooking, error) {
+	if b.ApplicationID != nil {
+		app, err := e.repo.Get(ctx, *b.ApplicationID)
+		if err != nil {
+			return entities.EventBooking{}, err
+		}
+		if b.ContactName == "" {
+			b.ContactName = app.Name
+		}
+		if b.ContactPhone == "" {
+			b.ContactPhone = app.Phone
+		}
+		if b.GuestsCount == 0 {
+			b.GuestsCount = app.GuestsCount
+		}
+	}
+
+	b.CheckIn = startOfDay(b.CheckIn)
+	b.CheckOut = startOfDay(b.CheckOut)
+	if !b.CheckOut.After(b.CheckIn) {
+		return entities.EventBooking{}, errorspkg.ErrInvalidPeriod
+	}
+	b.HouseIDs = uniqueIDs(b.HouseIDs)
+	if len(b.HouseIDs) == 0 {
+		return entities.EventBooking{}, errorspkg.ErrEventWithoutHouses
+	}
+	b.BathhouseIDs = uniqueIDs(b.BathhouseIDs)
+	if b.GuestsCount <= 0 {
+		return entities.EventBooking{}, errorspkg.ErrInvalidGuestsCount
+	}
+	if b.TotalPrice < 0 || b.Deposit < 0 || b.Deposit > b.TotalPrice {
+		return entities.EventBooking{}, errorspkg.ErrInvalidDeposit
+	}
+
+	b.ContactName = strings.TrimSpace(b.ContactName)
+	if phone := strings.TrimSpace(b.ContactPhone); phone != "" {
+		normalized, err := phonepkg.Normalize(phone)
+		if err != nil {
+			return entities.EventBooking{}, err
+		}
+		b.ContactPhone = normalized
+	}
+	b.Title = strings.TrimSpace(b.Title)
+	if b.Title == "" {
+		b.Title = b.ContactName
+	}
+	b.Notes = strings.TrimSpace(b.Notes)
+
+	bookingUUID, err := e.bookings.Create(ctx, b)
+	if err != nil {
+		return entities.EventBooking{}, err
+	}
+	e.logger.Info("event booked", "uuid", bookingUUID, "houses", b.HouseIDs)
+
+	return e.bookings.Get(ctx, bookingUUID)
+}
+
+// Bookings lists events overlapping [from, to); a zero from means today, a zero to no limit.
+func (e *Events) Bookings(ctx context.Context, from, to time.Time) ([]entities.EventBooking, error) {
+	if from.IsZero() {
+		from = time.Now()
+	}
+	from = startOfDay(from)
+	if !to.IsZero() && !to.After(from) {
+		return nil, errorspkg.ErrInvalidPeriod
+	}
+	return e.bookings.GetAll(ctx, from, to)
+}
+
+func (e *Events) Booking(ctx context.Context, bookingUUID uuid.UUID) (entities.EventBooking, error) {
+	return e.bookings.Get(ctx, bookingUUID)
+}
+
+func (e *Events) CancelBooking(ctx context.Context, bookingUUID uuid.UUID) error {
+	if err := e.bookings.Cancel(ctx, bookingUUID); err != nil {
+		return err
+	}
+	e.logger.Info("event cancelled", "uuid", bookingUUID)
 	return nil
 }
+
+func uniqueIDs(ids []int) []int {
+	res := slices.Clone(ids)
+	slices.Sort(res)
+	return slices.Compact(res)
+}
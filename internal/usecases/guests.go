@@ -0,0 +1,155 @@
+package usecases
+
+import (
+	"context"
+	"github.com/calyrexx/QuietGrooveBackend/internal/configuration"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/calyrexx/QuietGrooveBackend/internal/repository"
+	"github.com/calyrexx/zeroslog"
+	"github.com/google/uuid"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	minGuestQuery    = 3
+	guestSearchLimit = 50
+)
+
+type (
+	GuestsDependencies struct {
+		Repo   repository.IGuests
+		Config *configuration.Guests
+		Logger *slog.Logger
+	}
+
+	// Guests lets administrators find guests and merge duplicate accounts, and serves
+	// personal data requests: export and anonymization.
+	Guests struct {
+		repo   repository.IGuests
+		config *configuration.Guests
+		logger *slog.Logger
+	}
+)
+
+func NewGuests(d *GuestsDependencies) (*Guests, error) {
+	const method = "usecases.NewGuests"
+	if d == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "whole", "nil")
+	}
+	if d.Repo == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "Repo", "nil")
+	}
+	if d.Config == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "Config", "nil")
+	}
+	if d.Config.InactiveAfter <= 0 {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "InactiveAfter", "not positive")
+	}
+	if d.Config.AnonymizeBatch <= 0 {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "AnonymizeBatch", "not positive")
+	}
+
+	logger := d.Logger.With(zeroslog.UsecaseKey, "Guests")
+
+	return &Guests{
+		repo:   d.Repo,
+		config: d.Config,
+		logger: logger,
+	}, nil
+}
+
+// Search finds guests by part of the name, email or phone, newest first.
+func (u *Guests) Search(ctx context.Context, query string) ([]entities.GuestProfile, error) {
+	query = strings.TrimSpace(query)
+	if utf8.RuneCountInString(query) < minGuestQuery {
+		return nil, errorspkg.ErrGuestQueryTooShort
+	}
+	return u.repo.Search(ctx, query, guestSearchLimit)
+}
+
+// Merge folds the source guest into the target, the source is deleted.
+func (u *Guests) Merge(ctx context.Context, targetUUID, sourceUUID uuid.UUID) (entities.GuestMerge, error) {
+	if targetUUID == sourceUUID {
+		return entities.GuestMerge{}, errorspkg.ErrMergeSameGuest
+	}
+
+	res, err := u.repo.Merge(ctx, targetUUID, sourceUUID)
+	if err != nil {
+		return res, err
+	}
+
+	u.logger.Info("guests merged",
+		"target", targetUUID,
+		"source", sourceUUID,
+		"reservations", res.Reservations,
+		"reviews", res.Reviews,
+		"notifications", res.Notifications,
+	)
+	return res, nil
+}
+
+// Export collects everything stored about the guest.
+func (u *Guests) Export(ctx context.Context, guestUUID uuid.UUID) (GuestExport, error) {
+	data, err := u.repo.ExportData(ctx, guestUUID)
+	if err != nil {
+		return GuestExport{}, err
+	}
+
+	u.logger.Info("guest data exported", "guest", guestUUID)
+	return newGuestExport(data, time.Now()), nil
+}
+
+// ExportByTgID is Export for the guest linked to the Telegram account.
+func (u *Guests) ExportByTgID(ctx context.Context, tgID int64) (GuestExport, error) {
+	guest, err := u.repo.GetByTgID(ctx, tgID)
+	if err != nil {
+		return GuestExport{}, err
+	}
+	return u.Export(ctx, guest.UUID)
+}
+
+// Erase anonymizes the guest, its reservations stay for accounting. Fails with
+// ErrGuestHasActiveStays while the guest has an upcoming or ongoing stay.
+func (u *Guests) Erase(ctx context.Context, guestUUID uuid.UUID) error {
+	if err := u.repo.Anonymize(ctx, guestUUID); err != nil {
+		return err
+	}
+
+	u.logger.Info("guest anonymized", "guest", guestUUID)
+	return nil
+}
+
+// EraseByTgID is Erase for the guest linked to the Telegram account.
+func (u *Guests) EraseByTgID(ctx context.Context, tgID int64) error {
+	guest, err := u.repo.GetByTgID(ctx, tgID)
+	if err != nil {
+		return err
+	}
+	return u.Erase(ctx, guest.UUID)
+}
+
+// AnonymizeInactive anonymizes guests that neither stayed nor booked for InactiveAfter.
+func (u *Guests) AnonymizeInactive(ctx context.Context) error {
+	ids, err := u.repo.GetInactive(ctx, time.Now().Add(-u.config.InactiveAfter), u.config.AnonymizeBatch)
+	if err != nil {
+		return err
+	}
+
+	var anonymized int
+	for _, id := range ids {
+		if err = u.repo.Anonymize(ctx, id); err != nil {
+			u.logger.Error("anonymize inactive guest", zeroslog.ErrorKey, err, "guest", id)
+			continue
+		}
+		anonymized++
+	}
+
+	if len(ids) > 0 {
+		u.logger.Info("inactive guests anonymized", "found", len(ids), "anonymized", anonymized)
+	}
+	return nil
+}
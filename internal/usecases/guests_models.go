@@ -0,0 +1,112 @@
+package usecases
+
+import (
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"time"
+)
+
+// GuestExport is the document handed to a guest on a personal data request, the same
+// for the admin API and the bot.
+type GuestExport struct {
+	ExportedAt     time.Time                 `json:"exportedAt"`
+	ID             string                    `json:"id"`
+	Name           string                    `json:"name"`
+	Email          string                    `json:"email"`
+	Phone          string                    `json:"phone"`
+	TelegramID     int64                     `json:"telegramId,omitempty"`
+	Locale         string                    `json:"locale,omitempty"`
+	NotifyChannels []string                  `json:"notifyChannels"`
+	CreatedAt      time.Time                 `json:"createdAt"`
+	Reservations   []GuestExportReservation  `json:"reservations"`
+	Reviews        []GuestExportReview       `json:"reviews"`
+	Notifications  []GuestExportNotification `json:"notifications"`
+	Verifications  []GuestExportVerification `json:"verifications"`
+}
+
+type GuestExportReservation struct {
+	ID          string    `json:"id"`
+	House       string    `json:"house"`
+	CheckIn     string    `json:"checkIn"`
+	CheckOut    string    `json:"checkOut"`
+	GuestsCount int       `json:"guestsCount"`
+	Status      string    `json:"status"`
+	TotalPrice  int       `json:"totalPrice"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type GuestExportReview struct {
+	ReservationID string    `json:"reservationId"`
+	Rating        int       `json:"rating"`
+	Comment       string    `json:"comment"`
+	Status        string    `json:"status"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+type GuestExportNotification struct {
+	Kind      string    `json:"kind"`
+	Channel   string    `json:"channel"`
+	Recipient string    `json:"recipient"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type GuestExportVerification struct {
+	Name       string     `json:"name"`
+	Email      string     `json:"email"`
+	Phone      string     `json:"phone"`
+	Status     string     `json:"status"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	VerifiedAt *time.Time `json:"verifiedAt,omitempty"`
+}
+
+func newGuestExport(data entities.GuestData, now time.Time) GuestExport {
+	p := data.Profile
+	res := GuestExport{
+		ExportedAt:     now,
+		ID:             p.UUID.String(),
+		Name:           p.Name,
+		Email:          p.Email,
+		Phone:          p.Phone,
+		TelegramID:     p.TgID,
+		Locale:         p.Locale,
+		NotifyChannels: make([]string, 0, len(data.NotifyChannels)),
+		CreatedAt:      p.CreatedAt,
+		Reservations:   make([]GuestExportReservation, 0, len(data.Reservations)),
+		Reviews:        make([]GuestExportReview, 0, len(data.Reviews)),
+		Notifications:  make([]GuestExportNotification, 0, len(data.Notifications)),
+		Verifications:  make([]GuestExportVerification, 0, len(data.Verifications)),
+	}
+
+	for _, ch := range data.NotifyChannels {
+		res.NotifyChannels = append(res.NotifyChannels, string(ch))
+	}
+	for _, r := range data.Reservations {
+		res.Reservations = append(res.Reservations, GuestExportReservation{
+			ID:          r.UUID.String(),
+			House:       r.HouseName,
+			CheckIn:     r.CheckIn.Format(time.DateOnly),
+			CheckOut:    r.CheckOut.Format(time.DateOnly),
+			GuestsCount: r.GuestsCount,
+			Status:      r.Status,
+			TotalPrice:  r.TotalPrice,
+			CreatedAt:   r.CreatedAt,
+		})
+	}
+	for _, r := range data.Reviews {
+		res.Reviews = append(res.Reviews, GuestExportReview{
+			ReservationID: r.ReservationUUID.String(),
+			Rating:        r.Rating,
+			Comment:       r.Comment,
+			Status:        r.Status,
+			CreatedAt:     r.CreatedAt,
+		})
+	}
+	for _, n := range data.Notifications {
+		res.Notifications = append(res.Notifications, GuestExportNotification(n))
+	}
+	for _, v := range data.Verifications {
+		res.Verifications = append(res.Verifications, GuestExportVerification(v))
+	}
+
+	return res
+}
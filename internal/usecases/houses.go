@@ -7,16 +7,31 @@ import (
 	"github.com/calyrexx/QuietGrooveBackend/internal/repository"
 	"github.com/calyrexx/zeroslog"
 	"log/slog"
+	"math"
+	"slices"
+	"strings"
+	"unicode/utf8"
 )
 
+const maxTagLength = 32
+
+var bedKinds = []entities.BedKind{
+	entities.BedDouble,
+	entities.BedSingle,
+	entities.BedSofa,
+	entities.BedBunk,
+}
+
 type (
 	HousesDependencies struct {
-		Repo   repository.IHouses
-		Logger *slog.Logger
+		Repo        repository.IHouses
+		ReviewsRepo repository.IReviews
+		Logger      *slog.Logger
 	}
 	Houses struct {
-		repo   repository.IHouses
-		logger *slog.Logger
+		repo        repository.IHouses
+		reviewsRepo repository.IReviews
+		logger      *slog.Logger
 	}
 )
 
@@ -24,20 +39,64 @@ func NewHouses(d *HousesDependencies) (*Houses, error) {
 	if d == nil {
 		return nil, errorspkg.NewErrConstructorDependencies("Usecases Houses", "whole", "nil")
 	}
+	if d.ReviewsRepo == nil {
+		return nil, errorspkg.NewErrConstructorDependencies("Usecases Houses", "ReviewsRepo", "nil")
+	}
 
 	logger := d.Logger.With(zeroslog.UsecaseKey, "Houses")
 
 	return &Houses{
-		repo:   d.Repo,
-		logger: logger,
+		repo:        d.Repo,
+		reviewsRepo: d.ReviewsRepo,
+		logger:      logger,
 	}, nil
 }
 
+// GetAll returns houses with their approved reviews and average rating.
 func (u *Houses) GetAll(ctx context.Context) ([]entities.House, error) {
-	return u.repo.GetAll(ctx)
+	houses, err := u.repo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reviews, err := u.reviewsRepo.GetApproved(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byHouse := make(map[int][]entities.Review, len(houses))
+	for _, rv := range reviews {
+		byHouse[rv.HouseID] = append(byHouse[rv.HouseID], rv)
+	}
+
+	for i := range houses {
+		houseReviews := byHouse[houses[i].ID]
+		if len(houseReviews) == 0 {
+			continue
+		}
+		sum := 0
+		for _, rv := range houseReviews {
+			sum += rv.Rating
+		}
+		houses[i].Reviews = houseReviews
+		houses[i].ReviewsCount = len(houseReviews)
+		houses[i].Rating = math.Round(float64(sum)/float64(len(houseReviews))*10) / 10
+	}
+
+	return houses, nil
 }
 
 func (u *Houses) Add(ctx context.Context, houses []entities.House) error {
+	for i := range houses {
+		if err := validateAmenities(houses[i].Amenities); err != nil {
+			return err
+		}
+		tags, err := normalizeTags(houses[i].Tags)
+		if err != nil {
+			return err
+		}
+		houses[i].Tags = tags
+	}
 	return u.repo.Add(ctx, houses)
 }
 
@@ -48,3 +107,49 @@ func (u *Houses) Update(ctx context.Context, house entities.House) error {
 func (u *Houses) Delete(ctx context.Context, houseID int) error {
 	return u.repo.Delete(ctx, houseID)
 }
+
+func (u *Houses) UpdateAmenities(ctx context.Context, houseID int, amenities entities.HouseAmenities) error {
+	if err := validateAmenities(amenities); err != nil {
+		return err
+	}
+	return u.repo.UpdateAmenities(ctx, houseID, amenities)
+}
+
+func (u *Houses) UpdateTags(ctx context.Context, houseID int, tags []string) error {
+	tags, err := normalizeTags(tags)
+	if err != nil {
+		return err
+	}
+	return u.repo.UpdateTags(ctx, houseID, tags)
+}
+
+func (u *Houses) GetTags(ctx context.Context) ([]entities.HouseTag, error) {
+	return u.repo.GetTags(ctx)
+}
+
+func validateAmenities(amenities entities.HouseAmenities) error {
+	if amenities.Area < 0 {
+		return errorspkg.ErrInvalidArea
+	}
+	for _, bed := range amenities.Beds {
+		if !slices.Contains(bedKinds, bed.Kind) || bed.Count <= 0 {
+			return errorspkg.ErrInvalidBed
+		}
+	}
+	return nil
+}
+
+// normalizeTags trims and lowercases the tags and drops duplicates, keeping the first occurrence.
+func normalizeTags(tags []string) ([]string, error) {
+	res := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || utf8.RuneCountInString(tag) > maxTagLength {
+			return nil, errorspkg.ErrInvalidTag
+		}
+		if !slices.Contains(res, tag) {
+			res = append(res, tag)
+		}
+	}
+	return res, nil
+}
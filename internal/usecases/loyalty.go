@@ -0,0 +1,124 @@
+package usecases
+
+import (
+	"context"
+	"github.com/calyrexx/QuietGrooveBackend/internal/configuration"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/calyrexx/QuietGrooveBackend/internal/repository"
+	"github.com/calyrexx/zeroslog"
+	"github.com/google/uuid"
+	"log/slog"
+	"slices"
+)
+
+type (
+	LoyaltyDependencies struct {
+		Repo      repository.ILoyalty
+		GuestRepo repository.IGuests
+		Config    *configuration.Loyalty
+		Logger    *slog.Logger
+	}
+
+	// Loyalty rewards returning guests: tiers come from completed stays and spend and
+	// give a discount on new reservations.
+	Loyalty struct {
+		repo      repository.ILoyalty
+		guestRepo repository.IGuests
+		tiers     []entities.LoyaltyTier // by Discount, ascending
+		logger    *slog.Logger
+	}
+)
+
+func NewLoyalty(d *LoyaltyDependencies) (*Loyalty, error) {
+	const method = "usecases.NewLoyalty"
+	if d == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "whole", "nil")
+	}
+	if d.Repo == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "Repo", "nil")
+	}
+	if d.GuestRepo == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "GuestRepo", "nil")
+	}
+	if d.Config == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "Config", "nil")
+	}
+
+	tiers := make([]entities.LoyaltyTier, 0, len(d.Config.Tiers))
+	for _, t := range d.Config.Tiers {
+		if t.Discount <= 0 || t.Discount >= 100 {
+			return nil, errorspkg.NewErrConstructorDependencies(method, "Tiers.Discount", "not in 1..99")
+		}
+		if t.MinStays < 0 || t.MinSpend < 0 {
+			return nil, errorspkg.NewErrConstructorDependencies(method, "Tiers.MinStays/MinSpend", "negative")
+		}
+		tiers = append(tiers, entities.LoyaltyTier{
+			Name:     t.Name,
+			MinStays: t.MinStays,
+			MinSpend: t.MinSpend,
+			Discount: t.Discount,
+		})
+	}
+	slices.SortStableFunc(tiers, func(a, b entities.LoyaltyTier) int { return a.Discount - b.Discount })
+
+	logger := d.Logger.With(zeroslog.UsecaseKey, "Loyalty")
+
+	return &Loyalty{
+		repo:      d.Repo,
+		guestRepo: d.GuestRepo,
+		tiers:     tiers,
+		logger:    logger,
+	}, nil
+}
+
+func (u *Loyalty) Status(ctx context.Context, guestUUID uuid.UUID) (entities.LoyaltyStatus, error) {
+	stats, err := u.repo.GetStats(ctx, guestUUID)
+	if err != nil {
+		return entities.LoyaltyStatus{}, err
+	}
+	return u.status(stats), nil
+}
+
+// StatusByTgID is Status for the guest linked to the Telegram account.
+func (u *Loyalty) StatusByTgID(ctx context.Context, tgID int64) (entities.LoyaltyStatus, error) {
+	guest, err := u.guestRepo.GetByTgID(ctx, tgID)
+	if err != nil {
+		return entities.LoyaltyStatus{}, err
+	}
+	return u.Status(ctx, guest.UUID)
+}
+
+// Discount is the guest's discount percent, 0 below the first tier.
+func (u *Loyalty) Discount(ctx context.Context, guestUUID uuid.UUID) (int, error) {
+	status, err := u.Status(ctx, guestUUID)
+	if err != nil || status.Tier == nil {
+		return 0, err
+	}
+	return status.Tier.Discount, nil
+}
+
+// status picks the best reached tier; the next one is the cheapest tier above it.
+func (u *Loyalty) status(stats entities.LoyaltyStats) entities.LoyaltyStatus {
+	res := entities.LoyaltyStatus{LoyaltyStats: stats}
+
+	for i := range u.tiers {
+		t := u.tiers[i]
+		if stats.Stays >= t.MinStays && stats.Spend >= t.MinSpend {
+			res.Tier = &t
+		}
+	}
+
+	for i := range u.tiers {
+		t := u.tiers[i]
+		if res.Tier != nil && t.Discount <= res.Tier.Discount {
+			continue
+		}
+		res.Next = &t
+		res.StaysToNext = max(t.MinStays-stats.Stays, 0)
+		res.SpendToNext = max(t.MinSpend-stats.Spend, 0)
+		break
+	}
+
+	return res
+}
@@ -0,0 +1,204 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"github.com/calyrexx/QuietGrooveBackend/internal/configuration"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/imagepkg"
+	"github.com/calyrexx/QuietGrooveBackend/internal/repository"
+	"github.com/calyrexx/zeroslog"
+	"github.com/google/uuid"
+	"log/slog"
+	"slices"
+)
+
+type (
+	// MediaStorage keeps media files under keys and returns their public URLs.
+	MediaStorage interface {
+		Put(ctx context.Context, key, contentType string, data []byte) (string, error)
+		Delete(ctx context.Context, key string) error
+	}
+
+	MediaDependencies struct {
+		Repo    repository.IMedia
+		Storage MediaStorage
+		Config  *configuration.Media
+		Logger  *slog.Logger
+	}
+
+	// Media stores uploaded images of houses, extras and bathhouses with their scaled down
+	// variants and keeps the order of each owner's gallery.
+	Media struct {
+		repo     repository.IMedia
+		storage  MediaStorage
+		config   *configuration.Media
+		variants []configuration.MediaVariant // by Width, ascending
+		logger   *slog.Logger
+	}
+)
+
+var mediaOwners = []entities.MediaOwner{
+	entities.MediaHouse,
+	entities.MediaExtra,
+	entities.MediaBathhouse,
+}
+
+func NewMedia(d *MediaDependencies) (*Media, error) {
+	const method = "usecases.NewMedia"
+	if d == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "whole", "nil")
+	}
+	if d.Repo == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "Repo", "nil")
+	}
+	if d.Storage == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "Storage", "nil")
+	}
+	if d.Config == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "Config", "nil")
+	}
+	if d.Config.MaxSize <= 0 || d.Config.MaxPixels <= 0 {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "MaxSize/MaxPixels", "not positive")
+	}
+	if d.Config.JPEGQuality < 1 || d.Config.JPEGQuality > 100 {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "JPEGQuality", "not in 1..100")
+	}
+
+	variants := slices.Clone(d.Config.Variants)
+	for _, v := range variants {
+		if v.Name == "" || v.Width <= 0 {
+			return nil, errorspkg.NewErrConstructorDependencies(method, "Variants", "without name or width")
+		}
+	}
+	slices.SortStableFunc(variants, func(a, b configuration.MediaVariant) int { return a.Width - b.Width })
+
+	logger := d.Logger.With(zeroslog.UsecaseKey, "Media")
+
+	return &Media{
+		repo:     d.Repo,
+		storage:  d.Storage,
+		config:   d.Config,
+		variants: variants,
+		logger:   logger,
+	}, nil
+}
+
+// Upload validates the image, stores it with its variants and appends it to the owner's gallery.
+// Files already stored are removed if a later step fails.
+func (u *Media) Upload(ctx context.Context, owner entities.MediaOwner, ownerID int, data []byte) (entities.Media, error) {
+	if !slices.Contains(mediaOwners, owner) {
+		return entities.Media{}, errorspkg.ErrUnknownMediaOwner
+	}
+	if len(data) > u.config.MaxSize {
+		return entities.Media{}, errorspkg.ErrMediaTooLarge
+	}
+
+	contentType := imagepkg.Detect(data)
+	if contentType == "" {
+		return entities.Media{}, errorspkg.ErrUnsupportedMedia
+	}
+	width, height, err := imagepkg.Size(data)
+	if err != nil {
+		return entities.Media{}, errorspkg.ErrUnsupportedMedia
+	}
+	if width*height > u.config.MaxPixels {
+		return entities.Media{}, errorspkg.ErrMediaTooLarge
+	}
+	img, err := imagepkg.Decode(data)
+	if err != nil {
+		return entities.Media{}, errorspkg.ErrUnsupportedMedia
+	}
+
+	prefix := fmt.Sprintf("%s/%d/%s/", owner, ownerID, uuid.NewString())
+	media := entities.Media{
+		Owner:       owner,
+		OwnerID:     ownerID,
+		ContentType: contentType,
+		Size:        len(data),
+		Width:       width,
+		Height:      height,
+		Key:         prefix + "original" + imagepkg.Extension(contentType),
+		Variants:    make([]entities.MediaVariant, 0, len(u.variants)),
+	}
+
+	media.URL, err = u.storage.Put(ctx, media.Key, contentType, data)
+	if err != nil {
+		return entities.Media{}, err
+	}
+
+	for _, v := range u.variants {
+		scaled := imagepkg.Fit(img, v.Width)
+		body, variantType, encodeErr := imagepkg.Encode(scaled, u.config.JPEGQuality)
+		if encodeErr != nil {
+			u.remove(ctx, media)
+			return entities.Media{}, encodeErr
+		}
+
+		variant := entities.MediaVariant{
+			Name:        v.Name,
+			Key:         prefix + v.Name + imagepkg.Extension(variantType),
+			ContentType: variantType,
+			Width:       scaled.Bounds().Dx(),
+			Height:      scaled.Bounds().Dy(),
+		}
+		variant.URL, err = u.storage.Put(ctx, variant.Key, variantType, body)
+		if err != nil {
+			u.remove(ctx, media)
+			return entities.Media{}, err
+		}
+		media.Variants = append(media.Variants, variant)
+	}
+
+	created, err := u.repo.Create(ctx, media)
+	if err != nil {
+		u.remove(ctx, media)
+		return entities.Media{}, err
+	}
+
+	return created, nil
+}
+
+func (u *Media) List(ctx context.Context, owner entities.MediaOwner, ownerID int) ([]entities.Media, error) {
+	if !slices.Contains(mediaOwners, owner) {
+		return nil, errorspkg.ErrUnknownMediaOwner
+	}
+	return u.repo.GetByOwner(ctx, owner, ownerID)
+}
+
+func (u *Media) Reorder(ctx context.Context, owner entities.MediaOwner, ownerID int, ids []int) error {
+	if !slices.Contains(mediaOwners, owner) {
+		return errorspkg.ErrUnknownMediaOwner
+	}
+	return u.repo.Reorder(ctx, owner, ownerID, ids)
+}
+
+// Delete removes the media from the gallery first, so a storage failure leaves
+// only unreferenced files behind.
+func (u *Media) Delete(ctx context.Context, id int) error {
+	media, err := u.repo.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	u.remove(ctx, media)
+	return nil
+}
+
+// remove deletes the stored files of the media, failures are only logged.
+func (u *Media) remove(ctx context.Context, media entities.Media) {
+	keys := make([]string, 0, len(media.Variants)+1)
+	if media.URL != "" {
+		keys = append(keys, media.Key)
+	}
+	for _, v := range media.Variants {
+		keys = append(keys, v.Key)
+	}
+
+	for _, key := range keys {
+		if err := u.storage.Delete(ctx, key); err != nil {
+			u.logger.Error("delete media file", zeroslog.ErrorKey, err, "key", key)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/calyrexx/QuietGrooveBackend/internal/configuration"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/calyrexx/QuietGrooveBackend/internal/repository"
+)
+
+type memoryStorage struct {
+	files map[string][]byte
+}
+
+func (s *memoryStorage) Put(_ context.Context, key, _ string, data []byte) (string, error) {
+	s.files[key] = data
+	return "https://cdn.test/" + key, nil
+}
+
+func (s *memoryStorage) Delete(_ context.Context, key string) error {
+	delete(s.files, key)
+	return nil
+}
+
+type fakeMediaRepo struct {
+	repository.IMedia
+	created []entities.Media
+	err     error
+}
+
+func (r *fakeMediaRepo) Create(_ context.Context, media entities.Media) (entities.Media, error) {
+	if r.err != nil {
+		return entities.Media{}, r.err
+	}
+	media.ID = len(r.created) + 1
+	r.created = append(r.created, media)
+	return media, nil
+}
+
+func newTestMedia(t *testing.T, config *configuration.Media) (*Media, *memoryStorage, *fakeMediaRepo) {
+	t.Helper()
+
+	storage := &memoryStorage{files: map[string][]byte{}}
+	repo := &fakeMediaRepo{}
+	media, err := NewMedia(&MediaDependencies{
+		Repo:    repo,
+		Storage: storage,
+		Config:  config,
+		Logger:  discardLogger(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return media, storage, repo
+}
+
+func pngImage(t *testing.T, width, height int) []byte {
+	t.Helper()
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for x := range width {
+		for y := range height {
+			img.Set(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 200, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// pngBomb is a tiny PNG whose header claims the given size, decoding it would
+// allocate width*height pixels.
+func pngBomb(t *testing.T, width, height uint32) []byte {
+	t.Helper()
+
+	data := pngImage(t, 1, 1)
+	// The signature is followed by the IHDR chunk: length, type, width, height, ..., CRC.
+	ihdr := data[8+4 : 8+4+4+13]
+	binary.BigEndian.PutUint32(ihdr[4:], width)
+	binary.BigEndian.PutUint32(ihdr[8:], height)
+	binary.BigEndian.PutUint32(data[8+4+4+13:], crc32.ChecksumIEEE(ihdr))
+	return data
+}
+
+func TestUploadLimits(t *testing.T) {
+	config := &configuration.Media{
+		MaxSize:     64 << 10,
+		MaxPixels:   100 * 100,
+		JPEGQuality: 80,
+		Variants:    []configuration.MediaVariant{{Name: "thumb", Width: 20}},
+	}
+
+	tests := []struct {
+		name  string
+		owner entities.MediaOwner
+		data  []byte
+		want  error
+	}{
+		{"unknown owner", "guest", pngImage(t, 10, 10), errorspkg.ErrUnknownMediaOwner},
+		{"over max size", entities.MediaHouse, make([]byte, config.MaxSize+1), errorspkg.ErrMediaTooLarge},
+		{"over max pixels", entities.MediaHouse, pngImage(t, 101, 100), errorspkg.ErrMediaTooLarge},
+		{"pixel bomb", entities.MediaHouse, pngBomb(t, 50000, 50000), errorspkg.ErrMediaTooLarge},
+		{"not an image", entities.MediaHouse, []byte("<svg xmlns='http://www.w3.org/2000/svg'/>"), errorspkg.ErrUnsupportedMedia},
+		{"broken image", entities.MediaHouse, pngImage(t, 10, 10)[:40], errorspkg.ErrUnsupportedMedia},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			media, storage, repo := newTestMedia(t, config)
+
+			_, err := media.Upload(context.Background(), tt.owner, 1, tt.data)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Upload error = %v, want %v", err, tt.want)
+			}
+			if len(storage.files) != 0 || len(repo.created) != 0 {
+				t.Errorf("rejected upload was stored: files %d, rows %d", len(storage.files), len(repo.created))
+			}
+		})
+	}
+
+	t.Run("at the limits", func(t *testing.T) {
+		media, storage, _ := newTestMedia(t, config)
+
+		created, err := media.Upload(context.Background(), entities.MediaHouse, 3, pngImage(t, 100, 100))
+		if err != nil {
+			t.Fatalf("Upload: %v", err)
+		}
+		if created.Width != 100 || created.Height != 100 || created.ContentType != "image/png" {
+			t.Errorf("created = %dx%d %s", created.Width, created.Height, created.ContentType)
+		}
+		if !strings.HasPrefix(created.Key, "house/3/") || !strings.HasSuffix(created.Key, "/original.png") {
+			t.Errorf("key = %q", created.Key)
+		}
+		if len(created.Variants) != 1 || created.Variants[0].Width != 20 || created.Variants[0].Height != 20 {
+			t.Fatalf("variants = %+v", created.Variants)
+		}
+		keys := []string{created.Key, created.Variants[0].Key}
+		for _, key := range keys {
+			if _, ok := storage.files[key]; !ok {
+				t.Errorf("%s is not stored", key)
+			}
+		}
+		if len(storage.files) != len(keys) {
+			t.Errorf("stored %d files, want %d", len(storage.files), len(keys))
+		}
+	})
+}
+
+func TestUploadRemovesFilesWhenNotSaved(t *testing.T) {
+	media, storage, repo := newTestMedia(t, &configuration.Media{
+		MaxSize:     1 << 20,
+		MaxPixels:   1 << 20,
+		JPEGQuality: 80,
+		Variants:    []configuration.MediaVariant{{Name: "small", Width: 8}, {Name: "medium", Width: 16}},
+	})
+	repo.err = errors.New("db is down")
+
+	if _, err := media.Upload(context.Background(), entities.MediaExtra, 1, pngImage(t, 32, 32)); !errors.Is(err, repo.err) {
+		t.Fatalf("Upload error = %v, want the repo error", err)
+	}
+	if len(storage.files) != 0 {
+		t.Errorf("files left behind: %v", slices.Collect(maps.Keys(storage.files)))
+	}
+}
@@ -0,0 +1,335 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/calyrexx/QuietGrooveBackend/internal/configuration"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/calyrexx/QuietGrooveBackend/internal/repository"
+	"github.com/calyrexx/zeroslog"
+	"github.com/google/uuid"
+	"log/slog"
+	"maps"
+	"slices"
+	"strconv"
+)
+
+const (
+	notifyReservationCreated   = "reservation_created"
+	notifyReservationCancelled = "reservation_cancelled"
+	notifyReminder             = "reminder"
+	notifyEventApplication     = "event_application"
+
+	adminsRecipient = "admins"
+)
+
+var (
+	errNoGuestChannel   = errors.New("guest has no reachable notification channel")
+	errUnknownRecipient = errors.New("admin recipient is not configured")
+)
+
+type (
+	// ChannelNotifier is a single delivery channel: Telegram, email, SMS or webhook.
+	ChannelNotifier interface {
+		Notifier
+		EventsNotifier
+	}
+
+	// AdminChannel is a channel that sends admin notifications to several addresses. The
+	// dispatcher sends to them one by one, so each address is recorded and retried on its own.
+	AdminChannel interface {
+		AdminRecipients() []string
+		ForAdmin(recipient string) (ChannelNotifier, bool)
+	}
+
+	NotificationsDependencies struct {
+		Channels  map[entities.NotificationChannel]ChannelNotifier
+		Repo      repository.INotifications
+		GuestRepo repository.IGuests
+		Config    *configuration.Notifications
+		Logger    *slog.Logger
+	}
+
+	// Notifications dispatches domain notifications to the registered channels. Admin
+	// notifications fan out to every address of every admin channel; guest notifications go to the first
+	// channel that succeeds, in order of the guest's preferences followed by the defaults.
+	Notifications struct {
+		channels      map[entities.NotificationChannel]ChannelNotifier
+		repo          repository.INotifications
+		guestRepo     repository.IGuests
+		guestChannels []entities.NotificationChannel
+		adminChannels []entities.NotificationChannel
+		logger        *slog.Logger
+	}
+
+	guestContact struct {
+		UUID  uuid.UUID
+		TgID  int64
+		Email string
+		Phone string
+	}
+)
+
+func NewNotifications(d *NotificationsDependencies) (*Notifications, error) {
+	const method = "usecases.NewNotifications"
+	if d == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "whole", "nil")
+	}
+	if len(d.Channels) == 0 {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "Channels", "empty")
+	}
+	if d.Repo == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "Repo", "nil")
+	}
+	if d.GuestRepo == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "GuestRepo", "nil")
+	}
+	if d.Config == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "Config", "nil")
+	}
+
+	logger := d.Logger.With(zeroslog.UsecaseKey, "Notifications")
+
+	n := &Notifications{
+		channels:  d.Channels,
+		repo:      d.Repo,
+		guestRepo: d.GuestRepo,
+		logger:    logger,
+	}
+	n.guestChannels = n.registered(d.Config.GuestChannels)
+	n.adminChannels = n.registered(d.Config.AdminChannels)
+	if len(n.adminChannels) == 0 {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "AdminChannels", "none registered")
+	}
+
+	return n, nil
+}
+
+func (n *Notifications) ReservationCreatedForAdmin(msg entities.ReservationCreatedMessage) error {
+	return n.toAdmins(notifyReservationCreated, msg.UUID.String(), func(c ChannelNotifier) error {
+		return c.ReservationCreatedForAdmin(msg)
+	})
+}
+
+func (n *Notifications) ReservationCreatedForUser(msg entities.ReservationCreatedMessage, tgID int64) error {
+	contact := guestContact{UUID: msg.GuestUUID, TgID: tgID, Email: msg.GuestEmail, Phone: msg.GuestPhone}
+	return n.toGuest(notifyReservationCreated, msg.UUID.String(), contact, func(c ChannelNotifier) error {
+		return c.ReservationCreatedForUser(msg, tgID)
+	})
+}
+
+func (n *Notifications) ReservationCancelledForAdmin(msg entities.ReservationCancelledMessage) error {
+	return n.toAdmins(notifyReservationCancelled, msg.UUID.String(), func(c ChannelNotifier) error {
+		return c.ReservationCancelledForAdmin(msg)
+	})
+}
+
+func (n *Notifications) ReservationCancelledForUser(msg entities.ReservationCancelledMessage) error {
+	contact := guestContact{UUID: msg.GuestUUID, TgID: msg.GuestTgID, Email: msg.GuestEmail, Phone: msg.GuestPhone}
+	return n.toGuest(notifyReservationCancelled, msg.UUID.String(), contact, func(c ChannelNotifier) error {
+		return c.ReservationCancelledForUser(msg)
+	})
+}
+
+func (n *Notifications) RemindUser(msg []entities.ReservationReminderNotification) error {
+	var errs []error
+	for _, m := range msg {
+		contact := guestContact{UUID: m.GuestUUID, TgID: m.UserTgID, Email: m.GuestEmail, Phone: m.GuestPhone}
+		err := n.toGuest(notifyReminder, m.UUID.String(), contact, func(c ChannelNotifier) error {
+			return c.RemindUser([]entities.ReservationReminderNotification{m})
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("remind %s: %w", m.UUID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (n *Notifications) NewApplicationForEvent(app entities.EventApplication) error {
+	return n.toAdmins(notifyEventApplication, strconv.Itoa(app.ID), func(c ChannelNotifier) error {
+		return c.NewApplicationForEvent(app)
+	})
+}
+
+// AdminRecipients lists every address admin notifications go to. A channel that isn't an
+// AdminChannel counts as a single recipient.
+func (n *Notifications) AdminRecipients() []entities.AdminRecipient {
+	var res []entities.AdminRecipient
+	for _, ch := range n.adminChannels {
+		multi, ok := n.channels[ch].(AdminChannel)
+		if !ok {
+			res = append(res, entities.AdminRecipient{Channel: ch, Recipient: adminsRecipient})
+			continue
+		}
+		for _, recipient := range multi.AdminRecipients() {
+			res = append(res, entities.AdminRecipient{Channel: ch, Recipient: recipient})
+		}
+	}
+	return res
+}
+
+// ForAdmin returns a dispatcher whose admin notifications go to r only.
+func (n *Notifications) ForAdmin(r entities.AdminRecipient) (Notifier, error) {
+	channel, ok := n.adminChannel(r)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s %s", errUnknownRecipient, r.Channel, r.Recipient)
+	}
+
+	scoped := *n
+	scoped.channels = maps.Clone(n.channels)
+	scoped.channels[r.Channel] = channel
+	scoped.adminChannels = []entities.NotificationChannel{r.Channel}
+	return &scoped, nil
+}
+
+// toAdmins sends to every admin address separately and fails only when none of them got it.
+func (n *Notifications) toAdmins(kind, reference string, send func(ChannelNotifier) error) error {
+	recipients := n.AdminRecipients()
+
+	var errs []error
+	for _, r := range recipients {
+		channel, _ := n.adminChannel(r)
+		err := send(channel)
+		n.record(kind, r.Channel, r.Recipient, nil, reference, err)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s %s: %w", r.Channel, r.Recipient, err))
+		}
+	}
+	if len(errs) == len(recipients) {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// adminChannel narrows an admin channel to one of its addresses.
+func (n *Notifications) adminChannel(r entities.AdminRecipient) (ChannelNotifier, bool) {
+	channel, ok := n.channels[r.Channel]
+	if !ok || !slices.Contains(n.adminChannels, r.Channel) {
+		return nil, false
+	}
+	multi, ok := channel.(AdminChannel)
+	if !ok {
+		return channel, r.Recipient == adminsRecipient
+	}
+	return multi.ForAdmin(r.Recipient)
+}
+
+// toGuest tries the guest's channels in order and stops at the first successful delivery.
+func (n *Notifications) toGuest(kind, reference string, guest guestContact, send func(ChannelNotifier) error) error {
+	var (
+		errs      []error
+		guestUUID *uuid.UUID
+	)
+	if guest.UUID != uuid.Nil {
+		guestUUID = &guest.UUID
+	}
+
+	for _, ch := range n.guestOrder(guest.UUID) {
+		recipient, ok := guest.recipient(ch)
+		if !ok {
+			n.recordStatus(kind, ch, "", guestUUID, reference, entities.DeliverySkipped, "no contact for channel")
+			continue
+		}
+
+		err := send(n.channels[ch])
+		n.record(kind, ch, recipient, guestUUID, reference, err)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", ch, err))
+	}
+
+	if len(errs) == 0 {
+		return errNoGuestChannel
+	}
+	return errors.Join(errs...)
+}
+
+func (n *Notifications) guestOrder(guestUUID uuid.UUID) []entities.NotificationChannel {
+	var preferred []entities.NotificationChannel
+	if guestUUID != uuid.Nil {
+		channels, err := n.guestRepo.GetNotifyChannels(context.Background(), guestUUID)
+		if err != nil {
+			n.logger.Warn("get guest notify channels", zeroslog.ErrorKey, err, "guest", guestUUID)
+		}
+		preferred = channels
+	}
+
+	order := make([]entities.NotificationChannel, 0, len(preferred)+len(n.guestChannels))
+	for _, ch := range append(preferred, n.guestChannels...) {
+		if _, ok := n.channels[ch]; ok && !slices.Contains(order, ch) {
+			order = append(order, ch)
+		}
+	}
+	return order
+}
+
+func (n *Notifications) registered(names []string) []entities.NotificationChannel {
+	res := make([]entities.NotificationChannel, 0, len(names))
+	for _, name := range names {
+		ch := entities.NotificationChannel(name)
+		if _, ok := n.channels[ch]; !ok {
+			n.logger.Warn("notification channel is not configured, skipping", "channel", name)
+			continue
+		}
+		if !slices.Contains(res, ch) {
+			res = append(res, ch)
+		}
+	}
+	return res
+}
+
+func (n *Notifications) record(
+	kind string,
+	ch entities.NotificationChannel,
+	recipient string,
+	guestUUID *uuid.UUID,
+	reference string,
+	sendErr error,
+) {
+	if sendErr != nil {
+		n.recordStatus(kind, ch, recipient, guestUUID, reference, entities.DeliveryFailed, sendErr.Error())
+		return
+	}
+	n.recordStatus(kind, ch, recipient, guestUUID, reference, entities.DeliverySent, "")
+}
+
+func (n *Notifications) recordStatus(
+	kind string,
+	ch entities.NotificationChannel,
+	recipient string,
+	guestUUID *uuid.UUID,
+	reference string,
+	status entities.DeliveryStatus,
+	errText string,
+) {
+	err := n.repo.AddDelivery(context.Background(), entities.NotificationDelivery{
+		Kind:      kind,
+		Channel:   ch,
+		Recipient: recipient,
+		GuestUUID: guestUUID,
+		Reference: reference,
+		Status:    status,
+		Error:     errText,
+	})
+	if err != nil {
+		n.logger.Error("record notification delivery", zeroslog.ErrorKey, err, "kind", kind, "channel", ch)
+	}
+}
+
+func (g guestContact) recipient(ch entities.NotificationChannel) (string, bool) {
+	switch ch {
+	case entities.ChannelTelegram:
+		return strconv.FormatInt(g.TgID, 10), g.TgID != 0
+	case entities.ChannelEmail:
+		return g.Email, g.Email != ""
+	case entities.ChannelSMS:
+		return g.Phone, g.Phone != ""
+	case entities.ChannelWebhook:
+		return g.UUID.String(), g.UUID != uuid.Nil
+	default:
+		return "", false
+	}
+}
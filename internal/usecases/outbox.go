@@ -0,0 +1,244 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/calyrexx/QuietGrooveBackend/internal/configuration"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/calyrexx/QuietGrooveBackend/internal/repository"
+	"github.com/calyrexx/zeroslog"
+	"log/slog"
+	"time"
+)
+
+const (
+	outboxReservationCreatedAdmin   = "reservation_created_admin"
+	outboxReservationCreatedUser    = "reservation_created_user"
+	outboxReservationCancelledAdmin = "reservation_cancelled_admin"
+	outboxReservationCancelledUser  = "reservation_cancelled_user"
+)
+
+// errOutboxPermanent marks messages that will never be delivered however many times we retry.
+var errOutboxPermanent = errors.New("permanent outbox failure")
+
+type (
+	// OutboxNotifier delivers outbox messages. Admin messages are queued per recipient and
+	// sent through ForAdmin, so a retry reaches only the address that failed.
+	OutboxNotifier interface {
+		Notifier
+		ForAdmin(r entities.AdminRecipient) (Notifier, error)
+	}
+
+	OutboxDependencies struct {
+		Repo     repository.IOutbox
+		Notifier OutboxNotifier
+		Config   *configuration.Outbox
+		Logger   *slog.Logger
+	}
+
+	Outbox struct {
+		repo     repository.IOutbox
+		notifier OutboxNotifier
+		config   *configuration.Outbox
+		logger   *slog.Logger
+	}
+
+	// createdForAdminPayload is the copy of the message for one admin recipient. Messages
+	// queued before the per-recipient fan-out have no Recipient and go to every admin.
+	createdForAdminPayload struct {
+		entities.ReservationCreatedMessage
+		Recipient *entities.AdminRecipient `json:",omitempty"`
+	}
+
+	cancelledForAdminPayload struct {
+		entities.ReservationCancelledMessage
+		Recipient *entities.AdminRecipient `json:",omitempty"`
+	}
+
+	createdForUserPayload struct {
+		Message entities.ReservationCreatedMessage
+		TgID    int64
+	}
+)
+
+func NewOutbox(d *OutboxDependencies) (*Outbox, error) {
+	const method = "usecases.NewOutbox"
+	if d == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "whole", "nil")
+	}
+	if d.Repo == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "Repo", "nil")
+	}
+	if d.Notifier == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "Notifier", "nil")
+	}
+	if d.Config == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "Config", "nil")
+	}
+	if d.Config.BatchSize <= 0 || d.Config.MaxAttempts <= 0 || d.Config.Lease <= 0 {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "Config", "BatchSize, MaxAttempts and Lease must be positive")
+	}
+	if d.Config.BaseBackoff <= 0 || d.Config.MaxBackoff < d.Config.BaseBackoff {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "Config", "invalid backoff")
+	}
+
+	logger := d.Logger.With(zeroslog.UsecaseKey, "Outbox")
+
+	return &Outbox{
+		repo:     d.Repo,
+		notifier: d.Notifier,
+		config:   d.Config,
+		logger:   logger,
+	}, nil
+}
+
+// Process delivers due outbox messages. Temporary failures are rescheduled with exponential
+// backoff, permanent ones and those out of attempts are marked failed for admin review.
+func (o *Outbox) Process(ctx context.Context) error {
+	messages, err := o.repo.Claim(ctx, o.config.BatchSize, o.config.Lease)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, m := range messages {
+		if err = o.complete(ctx, m, o.deliver(m)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (o *Outbox) GetFailed(ctx context.Context) ([]entities.OutboxMessage, error) {
+	return o.repo.GetFailed(ctx)
+}
+
+func (o *Outbox) Retry(ctx context.Context, id int64) error {
+	return o.repo.Requeue(ctx, id)
+}
+
+func (o *Outbox) deliver(m entities.OutboxMessage) error {
+	switch m.Kind {
+	case outboxReservationCreatedAdmin:
+		var p createdForAdminPayload
+		if err := json.Unmarshal(m.Payload, &p); err != nil {
+			return fmt.Errorf("%w: decode payload: %v", errOutboxPermanent, err)
+		}
+		notifier, err := o.forAdmin(p.Recipient)
+		if err != nil {
+			return err
+		}
+		return notifier.ReservationCreatedForAdmin(p.ReservationCreatedMessage)
+	case outboxReservationCreatedUser:
+		var p createdForUserPayload
+		if err := json.Unmarshal(m.Payload, &p); err != nil {
+			return fmt.Errorf("%w: decode payload: %v", errOutboxPermanent, err)
+		}
+		return o.notifier.ReservationCreatedForUser(p.Message, p.TgID)
+	case outboxReservationCancelledAdmin:
+		var p cancelledForAdminPayload
+		if err := json.Unmarshal(m.Payload, &p); err != nil {
+			return fmt.Errorf("%w: decode payload: %v", errOutboxPermanent, err)
+		}
+		notifier, err := o.forAdmin(p.Recipient)
+		if err != nil {
+			return err
+		}
+		return notifier.ReservationCancelledForAdmin(p.ReservationCancelledMessage)
+	case outboxReservationCancelledUser:
+		var msg entities.ReservationCancelledMessage
+		if err := json.Unmarshal(m.Payload, &msg); err != nil {
+			return fmt.Errorf("%w: decode payload: %v", errOutboxPermanent, err)
+		}
+		return o.notifier.ReservationCancelledForUser(msg)
+	default:
+		return fmt.Errorf("%w: unknown kind %q", errOutboxPermanent, m.Kind)
+	}
+}
+
+// forAdmin narrows delivery to the message's recipient. An address removed from the
+// configuration since the message was queued can never be delivered.
+func (o *Outbox) forAdmin(r *entities.AdminRecipient) (Notifier, error) {
+	if r == nil {
+		return o.notifier, nil
+	}
+	notifier, err := o.notifier.ForAdmin(*r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errOutboxPermanent, err)
+	}
+	return notifier, nil
+}
+
+func (o *Outbox) complete(ctx context.Context, m entities.OutboxMessage, sendErr error) error {
+	if sendErr == nil {
+		return o.repo.MarkSent(ctx, m.ID)
+	}
+
+	attempts := m.Attempts + 1
+	permanent := errors.Is(sendErr, errOutboxPermanent) || errors.Is(sendErr, errNoGuestChannel)
+	if permanent || attempts >= o.config.MaxAttempts {
+		o.logger.Error("notification failed, needs admin review",
+			zeroslog.ErrorKey, sendErr, "id", m.ID, "kind", m.Kind, "attempts", attempts)
+		return o.repo.MarkFailed(ctx, m.ID, attempts, sendErr.Error())
+	}
+
+	delay := o.backoff(attempts, sendErr)
+	o.logger.Warn("notification failed, will retry",
+		zeroslog.ErrorKey, sendErr, "id", m.ID, "kind", m.Kind, "attempts", attempts, "retryIn", delay)
+
+	return o.repo.MarkRetry(ctx, m.ID, attempts, time.Now().Add(delay), sendErr.Error())
+}
+
+// backoff doubles the delay on every attempt up to MaxBackoff, but never waits less than
+// the channel asked for (Telegram's retry_after).
+func (o *Outbox) backoff(attempts int, sendErr error) time.Duration {
+	delay := o.config.BaseBackoff
+	for i := 1; i < attempts && delay < o.config.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > o.config.MaxBackoff {
+		delay = o.config.MaxBackoff
+	}
+
+	var retryAfter *errorspkg.ErrRetryAfter
+	if errors.As(sendErr, &retryAfter) && retryAfter.After > delay {
+		delay = retryAfter.After
+	}
+
+	return delay
+}
+
+func newOutboxMessage(kind string, payload any) (entities.OutboxMessage, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return entities.OutboxMessage{}, err
+	}
+
+	return entities.OutboxMessage{
+		Kind:    kind,
+		Payload: data,
+		Status:  entities.OutboxPending,
+	}, nil
+}
+
+// newAdminOutboxMessages queues one message per admin recipient, payload wraps the message
+// for the given recipient.
+func newAdminOutboxMessages(
+	kind string,
+	recipients []entities.AdminRecipient,
+	payload func(r *entities.AdminRecipient) any,
+) ([]entities.OutboxMessage, error) {
+	res := make([]entities.OutboxMessage, 0, len(recipients)+1)
+	for _, r := range recipients {
+		m, err := newOutboxMessage(kind, payload(&r))
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, m)
+	}
+	return res, nil
+}
@@ -0,0 +1,214 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/calyrexx/QuietGrooveBackend/internal/configuration"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/repository"
+	"github.com/google/uuid"
+)
+
+// sentLog is shared by a fake channel and the copies ForAdmin makes of it.
+type sentLog struct {
+	sent []string
+	fail map[string]bool
+}
+
+// fakeChannel sends to admins one by one like the real adapters and logs "<kind> <address>".
+type fakeChannel struct {
+	ChannelNotifier
+	admins []string
+	log    *sentLog
+}
+
+func (c *fakeChannel) AdminRecipients() []string {
+	return c.admins
+}
+
+func (c *fakeChannel) ForAdmin(recipient string) (ChannelNotifier, bool) {
+	if !slices.Contains(c.admins, recipient) {
+		return nil, false
+	}
+	return &fakeChannel{admins: []string{recipient}, log: c.log}, true
+}
+
+func (c *fakeChannel) ReservationCancelledForAdmin(entities.ReservationCancelledMessage) error {
+	var errs []error
+	for _, admin := range c.admins {
+		if err := c.send("cancelled_admin", admin); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (c *fakeChannel) ReservationCancelledForUser(msg entities.ReservationCancelledMessage) error {
+	return c.send("cancelled_user", msg.GuestEmail)
+}
+
+func (c *fakeChannel) send(kind, to string) error {
+	if c.log.fail[to] {
+		return errors.New("unreachable " + to)
+	}
+	c.log.sent = append(c.log.sent, kind+" "+to)
+	return nil
+}
+
+type fakeDeliveries struct {
+	repository.INotifications
+	deliveries []entities.NotificationDelivery
+}
+
+func (r *fakeDeliveries) AddDelivery(_ context.Context, d entities.NotificationDelivery) error {
+	r.deliveries = append(r.deliveries, d)
+	return nil
+}
+
+type fakeGuestsRepo struct {
+	repository.IGuests
+}
+
+func (fakeGuestsRepo) GetNotifyChannels(context.Context, uuid.UUID) ([]entities.NotificationChannel, error) {
+	return nil, nil
+}
+
+// fakeOutboxRepo hands out every pending message on Claim, ignoring the schedule.
+type fakeOutboxRepo struct {
+	repository.IOutbox
+	messages []entities.OutboxMessage
+}
+
+func (r *fakeOutboxRepo) Claim(context.Context, int, time.Duration) ([]entities.OutboxMessage, error) {
+	var res []entities.OutboxMessage
+	for _, m := range r.messages {
+		if m.Status == entities.OutboxPending {
+			res = append(res, m)
+		}
+	}
+	return res, nil
+}
+
+func (r *fakeOutboxRepo) MarkSent(_ context.Context, id int64) error {
+	r.messages[id-1].Status = entities.OutboxSent
+	return nil
+}
+
+func (r *fakeOutboxRepo) MarkRetry(_ context.Context, id int64, attempts int, _ time.Time, lastErr string) error {
+	r.messages[id-1].Attempts, r.messages[id-1].LastError = attempts, lastErr
+	return nil
+}
+
+// fakeReservationsRepo cancels by queueing the notifications into the outbox, as the
+// transaction in ReservationsRepo.cancel does.
+type fakeReservationsRepo struct {
+	repository.IReservations
+	outbox *fakeOutboxRepo
+	msg    entities.ReservationCancelledMessage
+}
+
+func (r *fakeReservationsRepo) CancelByAdmin(_ context.Context, _ string, notify repository.CancelNotifications) error {
+	messages, err := notify(r.msg)
+	if err != nil {
+		return err
+	}
+	for _, m := range messages {
+		m.ID = int64(len(r.outbox.messages) + 1)
+		r.outbox.messages = append(r.outbox.messages, m)
+	}
+	return nil
+}
+
+func TestCancelNotificationsAreQueuedPerAdmin(t *testing.T) {
+	log := &sentLog{fail: map[string]bool{"b@quietgrove.test": true}}
+	deliveries := &fakeDeliveries{}
+	notifications, err := NewNotifications(&NotificationsDependencies{
+		Channels: map[entities.NotificationChannel]ChannelNotifier{
+			entities.ChannelEmail: &fakeChannel{admins: []string{"a@quietgrove.test", "b@quietgrove.test"}, log: log},
+		},
+		Repo:      deliveries,
+		GuestRepo: fakeGuestsRepo{},
+		Config: &configuration.Notifications{
+			GuestChannels: []string{"email"},
+			AdminChannels: []string{"email"},
+		},
+		Logger: discardLogger(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outboxRepo := &fakeOutboxRepo{}
+	reservations, err := NewReservation(&ReservationDependencies{
+		ReservationRepo: &fakeReservationsRepo{
+			outbox: outboxRepo,
+			msg:    entities.ReservationCancelledMessage{UUID: uuid.New(), GuestEmail: "guest@example.com"},
+		},
+		GuestRepo:     fakeGuestsRepo{},
+		HouseRepo:     fakeHousesRepo{},
+		BathhouseRepo: struct{ repository.IBathhouses }{},
+		Loyalty:       struct{ ReservationLoyalty }{},
+		Config:        &configuration.Reservations{},
+		Logger:        discardLogger(),
+		Notifier:      notifications,
+		Admins:        notifications,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	outbox, err := NewOutbox(&OutboxDependencies{
+		Repo:     outboxRepo,
+		Notifier: notifications,
+		Config:   &configuration.Outbox{BatchSize: 10, MaxAttempts: 3, BaseBackoff: time.Second, MaxBackoff: time.Minute, Lease: time.Minute},
+		Logger:   discardLogger(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	// Nothing is sent on cancel, the outbox gets one message per admin address and one for the guest.
+	if err = reservations.CancelByAdmin(ctx, "any"); err != nil {
+		t.Fatalf("CancelByAdmin: %v", err)
+	}
+	if len(log.sent) != 0 {
+		t.Fatalf("sent on cancel: %v", log.sent)
+	}
+	if len(outboxRepo.messages) != 3 {
+		t.Fatalf("outbox has %d messages, want 3", len(outboxRepo.messages))
+	}
+
+	// The unreachable admin is retried alone, the others are done.
+	if err = outbox.Process(ctx); err != nil {
+		t.Fatalf("first run: %v", err)
+	}
+	want := []string{"cancelled_admin a@quietgrove.test", "cancelled_user guest@example.com"}
+	if !slices.Equal(log.sent, want) {
+		t.Fatalf("first run sent %v, want %v", log.sent, want)
+	}
+	if failed := outboxRepo.messages[1]; failed.Status != entities.OutboxPending || failed.Attempts != 1 {
+		t.Errorf("message for b = %+v, want pending after 1 attempt", failed)
+	}
+	var failedDeliveries []string
+	for _, d := range deliveries.deliveries {
+		if d.Status == entities.DeliveryFailed {
+			failedDeliveries = append(failedDeliveries, d.Recipient)
+		}
+	}
+	if !slices.Equal(failedDeliveries, []string{"b@quietgrove.test"}) {
+		t.Errorf("failed deliveries recorded for %v, want only b", failedDeliveries)
+	}
+
+	log.fail = nil
+	log.sent = nil
+	if err = outbox.Process(ctx); err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	if want = []string{"cancelled_admin b@quietgrove.test"}; !slices.Equal(log.sent, want) {
+		t.Errorf("retry sent %v, want %v", log.sent, want)
+	}
+}
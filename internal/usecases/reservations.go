@@ -2,13 +2,18 @@ package usecases
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/calyrexx/QuietGrooveBackend/internal/configuration"
 	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
 	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/phonepkg"
 	"github.com/calyrexx/QuietGrooveBackend/internal/repository"
 	"github.com/calyrexx/zeroslog"
+	"github.com/google/uuid"
 	"log/slog"
+	"slices"
+	"sort"
 	"time"
 )
 
@@ -16,26 +21,42 @@ const (
 	reservationConfirmed  = "confirmed"
 	reservationCheckedIn  = "checked_in"
 	reservationCheckedOut = "checked_out"
-	barnhouseImg          = "https://res.cloudinary.com/dxmp5yjmb/image/upload/v1747237710/houses1_ebawfo.webp"
-	cottageImg            = "https://res.cloudinary.com/dxmp5yjmb/image/upload/v1747237737/houses8_pbv273.jpg"
-	glampingImg           = "https://res.cloudinary.com/dxmp5yjmb/image/upload/v1747237765/houses15_djgvjf.webp"
+
+	reminderAnchorCheckIn  = "check_in"
+	reminderAnchorCheckOut = "check_out"
+	reminderSent           = "sent"
+	reminderSkipped        = "skipped"
 )
 
 type (
 	Notifier interface {
 		ReservationCreatedForAdmin(res entities.ReservationCreatedMessage) error
 		ReservationCreatedForUser(res entities.ReservationCreatedMessage, tgID int64) error
+		ReservationCancelledForAdmin(res entities.ReservationCancelledMessage) error
+		ReservationCancelledForUser(res entities.ReservationCancelledMessage) error
 		RemindUser(msg []entities.ReservationReminderNotification) error
 	}
 
+	// AdminRecipients lists the admin addresses outbox messages are queued for, one per address.
+	AdminRecipients interface {
+		AdminRecipients() []entities.AdminRecipient
+	}
+
+	// ReservationLoyalty gives the guest's loyalty discount in percent.
+	ReservationLoyalty interface {
+		Discount(ctx context.Context, guestUUID uuid.UUID) (int, error)
+	}
+
 	ReservationDependencies struct {
 		ReservationRepo repository.IReservations
 		GuestRepo       repository.IGuests
 		HouseRepo       repository.IHouses
 		BathhouseRepo   repository.IBathhouses
+		Loyalty         ReservationLoyalty
 		Config          *configuration.Reservations
 		Logger          *slog.Logger
 		Notifier        Notifier
+		Admins          AdminRecipients
 	}
 
 	Reservation struct {
@@ -43,9 +64,11 @@ type (
 		guestRepo       repository.IGuests
 		houseRepo       repository.IHouses
 		bathhouseRepo   repository.IBathhouses
+		loyalty         ReservationLoyalty
 		config          *configuration.Reservations
 		logger          *slog.Logger
 		notifier        Notifier
+		admins          AdminRecipients
 	}
 )
 
@@ -66,12 +89,28 @@ func NewReservation(d *ReservationDependencies) (*Reservation, error) {
 	if d.BathhouseRepo == nil {
 		return nil, errorspkg.NewErrConstructorDependencies(method, "BathhouseRepo", "nil")
 	}
+	if d.Loyalty == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "Loyalty", "nil")
+	}
 	if d.Config == nil {
 		return nil, errorspkg.NewErrConstructorDependencies(method, "Config", "nil")
 	}
 	if d.Notifier == nil {
 		return nil, errorspkg.NewErrConstructorDependencies(method, "Notifier", "nil")
 	}
+	if d.Admins == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "Admins", "nil")
+	}
+	stageNames := make(map[string]struct{}, len(d.Config.ReminderStages))
+	for _, stage := range d.Config.ReminderStages {
+		if stage.Anchor != reminderAnchorCheckIn && stage.Anchor != reminderAnchorCheckOut {
+			return nil, errorspkg.NewErrConstructorDependencies(method, "ReminderStages", "unknown anchor "+stage.Anchor)
+		}
+		if _, ok := stageNames[stage.Name]; ok || stage.Name == "" || stage.DaysBefore < 0 {
+			return nil, errorspkg.NewErrConstructorDependencies(method, "ReminderStages", "invalid stage "+stage.Name)
+		}
+		stageNames[stage.Name] = struct{}{}
+	}
 
 	logger := d.Logger.With(zeroslog.UsecaseKey, "Reservation")
 
@@ -80,29 +119,32 @@ func NewReservation(d *ReservationDependencies) (*Reservation, error) {
 		guestRepo:       d.GuestRepo,
 		houseRepo:       d.HouseRepo,
 		bathhouseRepo:   d.BathhouseRepo,
+		loyalty:         d.Loyalty,
 		config:          d.Config,
 		logger:          logger,
 		notifier:        d.Notifier,
+		admins:          d.Admins,
 	}, nil
 }
 
 func (u *Reservation) GetAvailableHouses(ctx context.Context, req entities.GetAvailableHouses) ([]GetAvailableHousesResponse, error) {
-	availableIDs, err := u.reservationRepo.GetAvailableHouses(ctx, req)
+	tags, err := normalizeTags(req.Tags)
 	if err != nil {
 		return nil, err
 	}
+	req.Tags = tags
 
-	response := make([]GetAvailableHousesResponse, 0, len(availableIDs))
-	for _, id := range availableIDs {
-		house, repoErr := u.houseRepo.GetOne(ctx, id)
-		if repoErr != nil {
-			return nil, repoErr
-		}
-		nights := int(req.CheckOut.Sub(req.CheckIn).Hours() / 24)
+	available, err := u.reservationRepo.GetAvailableHouses(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	nights := nightsBetween(req.CheckIn, req.CheckOut)
+	response := make([]GetAvailableHousesResponse, 0, len(available))
+	for _, a := range available {
+		house := a.House
 		totalPrice := u.calculateTotalPrice(house.BasePrice, 0, req.CheckIn, req.CheckOut)
 		price := totalPrice / nights
-		// This is костыль
-		bathhouses, _ := u.bathhouseRepo.GetByHouse(ctx, id)
 		response = append(response, GetAvailableHousesResponse{
 			ID:            house.ID,
 			Name:          house.Name,
@@ -110,10 +152,13 @@ func (u *Reservation) GetAvailableHouses(ctx context.Context, req entities.GetAv
 			Capacity:      house.Capacity,
 			BasePrice:     price,
 			TotalPrice:    totalPrice,
+			CoverImage:    house.CoverImage,
 			Images:        house.Images,
 			CheckInFrom:   house.CheckInFrom,
 			CheckOutUntil: house.CheckOutUntil,
-			Bathhouses:    u.convertBathhouseToSlots(bathhouses, req.CheckIn, req.CheckOut),
+			Amenities:     house.Amenities,
+			Tags:          house.Tags,
+			Bathhouses:    u.convertBathhouseToSlots(a.Bathhouses, req.CheckIn, req.CheckOut),
 		})
 	}
 
@@ -123,6 +168,11 @@ func (u *Reservation) GetAvailableHouses(ctx context.Context, req entities.GetAv
 func (u *Reservation) CreateReservation(ctx context.Context, req CreateReservationRequest) (entities.Reservation, error) {
 	response := entities.Reservation{}
 
+	phone, err := phonepkg.Normalize(req.Guest.Phone)
+	if err != nil {
+		return response, err
+	}
+
 	available, err := u.reservationRepo.CheckAvailability(ctx, entities.CheckAvailability{
 		HouseId:  req.HouseID,
 		CheckIn:  req.CheckIn,
@@ -135,7 +185,7 @@ func (u *Reservation) CreateReservation(ctx context.Context, req CreateReservati
 		return response, errorspkg.NewErrHouseUnavailable(req.HouseID, req.CheckIn, req.CheckOut)
 	}
 
-	guest, err := u.guestRepo.Get(ctx, req.Guest)
+	guest, err := u.guestRepo.Find(ctx, phone, phonepkg.NormalizeEmail(req.Guest.Email))
 	if err != nil {
 		return response, err
 	}
@@ -145,9 +195,16 @@ func (u *Reservation) CreateReservation(ctx context.Context, req CreateReservati
 		return response, err
 	}
 
+	discount, err := u.loyalty.Discount(ctx, guest.UUID)
+	if err != nil {
+		return response, err
+	}
+
 	totalPrice := u.calculateTotalPrice(basePrice.House, basePrice.Extras, req.CheckIn, req.CheckOut)
+	totalPrice -= totalPrice * discount / 100
 
 	reservation := entities.Reservation{
+		UUID:        uuid.New(),
 		HouseID:     req.HouseID,
 		GuestUUID:   guest.UUID,
 		CheckIn:     req.CheckIn,
@@ -155,52 +212,88 @@ func (u *Reservation) CreateReservation(ctx context.Context, req CreateReservati
 		GuestsCount: req.GuestsCount,
 		Status:      reservationConfirmed,
 		TotalPrice:  totalPrice,
+		Discount:    discount,
 		Bathhouse:   req.Bathhouse,
 	}
 
-	if err = u.reservationRepo.Create(ctx, reservation); err != nil {
+	notifications, err := u.createdNotifications(ctx, reservation, guest)
+	if err != nil {
 		return response, err
 	}
 
-	go func(res entities.Reservation, guestTgID int64) {
-		house, _ := u.houseRepo.GetOne(context.Background(), res.HouseID)
-		bathhouseMsg := make([]entities.BathhouseMessage, 0, len(res.Bathhouse))
-		for _, reqBh := range req.Bathhouse {
-			bh, _ := u.bathhouseRepo.GetByID(context.Background(), reqBh.TypeID)
-			var fillOption *string
-			for _, bhFillOptions := range bh.FillOptions {
-				if bhFillOptions.ID == reqBh.FillOptionID {
-					fillOption = &bhFillOptions.Name
-				}
-			}
-			bathhouseMsg = append(bathhouseMsg, entities.BathhouseMessage{
-				Name:       bh.Name,
-				Date:       reqBh.Date,
-				TimeFrom:   reqBh.TimeFrom,
-				TimeTo:     reqBh.TimeTo,
-				FillOption: fillOption,
-			})
-		}
+	if _, err = u.reservationRepo.Create(ctx, reservation, notifications); err != nil {
+		return response, err
+	}
 
-		reservationMsg := entities.ReservationCreatedMessage{
-			HouseName:   house.Name,
-			GuestName:   guest.Name,
-			GuestPhone:  guest.Phone,
-			CheckIn:     res.CheckIn,
-			CheckOut:    res.CheckOut,
-			GuestsCount: res.GuestsCount,
-			TotalPrice:  res.TotalPrice,
-			Bathhouse:   bathhouseMsg,
-		}
-		if errSend := u.notifier.ReservationCreatedForAdmin(reservationMsg); errSend != nil {
-			u.logger.Error("telegram notify", zeroslog.ErrorKey, err)
+	return reservation, nil
+}
+
+// createdNotifications prepares outbox messages that are stored together with the reservation
+// and delivered later by the outbox worker.
+func (u *Reservation) createdNotifications(
+	ctx context.Context,
+	res entities.Reservation,
+	guest repository.Guest,
+) ([]entities.OutboxMessage, error) {
+	house, err := u.houseRepo.GetOne(ctx, res.HouseID)
+	if err != nil {
+		return nil, err
+	}
+
+	bathhouseMsg := make([]entities.BathhouseMessage, 0, len(res.Bathhouse))
+	for _, reqBh := range res.Bathhouse {
+		bh, bhErr := u.bathhouseRepo.GetByID(ctx, reqBh.TypeID)
+		if bhErr != nil {
+			return nil, bhErr
 		}
-		if errSendToUser := u.notifier.ReservationCreatedForUser(reservationMsg, guestTgID); errSendToUser != nil {
-			u.logger.Error("telegram user notify", zeroslog.ErrorKey, err)
+		var fillOption *string
+		for _, bhFillOptions := range bh.FillOptions {
+			if bhFillOptions.ID == reqBh.FillOptionID {
+				fillOption = &bhFillOptions.Name
+			}
 		}
-	}(reservation, guest.TgId)
+		bathhouseMsg = append(bathhouseMsg, entities.BathhouseMessage{
+			Name:       bh.Name,
+			Date:       reqBh.Date,
+			TimeFrom:   reqBh.TimeFrom,
+			TimeTo:     reqBh.TimeTo,
+			FillOption: fillOption,
+		})
+	}
 
-	return reservation, nil
+	reservationMsg := entities.ReservationCreatedMessage{
+		UUID:        res.UUID,
+		HouseName:   house.Name,
+		GuestUUID:   guest.UUID,
+		GuestName:   guest.Name,
+		GuestPhone:  guest.Phone,
+		GuestEmail:  guest.Email,
+		GuestLocale: guest.Locale,
+		CheckIn:     res.CheckIn,
+		CheckOut:    res.CheckOut,
+		GuestsCount: res.GuestsCount,
+		TotalPrice:  res.TotalPrice,
+		Discount:    res.Discount,
+		Bathhouse:   bathhouseMsg,
+	}
+
+	messages, err := newAdminOutboxMessages(outboxReservationCreatedAdmin, u.admins.AdminRecipients(),
+		func(r *entities.AdminRecipient) any {
+			return createdForAdminPayload{ReservationCreatedMessage: reservationMsg, Recipient: r}
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	forUser, err := newOutboxMessage(outboxReservationCreatedUser, createdForUserPayload{
+		Message: reservationMsg,
+		TgID:    guest.TgId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return append(messages, forUser), nil
 }
 
 func (u *Reservation) GetByTelegramID(ctx context.Context, userTgID int64) ([]entities.ReservationMessage, error) {
@@ -218,21 +311,13 @@ func (u *Reservation) GetDetailsByUUID(ctx context.Context, userTgID int64, uuid
 		return entities.ReservationMessage{}, err
 	}
 
-	switch res.HouseName {
-	case "Барнхаус":
-		res.ImageURL = barnhouseImg
-	case "Коттедж":
-		res.ImageURL = cottageImg
-	case "Глэмпинг":
-		res.ImageURL = glampingImg
-	}
-
 	return res, nil
 }
 
+// GetForReminder sends every reminder stage that came due. A stage missed during downtime
+// is caught up unless a later stage is due as well, then the older one is skipped.
 func (u *Reservation) GetForReminder(ctx context.Context) error {
 	const method = "GetForReminder"
-	u.logger.Info("starting remind users", "method", method)
 	timeNow := time.Now()
 
 	reservations, err := u.reservationRepo.GetAllForReminder(ctx)
@@ -240,27 +325,90 @@ func (u *Reservation) GetForReminder(ctx context.Context) error {
 		return err
 	}
 
-	notificationDate := timeNow.AddDate(0, 0, u.config.NotificationThreshold)
-
-	reservationsToRemind := make([]entities.ReservationReminderNotification, 0, len(reservations))
+	var (
+		errs []error
+		sent int
+	)
 	for _, reservation := range reservations {
-		if reservation.CheckIn.Year() == notificationDate.Year() &&
-			reservation.CheckIn.Month() == notificationDate.Month() &&
-			reservation.CheckIn.Day() == notificationDate.Day() {
-			reservationsToRemind = append(reservationsToRemind, reservation)
+		due := u.dueReminderStages(reservation, timeNow)
+		if len(due) == 0 {
+			continue
 		}
-	}
 
-	if err = u.notifier.RemindUser(reservationsToRemind); err != nil {
-		return err
+		for _, stage := range due[:len(due)-1] {
+			if _, err = u.reservationRepo.ClaimReminder(ctx, reservation.UUID, stage.Name, reminderSkipped); err != nil {
+				errs = append(errs, err)
+			}
+		}
+
+		stage := due[len(due)-1]
+		claimed, claimErr := u.reservationRepo.ClaimReminder(ctx, reservation.UUID, stage.Name, reminderSent)
+		if claimErr != nil {
+			errs = append(errs, claimErr)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		reservation.Stage = stage.Name
+		reservation.Anchor = stage.Anchor
+		reservation.DaysBefore = stage.DaysBefore
+
+		if err = u.notifier.RemindUser([]entities.ReservationReminderNotification{reservation}); err != nil {
+			errs = append(errs, err)
+			if err = u.reservationRepo.ReleaseReminder(ctx, reservation.UUID, stage.Name); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		sent++
 	}
 
-	if len(reservationsToRemind) > 0 {
+	if sent > 0 {
 		u.logger.Info(fmt.Sprintf("finished remind users in [%s]", time.Since(timeNow)),
-			"method", method, "reservations", len(reservationsToRemind))
+			"method", method, "reservations", sent)
 	}
 
-	return nil
+	return errors.Join(errs...)
+}
+
+// dueReminderStages returns unsent stages whose time has come, oldest first. A stage is
+// only relevant until its anchor day is over and only if the reservation existed by then.
+func (u *Reservation) dueReminderStages(
+	res entities.ReservationReminderNotification,
+	now time.Time,
+) []configuration.ReminderStage {
+	var due []configuration.ReminderStage
+	for _, stage := range u.config.ReminderStages {
+		if slices.Contains(res.SentStages, stage.Name) {
+			continue
+		}
+		dueAt, anchorDay := reminderDueAt(res, stage)
+		if now.Before(dueAt) || !now.Before(anchorDay.AddDate(0, 0, 1)) || res.CreatedAt.After(dueAt) {
+			continue
+		}
+		due = append(due, stage)
+	}
+
+	sort.SliceStable(due, func(i, j int) bool {
+		a, _ := reminderDueAt(res, due[i])
+		b, _ := reminderDueAt(res, due[j])
+		return a.Before(b)
+	})
+	return due
+}
+
+func reminderDueAt(
+	res entities.ReservationReminderNotification,
+	stage configuration.ReminderStage,
+) (dueAt, anchorDay time.Time) {
+	anchor := res.CheckIn
+	if stage.Anchor == reminderAnchorCheckOut {
+		anchor = res.CheckOut
+	}
+	anchorDay = time.Date(anchor.Year(), anchor.Month(), anchor.Day(), 0, 0, 0, 0, time.Local)
+	return anchorDay.AddDate(0, 0, -stage.DaysBefore).Add(stage.At), anchorDay
 }
 
 func (u *Reservation) UpdateStatuses(ctx context.Context) error {
@@ -278,6 +426,9 @@ func (u *Reservation) UpdateStatuses(ctx context.Context) error {
 		switch {
 		case reservation.CheckIn.Before(timeNow) || reservation.CheckIn.Equal(timeNow):
 			if reservation.CheckOut.After(timeNow) {
+				if reservation.Status == reservationCheckedIn {
+					continue
+				}
 				reservation.Status = reservationCheckedIn
 				reservationsToUpdate = append(reservationsToUpdate, reservation)
 			} else if reservation.CheckOut.Before(timeNow) || reservation.CheckOut.Equal(timeNow) {
@@ -300,7 +451,31 @@ func (u *Reservation) UpdateStatuses(ctx context.Context) error {
 }
 
 func (u *Reservation) Cancel(ctx context.Context, userTgID int64, uuid string) error {
-	return u.reservationRepo.Cancel(ctx, userTgID, uuid)
+	return u.reservationRepo.Cancel(ctx, userTgID, uuid, u.cancelledNotifications)
+}
+
+// CancelByAdmin cancels the reservation on the admin's behalf and notifies the guest.
+func (u *Reservation) CancelByAdmin(ctx context.Context, uuid string) error {
+	return u.reservationRepo.CancelByAdmin(ctx, uuid, u.cancelledNotifications)
+}
+
+// cancelledNotifications prepares outbox messages that are stored in the cancel transaction.
+func (u *Reservation) cancelledNotifications(msg entities.ReservationCancelledMessage) ([]entities.OutboxMessage, error) {
+	messages, err := newAdminOutboxMessages(outboxReservationCancelledAdmin, u.admins.AdminRecipients(),
+		func(r *entities.AdminRecipient) any {
+			return cancelledForAdminPayload{ReservationCancelledMessage: msg, Recipient: r}
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	forUser, err := newOutboxMessage(outboxReservationCancelledUser, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(messages, forUser), nil
 }
 
 func (u *Reservation) calculateTotalPrice(basePrice, extrasPrice int, checkIn, checkOut time.Time) int {
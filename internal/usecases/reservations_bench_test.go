@@ -0,0 +1,196 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/calyrexx/QuietGrooveBackend/internal/configuration"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/repository"
+)
+
+// benchRoundTrip is the latency charged for every query, about a database in the same network.
+const benchRoundTrip = 100 * time.Microsecond
+
+// benchStore keeps dozens of houses and thousands of reservations in memory and counts queries,
+// each one waiting benchRoundTrip as a database would.
+type benchStore struct {
+	houses     []entities.House
+	bathhouses map[int][]entities.Bathhouse
+	busy       map[int][]entities.DateRange
+	queries    int
+}
+
+func newBenchStore(houses, reservationsPerHouse int) *benchStore {
+	s := &benchStore{
+		bathhouses: make(map[int][]entities.Bathhouse, houses),
+		busy:       make(map[int][]entities.DateRange, houses),
+	}
+	start := testDate("2030-01-01")
+	for id := 1; id <= houses; id++ {
+		s.houses = append(s.houses, entities.House{ID: id, Name: fmt.Sprintf("Дом %d", id), Capacity: 6, BasePrice: 5000})
+		for n := 1; n <= 2; n++ {
+			bh := entities.Bathhouse{ID: id*10 + n, HouseID: id, Name: fmt.Sprintf("Баня %d", n), Price: 2000}
+			for f := 1; f <= 3; f++ {
+				bh.FillOptions = append(bh.FillOptions, entities.BathhouseFillOption{ID: bh.ID*10 + f, BathhouseID: bh.ID, Price: 500})
+			}
+			s.bathhouses[id] = append(s.bathhouses[id], bh)
+		}
+		// Two-night stays every four days, shifted by house so about half are free on any one night.
+		for k := range reservationsPerHouse {
+			from := start.AddDate(0, 0, k*4+id%4)
+			s.busy[id] = append(s.busy[id], entities.DateRange{From: from, To: from.AddDate(0, 0, 2)})
+		}
+	}
+	return s
+}
+
+func (s *benchStore) query() {
+	s.queries++
+	time.Sleep(benchRoundTrip)
+}
+
+func (s *benchStore) freeHouses(req entities.GetAvailableHouses) []entities.House {
+	var free []entities.House
+	for _, h := range s.houses {
+		if h.Capacity >= req.GuestsCount && !overlapsBusy(s.busy[h.ID], req.CheckIn, req.CheckOut) {
+			free = append(free, h)
+		}
+	}
+	return free
+}
+
+type benchReservationsRepo struct {
+	repository.IReservations
+	store *benchStore
+}
+
+// GetAvailableHouses answers in two queries like ReservationsRepo.GetAvailableHouses.
+func (r benchReservationsRepo) GetAvailableHouses(_ context.Context, req entities.GetAvailableHouses) ([]entities.AvailableHouse, error) {
+	r.store.query()
+	free := r.store.freeHouses(req)
+	r.store.query()
+	result := make([]entities.AvailableHouse, 0, len(free))
+	for _, h := range free {
+		result = append(result, entities.AvailableHouse{House: h, Bathhouses: r.store.bathhouses[h.ID]})
+	}
+	return result, nil
+}
+
+// freeHouseIDs is the id-only availability query the per-house loop started with.
+func (r benchReservationsRepo) freeHouseIDs(req entities.GetAvailableHouses) []int {
+	r.store.query()
+	var ids []int
+	for _, h := range r.store.freeHouses(req) {
+		ids = append(ids, h.ID)
+	}
+	return ids
+}
+
+type benchHousesRepo struct {
+	repository.IHouses
+	store *benchStore
+}
+
+func (r benchHousesRepo) GetOne(_ context.Context, id int) (entities.House, error) {
+	r.store.query()
+	return r.store.houses[id-1], nil
+}
+
+type benchBathhousesRepo struct {
+	repository.IBathhouses
+	store *benchStore
+}
+
+func (r benchBathhousesRepo) GetByHouse(_ context.Context, houseID int) ([]entities.Bathhouse, error) {
+	r.store.query()
+	return r.store.bathhouses[houseID], nil
+}
+
+// availableHousesPerHouse is GetAvailableHouses as it was before the repository loaded
+// the houses with their bathhouses: two more queries for every free house.
+func availableHousesPerHouse(ctx context.Context, u *Reservation, repo benchReservationsRepo, req entities.GetAvailableHouses) ([]GetAvailableHousesResponse, error) {
+	ids := repo.freeHouseIDs(req)
+
+	nights := nightsBetween(req.CheckIn, req.CheckOut)
+	response := make([]GetAvailableHousesResponse, 0, len(ids))
+	for _, id := range ids {
+		house, err := u.houseRepo.GetOne(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		bathhouses, err := u.bathhouseRepo.GetByHouse(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		totalPrice := u.calculateTotalPrice(house.BasePrice, 0, req.CheckIn, req.CheckOut)
+		response = append(response, GetAvailableHousesResponse{
+			ID:            house.ID,
+			Name:          house.Name,
+			Description:   house.Description,
+			Capacity:      house.Capacity,
+			BasePrice:     totalPrice / nights,
+			TotalPrice:    totalPrice,
+			CoverImage:    house.CoverImage,
+			Images:        house.Images,
+			CheckInFrom:   house.CheckInFrom,
+			CheckOutUntil: house.CheckOutUntil,
+			Amenities:     house.Amenities,
+			Tags:          house.Tags,
+			Bathhouses:    u.convertBathhouseToSlots(bathhouses, req.CheckIn, req.CheckOut),
+		})
+	}
+
+	return response, nil
+}
+
+// BenchmarkGetAvailableHouses compares the batched search with the per-house loop it replaced.
+// queries/op shows the round trips, see the postgres package for the same against a database.
+func BenchmarkGetAvailableHouses(b *testing.B) {
+	req := entities.GetAvailableHouses{
+		CheckIn:     testDate("2030-03-01"),
+		CheckOut:    testDate("2030-03-02"),
+		GuestsCount: 2,
+	}
+
+	for _, houses := range []int{12, 48} {
+		store := newBenchStore(houses, 5000/houses)
+		repo := benchReservationsRepo{store: store}
+		u, err := NewReservation(&ReservationDependencies{
+			ReservationRepo: repo,
+			GuestRepo:       fakeGuestsRepo{},
+			HouseRepo:       benchHousesRepo{store: store},
+			BathhouseRepo:   benchBathhousesRepo{store: store},
+			Loyalty:         struct{ ReservationLoyalty }{},
+			Config:          &configuration.Reservations{},
+			Logger:          discardLogger(),
+			Notifier:        struct{ Notifier }{},
+			Admins:          noAdmins{},
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		run := func(b *testing.B, search func() ([]GetAvailableHousesResponse, error)) {
+			store.queries = 0
+			for b.Loop() {
+				if _, err := search(); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(store.queries)/float64(b.N), "queries/op")
+		}
+		b.Run(fmt.Sprintf("houses=%d/batch", houses), func(b *testing.B) {
+			run(b, func() ([]GetAvailableHousesResponse, error) {
+				return u.GetAvailableHouses(context.Background(), req)
+			})
+		})
+		b.Run(fmt.Sprintf("houses=%d/per_house", houses), func(b *testing.B) {
+			run(b, func() ([]GetAvailableHousesResponse, error) {
+				return availableHousesPerHouse(context.Background(), u, repo, req)
+			})
+		})
+	}
+}
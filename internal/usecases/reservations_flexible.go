@@ -0,0 +1,235 @@
+package usecases
+
+import (
+	"context"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"slices"
+	"time"
+)
+
+const (
+	defaultShiftDays    = 3
+	maxShiftDays        = 14
+	maxNightsWindow     = 62
+	defaultAlternatives = 10
+	maxAlternatives     = 50
+)
+
+// stayCandidate is a stay to check, offset ranks it: days away from the requested check-in
+// for FlexibleShift, days from the first candidate otherwise.
+type stayCandidate struct {
+	checkIn  time.Time
+	checkOut time.Time
+	offset   int
+}
+
+// GetAlternatives finds free stays near the requested one in every house matching the filters
+// and returns them closest first, cheaper first among equally close ones. Every house gets
+// at least one of the limited slots while there are enough of them.
+func (u *Reservation) GetAlternatives(ctx context.Context, req entities.FlexibleSearch) ([]AlternativeStay, error) {
+	tags, err := normalizeTags(req.Filter.Tags)
+	if err != nil {
+		return nil, err
+	}
+	req.Filter.Tags = tags
+
+	candidates, err := flexibleCandidates(req, today())
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return []AlternativeStay{}, nil
+	}
+
+	window := req.Filter
+	window.CheckIn, window.CheckOut = candidates[0].checkIn, candidates[0].checkOut
+	for _, c := range candidates[1:] {
+		if c.checkIn.Before(window.CheckIn) {
+			window.CheckIn = c.checkIn
+		}
+		if c.checkOut.After(window.CheckOut) {
+			window.CheckOut = c.checkOut
+		}
+	}
+
+	occupancy, err := u.reservationRepo.GetOccupancy(ctx, window)
+	if err != nil {
+		return nil, err
+	}
+
+	free := make(map[int][]stayCandidate, len(occupancy))
+	ids := make([]int, 0, len(occupancy))
+	for _, o := range occupancy {
+		for _, c := range candidates {
+			if !overlapsBusy(o.Busy, c.checkIn, c.checkOut) {
+				free[o.HouseID] = append(free[o.HouseID], c)
+			}
+		}
+		if len(free[o.HouseID]) > 0 {
+			ids = append(ids, o.HouseID)
+		}
+	}
+	if len(ids) == 0 {
+		return []AlternativeStay{}, nil
+	}
+
+	houses, err := u.houseRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]AlternativeStay, 0)
+	for _, house := range houses {
+		for _, c := range free[house.ID] {
+			result = append(result, AlternativeStay{
+				HouseID:    house.ID,
+				Name:       house.Name,
+				CoverImage: house.CoverImage,
+				CheckIn:    c.checkIn.Format(time.DateOnly),
+				CheckOut:   c.checkOut.Format(time.DateOnly),
+				Nights:     nightsBetween(c.checkIn, c.checkOut),
+				TotalPrice: u.calculateTotalPrice(house.BasePrice, 0, c.checkIn, c.checkOut),
+				offset:     c.offset,
+			})
+		}
+	}
+
+	slices.SortStableFunc(result, compareAlternatives)
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultAlternatives
+	}
+
+	return pickAlternatives(result, min(limit, maxAlternatives)), nil
+}
+
+// compareAlternatives orders stays closest first, then cheaper first.
+func compareAlternatives(a, b AlternativeStay) int {
+	if a.offset != b.offset {
+		return a.offset - b.offset
+	}
+	if a.TotalPrice != b.TotalPrice {
+		return a.TotalPrice - b.TotalPrice
+	}
+	return a.HouseID - b.HouseID
+}
+
+// pickAlternatives cuts the sorted stays to limit so that a house with many free dates
+// doesn't crowd out the others: the best stay of every house is taken first, the slots
+// left go to the next best stays overall.
+func pickAlternatives(sorted []AlternativeStay, limit int) []AlternativeStay {
+	if len(sorted) <= limit {
+		return sorted
+	}
+
+	picked := make([]bool, len(sorted))
+	seen := make(map[int]bool)
+	count := 0
+	for i, stay := range sorted {
+		if count == limit {
+			break
+		}
+		if !seen[stay.HouseID] {
+			seen[stay.HouseID] = true
+			picked[i] = true
+			count++
+		}
+	}
+	for i := range sorted {
+		if count == limit {
+			break
+		}
+		if !picked[i] {
+			picked[i] = true
+			count++
+		}
+	}
+
+	result := make([]AlternativeStay, 0, limit)
+	for i, stay := range sorted {
+		if picked[i] {
+			result = append(result, stay)
+		}
+	}
+
+	return result
+}
+
+// flexibleCandidates lists the stays to check for the search mode, skipping ones starting before today.
+func flexibleCandidates(req entities.FlexibleSearch, today time.Time) ([]stayCandidate, error) {
+	var candidates []stayCandidate
+	add := func(checkIn, checkOut time.Time, offset int) {
+		if !checkIn.Before(today) {
+			candidates = append(candidates, stayCandidate{checkIn: checkIn, checkOut: checkOut, offset: offset})
+		}
+	}
+
+	switch req.Mode {
+	case entities.FlexibleShift:
+		days := req.Days
+		if days == 0 {
+			days = defaultShiftDays
+		}
+		if days < 0 || days > maxShiftDays || !req.Filter.CheckOut.After(req.Filter.CheckIn) {
+			return nil, errorspkg.ErrFlexibleRange
+		}
+		for d := -days; d <= days; d++ {
+			add(req.Filter.CheckIn.AddDate(0, 0, d), req.Filter.CheckOut.AddDate(0, 0, d), abs(d))
+		}
+
+	case entities.FlexibleWeekend:
+		if req.Month.IsZero() {
+			return nil, errorspkg.ErrFlexibleRange
+		}
+		first := time.Date(req.Month.Year(), req.Month.Month(), 1, 0, 0, 0, 0, time.UTC)
+		friday := first.AddDate(0, 0, (int(time.Friday)-int(first.Weekday())+7)%7)
+		for ; friday.Month() == first.Month(); friday = friday.AddDate(0, 0, 7) {
+			add(friday, friday.AddDate(0, 0, 2), nightsBetween(first, friday))
+		}
+
+	case entities.FlexibleNights:
+		from, to := req.Filter.CheckIn, req.Filter.CheckOut
+		window := nightsBetween(from, to)
+		if req.Nights <= 0 || req.Nights > window || window > maxNightsWindow {
+			return nil, errorspkg.ErrFlexibleRange
+		}
+		for d := 0; d+req.Nights <= window; d++ {
+			checkIn := from.AddDate(0, 0, d)
+			add(checkIn, checkIn.AddDate(0, 0, req.Nights), d)
+		}
+
+	default:
+		return nil, errorspkg.ErrFlexibleMode
+	}
+
+	return candidates, nil
+}
+
+// overlapsBusy reports whether [checkIn, checkOut) intersects any of the busy ranges.
+func overlapsBusy(busy []entities.DateRange, checkIn, checkOut time.Time) bool {
+	for _, b := range busy {
+		if b.From.Before(checkOut) && checkIn.Before(b.To) {
+			return true
+		}
+	}
+	return false
+}
+
+func nightsBetween(checkIn, checkOut time.Time) int {
+	return int(checkOut.Sub(checkIn).Hours() / 24)
+}
+
+// today is the current date at UTC midnight, as dates are parsed from the requests.
+func today() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
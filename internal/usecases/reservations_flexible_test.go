@@ -0,0 +1,89 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/calyrexx/QuietGrooveBackend/internal/configuration"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/repository"
+)
+
+type fakeOccupancyRepo struct {
+	repository.IReservations
+	occupancy []entities.HouseOccupancy
+}
+
+func (r fakeOccupancyRepo) GetOccupancy(context.Context, entities.GetAvailableHouses) ([]entities.HouseOccupancy, error) {
+	return r.occupancy, nil
+}
+
+type fakeHousesByIDs struct {
+	repository.IHouses
+}
+
+func (fakeHousesByIDs) GetByIDs(_ context.Context, ids []int) ([]entities.House, error) {
+	houses := make([]entities.House, 0, len(ids))
+	for _, id := range ids {
+		houses = append(houses, entities.House{ID: id, Name: fmt.Sprintf("Дом %d", id), BasePrice: 1000 * id})
+	}
+	return houses, nil
+}
+
+type noAdmins struct{}
+
+func (noAdmins) AdminRecipients() []entities.AdminRecipient {
+	return nil
+}
+
+func TestAlternativesKeepASlotPerHouse(t *testing.T) {
+	reservations, err := NewReservation(&ReservationDependencies{
+		ReservationRepo: fakeOccupancyRepo{occupancy: []entities.HouseOccupancy{
+			{HouseID: 1},
+			{HouseID: 2, Busy: []entities.DateRange{{From: testDate("2099-07-01"), To: testDate("2099-07-13")}}},
+			{HouseID: 3, Busy: []entities.DateRange{{From: testDate("2099-07-10"), To: testDate("2099-07-20")}}},
+		}},
+		GuestRepo:     fakeGuestsRepo{},
+		HouseRepo:     fakeHousesByIDs{},
+		BathhouseRepo: struct{ repository.IBathhouses }{},
+		Loyalty:       struct{ ReservationLoyalty }{},
+		Config:        &configuration.Reservations{},
+		Logger:        discardLogger(),
+		Notifier:      struct{ Notifier }{},
+		Admins:        noAdmins{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// House 1 is free on every shifted date, house 3 only two or three days earlier, house 2 only three days later.
+	tests := []struct {
+		limit int
+		want  []string
+	}{
+		{1, []string{"1 2099-07-10"}},
+		{3, []string{"1 2099-07-10", "3 2099-07-08", "2 2099-07-13"}},
+		{5, []string{"1 2099-07-10", "1 2099-07-09", "1 2099-07-11", "3 2099-07-08", "2 2099-07-13"}},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint("limit ", tt.limit), func(t *testing.T) {
+			stays, err := reservations.GetAlternatives(context.Background(), entities.FlexibleSearch{
+				Filter: entities.GetAvailableHouses{CheckIn: testDate("2099-07-10"), CheckOut: testDate("2099-07-12")},
+				Mode:   entities.FlexibleShift,
+				Limit:  tt.limit,
+			})
+			if err != nil {
+				t.Fatalf("GetAlternatives: %v", err)
+			}
+			got := make([]string, 0, len(stays))
+			for _, s := range stays {
+				got = append(got, fmt.Sprintf("%d %s", s.HouseID, s.CheckIn))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("stays = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -22,12 +22,27 @@ type GetAvailableHousesResponse struct {
 	Capacity      int
 	BasePrice     int
 	TotalPrice    int
+	CoverImage    string
 	Images        []string
 	CheckInFrom   string
 	CheckOutUntil string
+	Amenities     entities.HouseAmenities
+	Tags          []string
 	Bathhouses    []BathhouseSlots
 }
 
+// AlternativeStay is a free stay found by the flexible search, dates are YYYY-MM-DD.
+type AlternativeStay struct {
+	HouseID    int
+	Name       string
+	CoverImage string
+	CheckIn    string
+	CheckOut   string
+	Nights     int
+	TotalPrice int
+	offset     int
+}
+
 type BathhouseSlots struct {
 	TypeID     int
 	Name       string
@@ -0,0 +1,138 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/calyrexx/QuietGrooveBackend/internal/configuration"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/calyrexx/QuietGrooveBackend/internal/repository"
+	"github.com/calyrexx/zeroslog"
+	"github.com/google/uuid"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+const maxReviewComment = 2000
+
+type (
+	FeedbackNotifier interface {
+		RequestFeedback(msg entities.FeedbackRequest) error
+	}
+
+	ReviewsDependencies struct {
+		Repo     repository.IReviews
+		Notifier FeedbackNotifier
+		Config   *configuration.Reviews
+		Logger   *slog.Logger
+	}
+
+	Reviews struct {
+		repo     repository.IReviews
+		notifier FeedbackNotifier
+		config   *configuration.Reviews
+		logger   *slog.Logger
+	}
+)
+
+func NewReviews(d *ReviewsDependencies) (*Reviews, error) {
+	const method = "usecases.NewReviews"
+	if d == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "whole", "nil")
+	}
+	if d.Repo == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "Repo", "nil")
+	}
+	if d.Notifier == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "Notifier", "nil")
+	}
+	if d.Config == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "Config", "nil")
+	}
+
+	logger := d.Logger.With(zeroslog.UsecaseKey, "Reviews")
+
+	return &Reviews{
+		repo:     d.Repo,
+		notifier: d.Notifier,
+		config:   d.Config,
+		logger:   logger,
+	}, nil
+}
+
+// RequestFeedback asks guests to rate the stay FeedbackDelay after check-out. Stays that
+// checked out more than FeedbackWindow ago are not asked any more.
+func (u *Reviews) RequestFeedback(ctx context.Context) error {
+	const method = "RequestFeedback"
+	timeNow := time.Now()
+
+	requests, err := u.repo.GetForFeedback(ctx,
+		timeNow.Add(-u.config.FeedbackDelay), timeNow.Add(-u.config.FeedbackWindow))
+	if err != nil {
+		return err
+	}
+
+	var (
+		errs []error
+		sent int
+	)
+	for _, req := range requests {
+		if err = u.notifier.RequestFeedback(req); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err = u.repo.MarkFeedbackRequested(ctx, req.ReservationUUID); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		sent++
+	}
+
+	if sent > 0 {
+		u.logger.Info(fmt.Sprintf("finished request feedback in [%s]", time.Since(timeNow)),
+			"method", method, "reservations", sent)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (u *Reviews) Rate(ctx context.Context, tgID int64, reservationUUID string, rating int) error {
+	if rating < 1 || rating > 5 {
+		return errorspkg.ErrInvalidRating
+	}
+	id, err := uuid.Parse(reservationUUID)
+	if err != nil {
+		return err
+	}
+	return u.repo.Rate(ctx, tgID, id, rating)
+}
+
+func (u *Reviews) Comment(ctx context.Context, tgID int64, comment string) error {
+	comment = strings.TrimSpace(comment)
+	if runes := []rune(comment); len(runes) > maxReviewComment {
+		comment = string(runes[:maxReviewComment])
+	}
+	return u.repo.SetComment(ctx, tgID, comment)
+}
+
+func (u *Reviews) SkipComment(ctx context.Context, tgID int64, reservationUUID string) error {
+	id, err := uuid.Parse(reservationUUID)
+	if err != nil {
+		return err
+	}
+	return u.repo.SkipComment(ctx, tgID, id)
+}
+
+func (u *Reviews) GetAll(ctx context.Context, status string) ([]entities.Review, error) {
+	return u.repo.GetAll(ctx, entities.ReviewStatus(status))
+}
+
+func (u *Reviews) Moderate(ctx context.Context, id int, status string) error {
+	s := entities.ReviewStatus(status)
+	if s != entities.ReviewApproved && s != entities.ReviewRejected {
+		return errorspkg.ErrInvalidReviewStatus
+	}
+	return u.repo.SetStatus(ctx, id, s)
+}
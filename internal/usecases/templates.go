@@ -0,0 +1,661 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/calyrexx/QuietGrooveBackend/internal/repository"
+	"github.com/calyrexx/zeroslog"
+	"github.com/google/uuid"
+	"io/fs"
+	"log/slog"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// Message types rendered through Templates.
+const (
+	TmplReservationCreatedAdmin   = "reservation_created_admin"
+	TmplReservationCreatedUser    = "reservation_created_user"
+	TmplReservationCancelledAdmin = "reservation_cancelled_admin"
+	TmplReservationCancelledUser  = "reservation_cancelled_user"
+	TmplReminder                  = "reminder"
+	TmplReminderCheckIn           = "reminder_check_in"
+	TmplReminderCheckOut          = "reminder_check_out"
+	TmplReminderButton            = "reminder_button"
+	TmplEventApplication          = "event_application"
+	TmplCalendarConflict          = "calendar_conflict"
+	TmplStart                     = "start"
+	TmplMenuMyReservations        = "menu_my_reservations"
+	TmplReservationsLoadFailed    = "reservations_load_failed"
+	TmplReservationsEmpty         = "reservations_empty"
+	TmplReservationsListTitle     = "reservations_list_title"
+	TmplReservationButton         = "reservation_button"
+	TmplReservationNotFound       = "reservation_not_found"
+	TmplReservationDetails        = "reservation_details"
+	TmplReservationCancelFailed   = "reservation_cancel_failed"
+	TmplReservationCancelledAlert = "reservation_cancelled_alert"
+	TmplButtonCancelReservation   = "button_cancel_reservation"
+	TmplButtonBack                = "button_back"
+	TmplVerificationInvalid       = "verification_invalid"
+	TmplVerificationSuccess       = "verification_success"
+	TmplVerificationLocked        = "verification_locked"
+	TmplVerificationSuspicious    = "verification_suspicious"
+	TmplVerificationConflict      = "verification_conflict"
+	TmplFeedbackRequest           = "feedback_request"
+	TmplFeedbackCommentPrompt     = "feedback_comment_prompt"
+	TmplFeedbackThanks            = "feedback_thanks"
+	TmplFeedbackFailed            = "feedback_failed"
+	TmplButtonSkip                = "button_skip"
+	TmplAdminAgenda               = "admin_agenda"
+	TmplAdminReservation          = "admin_reservation"
+	TmplAdminReservationButton    = "admin_reservation_button"
+	TmplAdminFound                = "admin_found"
+	TmplAdminNotFound             = "admin_not_found"
+	TmplAdminUsageReservation     = "admin_usage_reservation"
+	TmplAdminUsageBlock           = "admin_usage_block"
+	TmplAdminBlocked              = "admin_blocked"
+	TmplAdminUnavailable          = "admin_unavailable"
+	TmplAdminUnknownHouse         = "admin_unknown_house"
+	TmplAdminDone                 = "admin_done"
+	TmplAdminFailed               = "admin_failed"
+	TmplButtonAdminCancel         = "button_admin_cancel"
+	TmplButtonAdminCheckIn        = "button_admin_check_in"
+	TmplButtonAdminNoShow         = "button_admin_no_show"
+	TmplMenuBook                  = "menu_book"
+	TmplBookingNotVerified        = "booking_not_verified"
+	TmplBookingWeekdays           = "booking_weekdays"
+	TmplBookingCheckIn            = "booking_check_in"
+	TmplBookingCheckOut           = "booking_check_out"
+	TmplBookingGuests             = "booking_guests"
+	TmplBookingHouses             = "booking_houses"
+	TmplBookingNoHouses           = "booking_no_houses"
+	TmplBookingHouse              = "booking_house"
+	TmplBookingExtras             = "booking_extras"
+	TmplBookingExtraButton        = "booking_extra_button"
+	TmplBookingBathhouse          = "booking_bathhouse"
+	TmplBookingBathhouseButton    = "booking_bathhouse_button"
+	TmplBookingFillOption         = "booking_fill_option"
+	TmplBookingSummary            = "booking_summary"
+	TmplBookingCreated            = "booking_created"
+	TmplBookingUnavailable        = "booking_unavailable"
+	TmplBookingFailed             = "booking_failed"
+	TmplBookingCancelled          = "booking_cancelled"
+	TmplBookingExpired            = "booking_expired"
+	TmplBookingInvalidDate        = "booking_invalid_date"
+	TmplButtonNext                = "button_next"
+	TmplButtonChoose              = "button_choose"
+	TmplButtonBookingConfirm      = "button_booking_confirm"
+	TmplButtonBookingCancel       = "button_booking_cancel"
+	TmplButtonBookingRestart      = "button_booking_restart"
+	TmplPrivacyExport             = "privacy_export"
+	TmplPrivacyNotFound           = "privacy_not_found"
+	TmplPrivacyEraseConfirm       = "privacy_erase_confirm"
+	TmplPrivacyErased             = "privacy_erased"
+	TmplPrivacyActiveStays        = "privacy_active_stays"
+	TmplPrivacyFailed             = "privacy_failed"
+	TmplButtonPrivacyErase        = "button_privacy_erase"
+	TmplMenuLoyalty               = "menu_loyalty"
+	TmplLoyaltyStatus             = "loyalty_status"
+	TmplLoyaltyNotVerified        = "loyalty_not_verified"
+	TmplLoyaltyFailed             = "loyalty_failed"
+	TmplButtonApplicationStatus   = "button_application_status"
+	TmplSMSCreatedAdmin           = "sms_reservation_created_admin"
+	TmplSMSCreatedUser            = "sms_reservation_created_user"
+	TmplSMSCancelledAdmin         = "sms_reservation_cancelled_admin"
+	TmplSMSCancelledUser          = "sms_reservation_cancelled_user"
+	TmplSMSReminder               = "sms_reminder"
+	TmplSMSReminderCheckOut       = "sms_reminder_check_out"
+	TmplSMSEventApplication       = "sms_event_application"
+)
+
+var templateFuncs = template.FuncMap{
+	"date":     func(t time.Time) string { return t.Format("02.01.2006") },
+	"datetime": func(t time.Time) string { return t.Format("02.01.2006 15:04") },
+	"short":    func(t time.Time) string { return t.Format("02.01") },
+	"dots":     func(s string) string { return strings.ReplaceAll(s, "-", ".") },
+}
+
+type (
+	TemplatesDependencies struct {
+		Repo repository.ITemplates
+		// Defaults holds <locale>/<key>.tmpl files; the set of keys and locales comes from it.
+		Defaults      fs.FS
+		DefaultLocale string
+		// ReloadInterval is how long overrides are cached before they are read again, so
+		// edits made through another instance show up here too. Zero reads them every time.
+		ReloadInterval time.Duration
+		Logger         *slog.Logger
+	}
+
+	// Templates renders message texts by key and locale. An admin override stored in the
+	// database wins over the file default; a missing locale falls back to DefaultLocale.
+	Templates struct {
+		repo           repository.ITemplates
+		defaults       map[string]map[string]string // locale -> key -> body
+		locales        []string
+		defaultLocale  string
+		reloadInterval time.Duration
+		logger         *slog.Logger
+
+		mu        sync.RWMutex
+		loadedAt  time.Time
+		overrides map[string]entities.MessageTemplate // key/locale -> template
+	}
+)
+
+func NewTemplates(d *TemplatesDependencies) (*Templates, error) {
+	const method = "usecases.NewTemplates"
+	if d == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "whole", "nil")
+	}
+	if d.Repo == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "Repo", "nil")
+	}
+	if d.Defaults == nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "Defaults", "nil")
+	}
+	if d.ReloadInterval < 0 {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "ReloadInterval", "negative")
+	}
+
+	defaults, err := loadDefaultTemplates(d.Defaults)
+	if err != nil {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "Defaults", err.Error())
+	}
+	if _, ok := defaults[d.DefaultLocale]; !ok {
+		return nil, errorspkg.NewErrConstructorDependencies(method, "DefaultLocale", "no templates for "+d.DefaultLocale)
+	}
+
+	locales := make([]string, 0, len(defaults))
+	for locale := range defaults {
+		locales = append(locales, locale)
+	}
+	slices.Sort(locales)
+
+	logger := d.Logger.With(zeroslog.UsecaseKey, "Templates")
+
+	return &Templates{
+		repo:           d.Repo,
+		defaults:       defaults,
+		locales:        locales,
+		defaultLocale:  d.DefaultLocale,
+		reloadInterval: d.ReloadInterval,
+		logger:         logger,
+	}, nil
+}
+
+// Locale maps a Telegram language_code ("en-US", "ru") to a supported locale.
+func (t *Templates) Locale(languageCode string) string {
+	code := strings.ToLower(languageCode)
+	if i := strings.IndexAny(code, "-_"); i >= 0 {
+		code = code[:i]
+	}
+	if slices.Contains(t.locales, code) {
+		return code
+	}
+	return t.defaultLocale
+}
+
+func (t *Templates) Locales() []string {
+	return t.locales
+}
+
+func (t *Templates) Render(ctx context.Context, key, locale string, data any) (string, error) {
+	locale = t.Locale(locale)
+
+	body, ok := t.body(ctx, key, locale)
+	if !ok && locale != t.defaultLocale {
+		body, ok = t.body(ctx, key, t.defaultLocale)
+	}
+	if !ok {
+		return "", fmt.Errorf("%w: %s", errorspkg.ErrUnknownTemplate, key)
+	}
+
+	return execute(key, body, data)
+}
+
+func (t *Templates) GetAll(ctx context.Context) ([]entities.MessageTemplate, error) {
+	if err := t.load(ctx); err != nil {
+		return nil, err
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var res []entities.MessageTemplate
+	for _, locale := range t.locales {
+		keys := make([]string, 0, len(t.defaults[locale]))
+		for key := range t.defaults[locale] {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+
+		for _, key := range keys {
+			if override, ok := t.overrides[templateID(key, locale)]; ok {
+				res = append(res, override)
+				continue
+			}
+			res = append(res, entities.MessageTemplate{
+				Key:       key,
+				Locale:    locale,
+				Body:      t.defaults[locale][key],
+				IsDefault: true,
+			})
+		}
+	}
+
+	return res, nil
+}
+
+func (t *Templates) Update(ctx context.Context, key, locale, body string) error {
+	if !t.known(key, locale) {
+		return errorspkg.ErrUnknownTemplate
+	}
+	if _, err := execute(key, body, sampleTemplateData[key]); err != nil {
+		return fmt.Errorf("%w: %v", errorspkg.ErrInvalidTemplate, err)
+	}
+	if err := t.load(ctx); err != nil {
+		return err
+	}
+
+	tmpl := entities.MessageTemplate{Key: key, Locale: locale, Body: body}
+	if err := t.repo.Upsert(ctx, tmpl); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	tmpl.UpdatedAt = &now
+
+	t.mu.Lock()
+	t.overrides[templateID(key, locale)] = tmpl
+	t.mu.Unlock()
+
+	return nil
+}
+
+// Reset removes the admin override so the file default is used again.
+func (t *Templates) Reset(ctx context.Context, key, locale string) error {
+	if !t.known(key, locale) {
+		return errorspkg.ErrUnknownTemplate
+	}
+	if err := t.load(ctx); err != nil {
+		return err
+	}
+	if err := t.repo.Delete(ctx, key, locale); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	delete(t.overrides, templateID(key, locale))
+	t.mu.Unlock()
+
+	return nil
+}
+
+// Preview renders body (or the current template when body is empty) with sample data.
+func (t *Templates) Preview(ctx context.Context, key, locale, body string) (string, error) {
+	if !t.known(key, locale) {
+		return "", errorspkg.ErrUnknownTemplate
+	}
+	if body == "" {
+		body, _ = t.body(ctx, key, locale)
+	}
+
+	text, err := execute(key, body, sampleTemplateData[key])
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", errorspkg.ErrInvalidTemplate, err)
+	}
+	return text, nil
+}
+
+func (t *Templates) body(ctx context.Context, key, locale string) (string, bool) {
+	if err := t.load(ctx); err != nil {
+		t.logger.Warn("load template overrides, using defaults", zeroslog.ErrorKey, err)
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if override, ok := t.overrides[templateID(key, locale)]; ok {
+		return override.Body, true
+	}
+	body, ok := t.defaults[locale][key]
+	return body, ok
+}
+
+// load reads admin overrides when the cached ones are older than reloadInterval.
+// Update and Reset change the cache right away, other instances see them on their next reload.
+func (t *Templates) load(ctx context.Context) error {
+	t.mu.RLock()
+	fresh := t.overrides != nil && time.Since(t.loadedAt) < t.reloadInterval
+	t.mu.RUnlock()
+	if fresh {
+		return nil
+	}
+
+	loadedAt := time.Now()
+	templates, err := t.repo.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	overrides := make(map[string]entities.MessageTemplate, len(templates))
+	for _, tmpl := range templates {
+		overrides[templateID(tmpl.Key, tmpl.Locale)] = tmpl
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if loadedAt.Before(t.loadedAt) {
+		return nil
+	}
+	t.overrides = overrides
+	t.loadedAt = loadedAt
+
+	return nil
+}
+
+func (t *Templates) known(key, locale string) bool {
+	_, ok := t.defaults[locale][key]
+	return ok
+}
+
+func loadDefaultTemplates(fsys fs.FS) (map[string]map[string]string, error) {
+	files, err := fs.Glob(fsys, "*/*.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, errors.New("no *.tmpl files")
+	}
+
+	res := make(map[string]map[string]string)
+	for _, file := range files {
+		data, readErr := fs.ReadFile(fsys, file)
+		if readErr != nil {
+			return nil, readErr
+		}
+
+		locale := path.Dir(file)
+		key := strings.TrimSuffix(path.Base(file), ".tmpl")
+		if _, err = parseTemplate(key, string(data)); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+
+		if res[locale] == nil {
+			res[locale] = make(map[string]string)
+		}
+		res[locale][key] = string(data)
+	}
+
+	return res, nil
+}
+
+func parseTemplate(key, body string) (*template.Template, error) {
+	return template.New(key).Funcs(templateFuncs).Option("missingkey=error").Parse(body)
+}
+
+func execute(key, body string, data any) (string, error) {
+	tmpl, err := parseTemplate(key, body)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err = tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+func templateID(key, locale string) string {
+	return key + "/" + locale
+}
+
+var (
+	sampleCheckIn  = time.Date(2025, 7, 11, 0, 0, 0, 0, time.Local)
+	sampleCheckOut = sampleCheckIn.AddDate(0, 0, 2)
+	sampleFill     = "Травяной сбор"
+
+	sampleCreated = entities.ReservationCreatedMessage{
+		UUID:        uuid.MustParse("00000000-0000-0000-0000-000000000001"),
+		HouseName:   "Барнхаус",
+		GuestName:   "Иван Петров",
+		GuestPhone:  "+79990000000",
+		GuestEmail:  "guest@example.com",
+		CheckIn:     sampleCheckIn,
+		CheckOut:    sampleCheckOut,
+		GuestsCount: 4,
+		TotalPrice:  24000,
+		Discount:    5,
+		Bathhouse: []entities.BathhouseMessage{{
+			Name:       "Баня",
+			Date:       "2025-07-11",
+			TimeFrom:   "18:00",
+			TimeTo:     "20:00",
+			FillOption: &sampleFill,
+		}},
+	}
+
+	sampleCancelled = entities.ReservationCancelledMessage{
+		UUID:       sampleCreated.UUID,
+		HouseName:  sampleCreated.HouseName,
+		GuestName:  sampleCreated.GuestName,
+		GuestPhone: sampleCreated.GuestPhone,
+		CheckIn:    sampleCheckIn,
+		CheckOut:   sampleCheckOut,
+		TotalPrice: sampleCreated.TotalPrice,
+	}
+
+	sampleReminder = entities.ReservationReminderNotification{
+		UUID:          sampleCreated.UUID,
+		HouseName:     sampleCreated.HouseName,
+		CheckIn:       sampleCheckIn,
+		CheckOut:      sampleCheckOut,
+		CheckInFrom:   "14:00",
+		CheckOutUntil: "11:00",
+		Directions:    "Трасса М‑7, 112 км, после заправки направо, 2 км по грунтовке.",
+		GuestName:     sampleCreated.GuestName,
+		Stage:         "day",
+		Anchor:        reminderAnchorCheckIn,
+		DaysBefore:    1,
+	}
+
+	sampleReservation = entities.ReservationMessage{
+		UUID:        sampleCreated.UUID.String(),
+		HouseName:   sampleCreated.HouseName,
+		CheckIn:     sampleCheckIn,
+		CheckOut:    sampleCheckOut,
+		GuestsCount: 4,
+		Status:      reservationConfirmed,
+		TotalPrice:  24000,
+		Bathhouse: []entities.BathhouseReservationMessage{{
+			Name:           "Баня",
+			Date:           "2025-07-11",
+			TimeFrom:       "18:00",
+			TimeTo:         "20:00",
+			FillOptionName: &sampleFill,
+		}},
+	}
+
+	sampleApplication = entities.EventApplication{
+		ID:          12,
+		Name:        "Иван Петров",
+		Phone:       "+79990000000",
+		CheckIn:     "2025-07-11",
+		GuestsCount: 20,
+		Status:      entities.ApplicationContacted,
+		Notes:       "Корпоратив, нужен банкет",
+		Manager:     "@manager",
+	}
+
+	sampleAdminReservation = entities.AdminReservation{
+		UUID:        sampleCreated.UUID,
+		HouseName:   sampleCreated.HouseName,
+		GuestName:   sampleCreated.GuestName,
+		GuestPhone:  sampleCreated.GuestPhone,
+		GuestsCount: 4,
+		CheckIn:     sampleCheckIn,
+		CheckOut:    sampleCheckOut,
+		Status:      reservationConfirmed,
+		TotalPrice:  sampleCreated.TotalPrice,
+	}
+
+	sampleBookingSession = entities.BookingSession{
+		Step:        entities.BookingConfirm,
+		Month:       sampleCheckIn.AddDate(0, 0, 1-sampleCheckIn.Day()),
+		CheckIn:     sampleCheckIn,
+		CheckOut:    sampleCheckOut,
+		GuestsCount: 4,
+		HouseID:     1,
+		HouseName:   sampleCreated.HouseName,
+		StayPrice:   sampleCreated.TotalPrice,
+	}
+
+	sampleEventBooking = entities.EventBooking{
+		UUID:           sampleCreated.UUID,
+		Title:          "Свадьба Ивановых",
+		ContactName:    sampleCreated.GuestName,
+		ContactPhone:   sampleCreated.GuestPhone,
+		CheckIn:        sampleCheckIn,
+		CheckOut:       sampleCheckOut,
+		GuestsCount:    30,
+		HouseIDs:       []int{1, 2},
+		HouseNames:     []string{sampleCreated.HouseName, "Шале"},
+		BathhouseIDs:   []int{1},
+		BathhouseNames: []string{"Баня"},
+		TotalPrice:     250000,
+		Deposit:        50000,
+		Status:         entities.EventConfirmed,
+	}
+
+	sampleExtra = entities.Extra{
+		ID:        1,
+		Name:      "Завтрак",
+		BasePrice: 1500,
+	}
+
+	sampleTemplateData = map[string]any{
+		TmplReservationCreatedAdmin:   sampleCreated,
+		TmplReservationCreatedUser:    sampleCreated,
+		TmplReservationCancelledAdmin: sampleCancelled,
+		TmplReservationCancelledUser:  sampleCancelled,
+		TmplReminder:                  sampleReminder,
+		TmplReminderCheckIn:           sampleReminder,
+		TmplReminderCheckOut:          sampleReminder,
+		TmplReminderButton:            sampleReminder,
+		TmplEventApplication:          sampleApplication,
+		TmplButtonApplicationStatus:   entities.ApplicationQuoted,
+		TmplCalendarConflict: entities.CalendarConflictMessage{
+			SourceName: "Avito",
+			HouseName:  sampleCreated.HouseName,
+			CheckIn:    sampleCheckIn,
+			CheckOut:   sampleCheckOut,
+			Reservations: []entities.ReservationConflict{{
+				CheckIn:    sampleCheckIn,
+				CheckOut:   sampleCheckOut,
+				GuestName:  sampleCreated.GuestName,
+				GuestPhone: sampleCreated.GuestPhone,
+			}},
+		},
+		TmplVerificationLocked: &errorspkg.ErrVerificationLocked{
+			Until: sampleCheckIn.Add(15 * time.Minute),
+		},
+		TmplVerificationSuspicious: entities.SuspiciousVerification{
+			Reason:      entities.SuspiciousChatLocked,
+			TgID:        123456789,
+			Lockouts:    2,
+			LockedUntil: sampleCheckIn.Add(30 * time.Minute),
+			Name:        sampleCreated.GuestName,
+			Phone:       sampleCreated.GuestPhone,
+		},
+		TmplReservationButton:  sampleReservation,
+		TmplReservationDetails: sampleReservation,
+		TmplFeedbackRequest: entities.FeedbackRequest{
+			ReservationUUID: sampleCreated.UUID,
+			HouseName:       sampleCreated.HouseName,
+			GuestName:       sampleCreated.GuestName,
+			CheckIn:         sampleCheckIn,
+			CheckOut:        sampleCheckOut,
+		},
+		TmplAdminAgenda: entities.DayAgenda{
+			Date:       sampleCheckIn,
+			Arrivals:   []entities.AdminReservation{sampleAdminReservation},
+			Departures: []entities.AdminReservation{sampleAdminReservation},
+			Bathhouses: []entities.BathhouseSession{{
+				ReservationUUID: sampleCreated.UUID,
+				HouseName:       sampleCreated.HouseName,
+				Name:            "Баня",
+				TimeFrom:        "18:00",
+				TimeTo:          "20:00",
+				FillOption:      &sampleFill,
+				GuestName:       sampleCreated.GuestName,
+				GuestPhone:      sampleCreated.GuestPhone,
+			}},
+			Events: []entities.EventBooking{sampleEventBooking},
+		},
+		TmplAdminReservation:       sampleAdminReservation,
+		TmplAdminReservationButton: sampleAdminReservation,
+		TmplBookingCheckOut:        sampleBookingSession,
+		TmplBookingGuests:          sampleBookingSession,
+		TmplBookingHouses:          sampleBookingSession,
+		TmplBookingHouse: GetAvailableHousesResponse{
+			ID:            1,
+			Name:          sampleCreated.HouseName,
+			Capacity:      6,
+			BasePrice:     12000,
+			TotalPrice:    sampleCreated.TotalPrice,
+			CheckInFrom:   "14:00",
+			CheckOutUntil: "11:00",
+		},
+		TmplBookingExtraButton:     sampleExtra,
+		TmplBookingBathhouseButton: sampleCreated.Bathhouse[0],
+		TmplBookingSummary: entities.BookingSummary{
+			HouseName:   sampleCreated.HouseName,
+			CheckIn:     sampleCheckIn,
+			CheckOut:    sampleCheckOut,
+			GuestsCount: 4,
+			StayPrice:   sampleCreated.TotalPrice,
+			Discount:    sampleCreated.Discount,
+			Extras:      []entities.Extra{sampleExtra},
+			Bathhouse:   sampleCreated.Bathhouse,
+		},
+		TmplBookingCreated: entities.Reservation{
+			UUID:        sampleCreated.UUID,
+			CheckIn:     sampleCheckIn,
+			CheckOut:    sampleCheckOut,
+			GuestsCount: 4,
+			Status:      reservationConfirmed,
+			TotalPrice:  sampleCreated.TotalPrice,
+		},
+		TmplAdminBlocked: entities.Blackout{
+			HouseID:   1,
+			HouseName: sampleCreated.HouseName,
+			CheckIn:   sampleCheckIn,
+			CheckOut:  sampleCheckOut,
+			Reason:    "Ремонт",
+		},
+		TmplLoyaltyStatus: entities.LoyaltyStatus{
+			LoyaltyStats: entities.LoyaltyStats{Stays: 2, Spend: 48000},
+			Tier:         &entities.LoyaltyTier{Name: "Silver", MinStays: 2, Discount: 5},
+			Next:         &entities.LoyaltyTier{Name: "Gold", MinStays: 4, MinSpend: 100000, Discount: 10},
+			StaysToNext:  2,
+			SpendToNext:  52000,
+		},
+		TmplSMSCreatedAdmin:     sampleCreated,
+		TmplSMSCreatedUser:      sampleCreated,
+		TmplSMSCancelledAdmin:   sampleCancelled,
+		TmplSMSCancelledUser:    sampleCancelled,
+		TmplSMSReminder:         sampleReminder,
+		TmplSMSReminderCheckOut: sampleReminder,
+		TmplSMSEventApplication: sampleApplication,
+	}
+)
@@ -0,0 +1,80 @@
+package usecases
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
+	"github.com/calyrexx/QuietGrooveBackend/internal/repository"
+)
+
+// fakeTemplatesRepo is the message_templates table shared by every instance.
+type fakeTemplatesRepo struct {
+	repository.ITemplates
+	mu        sync.Mutex
+	templates map[string]entities.MessageTemplate
+}
+
+func (r *fakeTemplatesRepo) GetAll(context.Context) ([]entities.MessageTemplate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	res := make([]entities.MessageTemplate, 0, len(r.templates))
+	for _, tmpl := range r.templates {
+		res = append(res, tmpl)
+	}
+	return res, nil
+}
+
+func (r *fakeTemplatesRepo) Upsert(_ context.Context, tmpl entities.MessageTemplate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.templates[templateID(tmpl.Key, tmpl.Locale)] = tmpl
+	return nil
+}
+
+func TestOverridesFromAnotherInstanceAreReloaded(t *testing.T) {
+	repo := &fakeTemplatesRepo{templates: make(map[string]entities.MessageTemplate)}
+	defaults := fstest.MapFS{"ru/start.tmpl": {Data: []byte("Привет")}}
+
+	newInstance := func() *Templates {
+		tmpl, err := NewTemplates(&TemplatesDependencies{
+			Repo:           repo,
+			Defaults:       defaults,
+			DefaultLocale:  "ru",
+			ReloadInterval: 50 * time.Millisecond,
+			Logger:         discardLogger(),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tmpl
+	}
+	editor, reader := newInstance(), newInstance()
+	ctx := context.Background()
+
+	render := func() string {
+		text, err := reader.Render(ctx, TmplStart, "ru", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return text
+	}
+
+	if text := render(); text != "Привет" {
+		t.Fatalf("before update = %q", text)
+	}
+	if err := editor.Update(ctx, TmplStart, "ru", "Здравствуйте"); err != nil {
+		t.Fatal(err)
+	}
+	if text := render(); text != "Привет" {
+		t.Fatalf("cached overrides were reloaded early: %q", text)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if text := render(); text != "Здравствуйте" {
+		t.Errorf("after reload = %q, want the override", text)
+	}
+}
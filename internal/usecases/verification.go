@@ -3,93 +3,299 @@ package usecases
 import (
 	"context"
 	"crypto/rand"
-	"fmt"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"github.com/calyrexx/QuietGrooveBackend/internal/configuration"
 	"github.com/calyrexx/QuietGrooveBackend/internal/entities"
 	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/errorspkg"
+	"github.com/calyrexx/QuietGrooveBackend/internal/pkg/phonepkg"
 	"github.com/calyrexx/QuietGrooveBackend/internal/repository"
-	"math/big"
+	"github.com/calyrexx/zeroslog"
+	"log/slog"
+	"strings"
 	"time"
 )
 
+// tokenBytes gives a 43 character token, Telegram allows up to 64 in a start payload.
+const tokenBytes = 32
+
+type VerificationNotifier interface {
+	SuspiciousVerification(msg entities.SuspiciousVerification) error
+}
+
+// VerificationLinker builds the bot link that passes payload to /start.
+type VerificationLinker interface {
+	StartLink(payload string) string
+}
+
 type VerificationDependencies struct {
-	Repo       repository.IVerification
-	GuestsRepo repository.IGuests
-	TTL        time.Duration
+	Repo     repository.IVerification
+	Notifier VerificationNotifier
+	Linker   VerificationLinker
+	Config   *configuration.Verification
+	Logger   *slog.Logger
 }
 
 type Verification struct {
-	repo       repository.IVerification
-	guestsRepo repository.IGuests
-	ttl        time.Duration
+	repo     repository.IVerification
+	notifier VerificationNotifier
+	linker   VerificationLinker
+	config   *configuration.Verification
+	logger   *slog.Logger
 }
 
 func NewVerification(d *VerificationDependencies) (*Verification, error) {
 	if d.Repo == nil {
 		return nil, errorspkg.NewErrConstructorDependencies("NewVerification", "Repo", "nil")
 	}
-	if d.GuestsRepo == nil {
-		return nil, errorspkg.NewErrConstructorDependencies("NewVerification", "GuestsRepo", "nil")
+	if d.Notifier == nil {
+		return nil, errorspkg.NewErrConstructorDependencies("NewVerification", "Notifier", "nil")
 	}
-	if d.TTL == 0 {
+	if d.Linker == nil {
+		return nil, errorspkg.NewErrConstructorDependencies("NewVerification", "Linker", "nil")
+	}
+	if d.Config == nil {
+		return nil, errorspkg.NewErrConstructorDependencies("NewVerification", "Config", "nil")
+	}
+	if d.Config.TTL == 0 {
 		return nil, errorspkg.NewErrConstructorDependencies("NewVerification", "TTL", "0")
 	}
+	if d.Config.MaxChatAttempts <= 0 || d.Config.MaxLinkAttempts <= 0 {
+		return nil, errorspkg.NewErrConstructorDependencies("NewVerification", "MaxAttempts", "not positive")
+	}
+	if d.Config.Retention <= 0 {
+		return nil, errorspkg.NewErrConstructorDependencies("NewVerification", "Retention", "not positive")
+	}
 
 	return &Verification{
-		repo:       d.Repo,
-		guestsRepo: d.GuestsRepo,
-		ttl:        d.TTL,
+		repo:     d.Repo,
+		notifier: d.Notifier,
+		linker:   d.Linker,
+		config:   d.Config,
+		logger:   d.Logger.With(zeroslog.UsecaseKey, "Verification"),
 	}, nil
 }
 
-func (s *Verification) Generate(ctx context.Context, email, phone, name string) (string, error) {
-	code := sixDigits()
-	exp := time.Now().Add(s.ttl)
+// Generate starts a verification and returns the bot link that approves it.
+func (s *Verification) Generate(
+	ctx context.Context,
+	email, phone, name string,
+	channels []entities.NotificationChannel,
+) (entities.VerificationLink, error) {
+	for _, ch := range channels {
+		switch ch {
+		case entities.ChannelTelegram, entities.ChannelEmail, entities.ChannelSMS:
+		default:
+			return entities.VerificationLink{}, errorspkg.ErrUnknownNotifyChannel
+		}
+	}
+
+	phone, err := phonepkg.Normalize(phone)
+	if err != nil {
+		return entities.VerificationLink{}, err
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return entities.VerificationLink{}, err
+	}
+	exp := time.Now().Add(s.config.TTL)
 
-	err := s.repo.Create(ctx, entities.Verification{
-		Code:      code,
-		Email:     email,
+	id, err := s.repo.Create(ctx, entities.Verification{
+		TokenHash: hashToken(token),
+		Email:     phonepkg.NormalizeEmail(email),
 		Phone:     phone,
-		Name:      name,
+		Name:      strings.TrimSpace(name),
 		Status:    entities.VerifPending,
 		ExpiresAt: exp,
+
+		NotifyChannels: channels,
 	})
+	if err != nil {
+		return entities.VerificationLink{}, err
+	}
 
-	return code, err
+	return entities.VerificationLink{
+		ID:        id,
+		Link:      s.linker.StartLink(token),
+		ExpiresAt: exp,
+	}, nil
+}
+
+// Status reports a pending verification past its deadline as expired.
+func (s *Verification) Status(ctx context.Context, id string) (entities.Verification, error) {
+	v, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return v, err
+	}
+
+	if v.Status == entities.VerifPending && time.Now().After(v.ExpiresAt) {
+		v.Status = entities.VerifExpired
+	}
+	return v, nil
 }
 
-func (s *Verification) Approve(ctx context.Context, code string, tgID int64) error {
-	v, err := s.repo.GetByCode(ctx, code)
+// Approve binds the Telegram chat to the pending verification of this token and creates
+// the guest from it. Unknown tokens count towards the chat's lockout; while locked out
+// the chat gets ErrVerificationLocked even for a valid token. A token whose phone or chat
+// is already bound to another guest is left pending with ErrGuestConflict and reported to
+// the admins, who can merge the guests.
+func (s *Verification) Approve(ctx context.Context, token string, tgID int64, locale string) error {
+	now := time.Now()
+
+	lockout, err := s.repo.GetLockout(ctx, tgID)
 	if err != nil {
 		return err
 	}
+	if lockout.LockedUntil != nil && now.Before(*lockout.LockedUntil) {
+		s.lockedAttempt(ctx, token, tgID)
+		return errorspkg.NewErrVerificationLocked(*lockout.LockedUntil)
+	}
 
-	if v.Status != entities.VerifPending || time.Now().After(v.ExpiresAt) {
-		return errorspkg.ErrInvalidVerificationCode
+	v, err := s.repo.Approve(ctx, hashToken(token), tgID, locale)
+	if err != nil {
+		var notFound *errorspkg.ErrRepoNotFound
+		switch {
+		case errors.As(err, &notFound):
+			return s.fail(ctx, tgID, now)
+		case errors.Is(err, errorspkg.ErrGuestConflict):
+			s.report(entities.SuspiciousVerification{
+				Reason: entities.SuspiciousGuestConflict,
+				TgID:   tgID,
+				Name:   v.Name,
+				Phone:  v.Phone,
+			})
+		}
+		return err
 	}
 
-	err = s.repo.Approve(ctx, v.ID, tgID)
+	if err = s.repo.ResetLockout(ctx, tgID); err != nil {
+		s.logger.Error("reset verification lockout", zeroslog.ErrorKey, err, "tgID", tgID)
+	}
+
+	return nil
+}
+
+// Cleanup expires overdue verifications, deletes finished ones older than Retention and
+// logs how many guests of the last FunnelWindow stopped before confirming in Telegram.
+func (s *Verification) Cleanup(ctx context.Context) error {
+	now := time.Now()
+
+	expired, err := s.repo.ExpireOverdue(ctx)
 	if err != nil {
 		return err
 	}
 
-	err = s.guestsRepo.Create(ctx, entities.Guest{
-		Name:  v.Name,
-		Email: v.Email,
-		Phone: v.Phone,
-		TgID:  tgID,
-	})
+	deleted, err := s.repo.DeleteFinishedBefore(ctx, now.Add(-s.config.Retention))
 	if err != nil {
 		return err
 	}
 
+	funnel, err := s.repo.Funnel(ctx, now.Add(-s.config.FunnelWindow))
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("verification cleanup",
+		"expired", expired,
+		"deleted", deleted,
+		"window", s.config.FunnelWindow.String(),
+		"created", funnel.Created,
+		"pending", funnel.Pending,
+		"approved", funnel.Approved,
+		"abandoned", funnel.Expired,
+	)
+
 	return nil
 }
 
-func sixDigits() string {
-	n, err := rand.Int(rand.Reader, big.NewInt(900_000))
+// fail counts a wrong token and locks the chat out once it reaches MaxChatAttempts.
+func (s *Verification) fail(ctx context.Context, tgID int64, now time.Time) error {
+	lockout, err := s.repo.AddFailure(ctx, tgID, now.Add(-s.config.Window))
+	if err != nil {
+		return err
+	}
+	if lockout.Failures < s.config.MaxChatAttempts {
+		return errorspkg.ErrInvalidVerificationCode
+	}
+
+	until := now.Add(s.lockoutDuration(lockout.Lockouts))
+	if err = s.repo.Lock(ctx, tgID, until); err != nil {
+		return err
+	}
+
+	s.report(entities.SuspiciousVerification{
+		Reason:      entities.SuspiciousChatLocked,
+		TgID:        tgID,
+		Lockouts:    lockout.Lockouts + 1,
+		LockedUntil: until,
+	})
+
+	return errorspkg.NewErrVerificationLocked(until)
+}
+
+// lockedAttempt counts a token sent by a locked out chat against the token itself: a pending
+// link that keeps coming from such chats has likely leaked, so it gets revoked.
+func (s *Verification) lockedAttempt(ctx context.Context, token string, tgID int64) {
+	v, err := s.repo.GetPendingByToken(ctx, hashToken(token))
+	if err != nil {
+		var notFound *errorspkg.ErrRepoNotFound
+		if !errors.As(err, &notFound) {
+			s.logger.Error("get verification", zeroslog.ErrorKey, err)
+		}
+		return
+	}
+
+	attempts, err := s.repo.AddAttempt(ctx, v.ID)
 	if err != nil {
-		return ""
+		s.logger.Error("count verification attempt", zeroslog.ErrorKey, err)
+		return
+	}
+	if attempts < s.config.MaxLinkAttempts {
+		return
+	}
+
+	if err = s.repo.Expire(ctx, v.ID); err != nil {
+		s.logger.Error("revoke verification", zeroslog.ErrorKey, err)
+		return
+	}
+
+	s.report(entities.SuspiciousVerification{
+		Reason: entities.SuspiciousLinkRevoked,
+		TgID:   tgID,
+		Name:   v.Name,
+		Phone:  v.Phone,
+	})
+}
+
+// lockoutDuration doubles Lockout for every previous lockout, capped at MaxLockout.
+func (s *Verification) lockoutDuration(previous int) time.Duration {
+	d := s.config.Lockout
+	for i := 0; i < previous && d < s.config.MaxLockout; i++ {
+		d *= 2
 	}
-	code := n.Int64() + 100_000
-	return fmt.Sprintf("%06d", code)
+	return min(d, s.config.MaxLockout)
+}
+
+func (s *Verification) report(msg entities.SuspiciousVerification) {
+	s.logger.Warn("suspicious verification activity", "reason", msg.Reason, "tgID", msg.TgID)
+
+	if err := s.notifier.SuspiciousVerification(msg); err != nil {
+		s.logger.Error("notify admins", zeroslog.ErrorKey, err)
+	}
+}
+
+func newToken() (string, error) {
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }